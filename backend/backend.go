@@ -16,6 +16,12 @@
 package backend
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
 	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/logger"
 	"github.com/rs/zerolog"
@@ -47,6 +53,10 @@ func (id ID) String() string {
 	}
 }
 
+// ErrCircuitMismatch is returned by the provers when the proving key was not generated
+// from the given constraint system (their circuit digests differ)
+var ErrCircuitMismatch = errors.New("proving key doesn't match the constraint system")
+
 // ProverOption defines option for altering the behaviour of the prover in
 // Prove, ReadAndProve and IsSolved methods. See the descriptions of functions
 // returning instances of this type for implemented options.
@@ -57,13 +67,17 @@ type ProverConfig struct {
 	Force         bool                      // defaults to false
 	HintFunctions map[hint.ID]hint.Function // defaults to all built-in hint functions
 	CircuitLogger zerolog.Logger            // defaults to gnark.Logger
+	Ctx           context.Context           // checked between the prover phases, defaults to context.Background()
+	Progress      ProgressFunc              // called at the start of each prover phase, defaults to nil
+	RandomSource  io.Reader                 // source of the blinding factors, defaults to nil (crypto/rand)
+	NbTasks       int                       // maximum number of concurrent tasks, defaults to runtime.NumCPU()
 }
 
 // NewProverConfig returns a default ProverConfig with given prover options opts
 // applied.
 func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
 	log := logger.Logger()
-	opt := ProverConfig{CircuitLogger: log, HintFunctions: make(map[hint.ID]hint.Function)}
+	opt := ProverConfig{CircuitLogger: log, HintFunctions: make(map[hint.ID]hint.Function), Ctx: context.Background(), NbTasks: runtime.NumCPU()}
 	for _, v := range hint.GetRegistered() {
 		opt.HintFunctions[hint.UUID(v)] = v
 	}
@@ -114,3 +128,159 @@ func WithCircuitLogger(l zerolog.Logger) ProverOption {
 		return nil
 	}
 }
+
+// WithContext is a prover option that specifies a context to cancel the Prove algorithm.
+// The context is checked between the solver, FFT, MSM and quotient phases; if it is done,
+// the prover returns ctx.Err().
+func WithContext(ctx context.Context) ProverOption {
+	return func(opt *ProverConfig) error {
+		opt.Ctx = ctx
+		return nil
+	}
+}
+
+// WithProgress is a prover option that specifies a function called at the start of each
+// phase of the Prove algorithm (see ProgressFunc).
+func WithProgress(f ProgressFunc) ProverOption {
+	return func(opt *ProverConfig) error {
+		opt.Progress = f
+		return nil
+	}
+}
+
+// WithRandomSource is a prover option that specifies the source of the random blinding
+// factors of the proof. With the same source, Prove outputs the same proof, which is useful
+// for golden-file tests and to reproduce a proof on another machine.
+//
+// WARNING: a proof generated from a predictable source leaks the witness. By default, the
+// prover uses crypto/rand; this option must not be used in production.
+func WithRandomSource(r io.Reader) ProverOption {
+	return func(opt *ProverConfig) error {
+		log := logger.Logger()
+		log.Warn().Msg("prover uses a caller-provided random source: proofs are NOT zero-knowledge unless it is cryptographically secure")
+		opt.RandomSource = r
+		return nil
+	}
+}
+
+// WithNbTasks is a prover option that limits the number of tasks the solver and the prover
+// run concurrently (solver levels, FFTs, multi exponentiations). It defaults to runtime.NumCPU().
+//
+// Below runtime.NumCPU(), the independent parts of the proof run one after the other, each using
+// nbTasks go routines. The multi exponentiations are split in nbTasks parts by gnark-crypto
+// (ecc.MultiExpConfig.NbTasks), which may still process their windows in more go routines;
+// the KZG openings of the PlonK prover are not bounded.
+func WithNbTasks(nbTasks int) ProverOption {
+	return func(opt *ProverConfig) error {
+		if nbTasks < 1 {
+			return fmt.Errorf("invalid number of tasks %d", nbTasks)
+		}
+		opt.NbTasks = nbTasks
+		return nil
+	}
+}
+
+// Checkpoint is called by the provers at the start of a phase. It returns the context error
+// if the context is done, and reports the phase to the progress function otherwise.
+func (cfg *ProverConfig) Checkpoint(phase string, percent int) error {
+	return checkpoint(cfg.Ctx, cfg.Progress, phase, percent)
+}
+
+// ProgressFunc is called by the provers and setups with the name of the phase being started
+// and an estimate of the percentage of the work done so far (0 to 100). The last call reports
+// phase "done" at 100%.
+type ProgressFunc func(phase string, percent int)
+
+// PhaseDone is the phase reported when Setup or Prove completes
+const PhaseDone = "done"
+
+// SetupOption defines option for altering the behaviour of the Setup methods.
+// See the descriptions of functions returning instances of this type for
+// implemented options.
+type SetupOption func(*SetupConfig) error
+
+// SetupConfig is the configuration for the setup with the options applied.
+type SetupConfig struct {
+	Ctx          context.Context // checked between the setup phases, defaults to context.Background()
+	Progress     ProgressFunc    // called at the start of each setup phase, defaults to nil
+	RandomSource io.Reader       // source of the toxic waste, defaults to nil (crypto/rand)
+	NbTasks      int             // maximum number of concurrent tasks, defaults to runtime.NumCPU()
+}
+
+// NewSetupConfig returns a default SetupConfig with given setup options opts
+// applied.
+func NewSetupConfig(opts ...SetupOption) (SetupConfig, error) {
+	opt := SetupConfig{Ctx: context.Background(), NbTasks: runtime.NumCPU()}
+	for _, option := range opts {
+		if err := option(&opt); err != nil {
+			return SetupConfig{}, err
+		}
+	}
+	return opt, nil
+}
+
+// WithSetupContext is a setup option that specifies a context to cancel the Setup algorithm.
+// The context is checked between the setup phases; if it is done, the setup returns ctx.Err().
+func WithSetupContext(ctx context.Context) SetupOption {
+	return func(opt *SetupConfig) error {
+		opt.Ctx = ctx
+		return nil
+	}
+}
+
+// WithSetupProgress is a setup option that specifies a function called at the start of each
+// phase of the Setup algorithm (see ProgressFunc).
+func WithSetupProgress(f ProgressFunc) SetupOption {
+	return func(opt *SetupConfig) error {
+		opt.Progress = f
+		return nil
+	}
+}
+
+// WithSetupRandomSource is a setup option that specifies the source of the secret values
+// sampled by the setup (the toxic waste of groth16). With the same source, Setup outputs
+// the same keys.
+//
+// WARNING: anyone who can reproduce the source can forge proofs for the resulting keys.
+// By default, the setup uses crypto/rand; this option must not be used in production.
+func WithSetupRandomSource(r io.Reader) SetupOption {
+	return func(opt *SetupConfig) error {
+		log := logger.Logger()
+		log.Warn().Msg("setup uses a caller-provided random source: keys are INSECURE unless it is cryptographically secure and discarded")
+		opt.RandomSource = r
+		return nil
+	}
+}
+
+// WithSetupNbTasks is a setup option that limits the number of tasks the Setup algorithm runs
+// concurrently (FFTs, scalar multiplications, KZG commitments). It defaults to runtime.NumCPU().
+//
+// The precomputations of the FFT domains (fft.NewDomain) are not bounded, and the KZG commitments
+// are split in nbTasks parts by gnark-crypto (see WithNbTasks).
+func WithSetupNbTasks(nbTasks int) SetupOption {
+	return func(opt *SetupConfig) error {
+		if nbTasks < 1 {
+			return fmt.Errorf("invalid number of tasks %d", nbTasks)
+		}
+		opt.NbTasks = nbTasks
+		return nil
+	}
+}
+
+// Checkpoint is called by the setups at the start of a phase. It returns the context error
+// if the context is done, and reports the phase to the progress function otherwise.
+func (cfg *SetupConfig) Checkpoint(phase string, percent int) error {
+	return checkpoint(cfg.Ctx, cfg.Progress, phase, percent)
+}
+
+func checkpoint(ctx context.Context, progress ProgressFunc, phase string, percent int) error {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if progress != nil {
+		progress(phase, percent)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package distributed implements a Groth16 prover whose multi-exponentiations and FFTs are
+// computed by worker processes, after DIZK (https://eprint.iacr.org/2018/691.pdf).
+//
+// The proving key is Split in a coordinator key and one KeyShard per worker, holding a contiguous
+// range of each multi-exponentiation basis. The coordinator solves the R1CS, sends each worker its
+// range of the scalars and sums the partial multi-exponentiations; the FFTs of the quotient
+// computation are decomposed in batches of smaller FFTs (four-step algorithm) computed by the workers.
+//
+// Workers are served with net/rpc (see Serve and Dial), or called in process (see NewWorker).
+// The proof is the one groth16.Prove returns with the same random source.
+package distributed
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	backend_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
+	backend_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/cs"
+	backend_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/cs"
+	backend_bn254 "github.com/consensys/gnark/internal/backend/bn254/cs"
+	backend_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/cs"
+	backend_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+
+	witness_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/witness"
+	witness_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/witness"
+	witness_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/witness"
+	witness_bn254 "github.com/consensys/gnark/internal/backend/bn254/witness"
+	witness_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/witness"
+	witness_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/witness"
+
+	groth16_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/groth16"
+	groth16_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/groth16"
+	groth16_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/groth16"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	groth16_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/groth16"
+	groth16_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/groth16"
+)
+
+// Client calls the methods of a worker; it is implemented by *rpc.Client and by the workers
+// returned by NewWorker.
+type Client interface {
+	Call(serviceMethod string, args interface{}, reply interface{}) error
+}
+
+// KeyShard is the part of a groth16.ProvingKey held by a worker
+type KeyShard interface {
+	io.WriterTo
+	io.ReaderFrom
+	CurveID() ecc.ID
+}
+
+// Split returns a copy of pk without the multi-exponentiation bases, for the coordinator,
+// and the nbShards KeyShard to be held by the workers; the i-th worker of Prove must hold
+// the i-th KeyShard.
+func Split(pk groth16.ProvingKey, nbShards int) (groth16.ProvingKey, []KeyShard, error) {
+	if nbShards < 1 {
+		return nil, nil, fmt.Errorf("invalid number of shards %d", nbShards)
+	}
+	var shards []KeyShard
+	switch _pk := pk.(type) {
+	case *groth16_bls12377.ProvingKey:
+		coordinator, _shards := _pk.Split(nbShards)
+		for _, s := range _shards {
+			shards = append(shards, s)
+		}
+		return coordinator, shards, nil
+	case *groth16_bls12381.ProvingKey:
+		coordinator, _shards := _pk.Split(nbShards)
+		for _, s := range _shards {
+			shards = append(shards, s)
+		}
+		return coordinator, shards, nil
+	case *groth16_bn254.ProvingKey:
+		coordinator, _shards := _pk.Split(nbShards)
+		for _, s := range _shards {
+			shards = append(shards, s)
+		}
+		return coordinator, shards, nil
+	case *groth16_bw6761.ProvingKey:
+		coordinator, _shards := _pk.Split(nbShards)
+		for _, s := range _shards {
+			shards = append(shards, s)
+		}
+		return coordinator, shards, nil
+	case *groth16_bls24315.ProvingKey:
+		coordinator, _shards := _pk.Split(nbShards)
+		for _, s := range _shards {
+			shards = append(shards, s)
+		}
+		return coordinator, shards, nil
+	case *groth16_bw6633.ProvingKey:
+		coordinator, _shards := _pk.Split(nbShards)
+		for _, s := range _shards {
+			shards = append(shards, s)
+		}
+		return coordinator, shards, nil
+	default:
+		panic("unrecognized ProvingKey type")
+	}
+}
+
+// NewKeyShard instantiates a curve-typed KeyShard, to be read with ReadFrom
+func NewKeyShard(curveID ecc.ID) KeyShard {
+	var shard KeyShard
+	switch curveID {
+	case ecc.BN254:
+		shard = &groth16_bn254.KeyShard{}
+	case ecc.BLS12_377:
+		shard = &groth16_bls12377.KeyShard{}
+	case ecc.BLS12_381:
+		shard = &groth16_bls12381.KeyShard{}
+	case ecc.BW6_761:
+		shard = &groth16_bw6761.KeyShard{}
+	case ecc.BLS24_315:
+		shard = &groth16_bls24315.KeyShard{}
+	case ecc.BW6_633:
+		shard = &groth16_bw6633.KeyShard{}
+	default:
+		panic("not implemented")
+	}
+	return shard
+}
+
+// NewWorker returns an in-process worker holding shard. nbTasks bounds the number of tasks
+// of each request (see backend.WithNbTasks), 0 meaning unbounded.
+func NewWorker(shard KeyShard, nbTasks int) (Client, error) {
+	switch _shard := shard.(type) {
+	case *groth16_bls12377.KeyShard:
+		return groth16_bls12377.NewWorker(_shard, nbTasks), nil
+	case *groth16_bls12381.KeyShard:
+		return groth16_bls12381.NewWorker(_shard, nbTasks), nil
+	case *groth16_bn254.KeyShard:
+		return groth16_bn254.NewWorker(_shard, nbTasks), nil
+	case *groth16_bw6761.KeyShard:
+		return groth16_bw6761.NewWorker(_shard, nbTasks), nil
+	case *groth16_bls24315.KeyShard:
+		return groth16_bls24315.NewWorker(_shard, nbTasks), nil
+	case *groth16_bw6633.KeyShard:
+		return groth16_bw6633.NewWorker(_shard, nbTasks), nil
+	default:
+		return nil, fmt.Errorf("unsupported key shard type %T", shard)
+	}
+}
+
+// Serve serves a worker holding shard with net/rpc on the connections accepted by l,
+// until l is closed.
+func Serve(l net.Listener, shard KeyShard, nbTasks int) error {
+	worker, err := NewWorker(shard, nbTasks)
+	if err != nil {
+		return err
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName(groth16_bn254.WorkerService, worker); err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go server.ServeConn(conn)
+	}
+}
+
+// Dial connects to a worker served with Serve
+func Dial(network, address string) (Client, error) {
+	return rpc.Dial(network, address)
+}
+
+// Prove generates the proof of knowledge of a r1cs with full witness (secret + public part),
+// with the multi-exponentiations and the FFTs computed by the workers.
+//
+// pk is the proving key or the coordinator key returned by Split, and workers[i] holds the
+// i-th KeyShard of Split(pk, len(workers)).
+func Prove(r1cs frontend.CompiledConstraintSystem, pk groth16.ProvingKey, fullWitness *witness.Witness, workers []Client, opts ...backend.ProverOption) (groth16.Proof, error) {
+
+	// apply options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls12377.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bls12377.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		clients := make([]groth16_bls12377.WorkerClient, len(workers))
+		for i := range workers {
+			clients[i] = workers[i]
+		}
+		return groth16_bls12377.ProveDistributed(_r1cs, pk.(*groth16_bls12377.ProvingKey), *w, clients, opt)
+	case *backend_bls12381.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bls12381.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		clients := make([]groth16_bls12381.WorkerClient, len(workers))
+		for i := range workers {
+			clients[i] = workers[i]
+		}
+		return groth16_bls12381.ProveDistributed(_r1cs, pk.(*groth16_bls12381.ProvingKey), *w, clients, opt)
+	case *backend_bn254.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bn254.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		clients := make([]groth16_bn254.WorkerClient, len(workers))
+		for i := range workers {
+			clients[i] = workers[i]
+		}
+		return groth16_bn254.ProveDistributed(_r1cs, pk.(*groth16_bn254.ProvingKey), *w, clients, opt)
+	case *backend_bw6761.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bw6761.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		clients := make([]groth16_bw6761.WorkerClient, len(workers))
+		for i := range workers {
+			clients[i] = workers[i]
+		}
+		return groth16_bw6761.ProveDistributed(_r1cs, pk.(*groth16_bw6761.ProvingKey), *w, clients, opt)
+	case *backend_bls24315.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bls24315.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		clients := make([]groth16_bls24315.WorkerClient, len(workers))
+		for i := range workers {
+			clients[i] = workers[i]
+		}
+		return groth16_bls24315.ProveDistributed(_r1cs, pk.(*groth16_bls24315.ProvingKey), *w, clients, opt)
+	case *backend_bw6633.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bw6633.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		clients := make([]groth16_bw6633.WorkerClient, len(workers))
+		for i := range workers {
+			clients[i] = workers[i]
+		}
+		return groth16_bw6633.ProveDistributed(_r1cs, pk.(*groth16_bw6633.ProvingKey), *w, clients, opt)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
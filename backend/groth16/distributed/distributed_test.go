@@ -0,0 +1,83 @@
+package distributed
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type powerCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *powerCircuit) Define(api frontend.API) error {
+	res := c.X
+	for i := 0; i < 100; i++ {
+		res = api.Mul(res, c.X)
+	}
+	api.AssertIsEqual(res, c.Y)
+	return nil
+}
+
+func TestProveLoopback(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &powerCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	// y = 2^101
+	y := new(big.Int).Lsh(big.NewInt(1), 101)
+	w, err := frontend.NewWitness(&powerCircuit{X: 2, Y: y}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+
+	expected, err := groth16.Prove(ccs, pk, w, backend.WithRandomSource(rand.New(rand.NewSource(42))))
+	assert.NoError(err)
+
+	const nbWorkers = 3
+	coordinator, shards, err := Split(pk, nbWorkers)
+	assert.NoError(err)
+
+	workers := make([]Client, nbWorkers)
+	for i, shard := range shards {
+		// the workers read their shard from a file in practice
+		var buf bytes.Buffer
+		_, err := shard.WriteTo(&buf)
+		assert.NoError(err)
+		read := NewKeyShard(ecc.BN254)
+		_, err = read.ReadFrom(&buf)
+		assert.NoError(err)
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(err)
+		defer l.Close()
+		go func() {
+			_ = Serve(l, read, 1)
+		}()
+		workers[i], err = Dial("tcp", l.Addr().String())
+		assert.NoError(err)
+	}
+
+	proof, err := Prove(ccs, coordinator, w, workers, backend.WithRandomSource(rand.New(rand.NewSource(42))))
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+	assert.True(reflect.DeepEqual(expected, proof), "distributed proof differs from the local proof")
+
+	// the workers must hold the shards of Split(pk, len(workers))
+	_, err = Prove(ccs, coordinator, w, workers[:2])
+	assert.Error(err)
+}
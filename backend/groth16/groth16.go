@@ -20,6 +20,7 @@
 package groth16
 
 import (
+	"errors"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -106,6 +107,8 @@ type VerifyingKey interface {
 }
 
 // Verify runs the groth16.Verify algorithm on provided proof with given witness
+//
+// If vk was returned by Prepare, the pairings use its precomputations.
 func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error {
 
 	switch _proof := proof.(type) {
@@ -114,42 +117,108 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*groth16_bls12377.PreparedVerifyingKey); ok {
+			return groth16_bls12377.VerifyPrepared(_proof, pvk, *w)
+		}
 		return groth16_bls12377.Verify(_proof, vk.(*groth16_bls12377.VerifyingKey), *w)
 	case *groth16_bls12381.Proof:
 		w, ok := publicWitness.Vector.(*witness_bls12381.Witness)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*groth16_bls12381.PreparedVerifyingKey); ok {
+			return groth16_bls12381.VerifyPrepared(_proof, pvk, *w)
+		}
 		return groth16_bls12381.Verify(_proof, vk.(*groth16_bls12381.VerifyingKey), *w)
 	case *groth16_bn254.Proof:
 		w, ok := publicWitness.Vector.(*witness_bn254.Witness)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*groth16_bn254.PreparedVerifyingKey); ok {
+			return groth16_bn254.VerifyPrepared(_proof, pvk, *w)
+		}
 		return groth16_bn254.Verify(_proof, vk.(*groth16_bn254.VerifyingKey), *w)
 	case *groth16_bw6761.Proof:
 		w, ok := publicWitness.Vector.(*witness_bw6761.Witness)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*groth16_bw6761.PreparedVerifyingKey); ok {
+			return groth16_bw6761.VerifyPrepared(_proof, pvk, *w)
+		}
 		return groth16_bw6761.Verify(_proof, vk.(*groth16_bw6761.VerifyingKey), *w)
 	case *groth16_bls24315.Proof:
 		w, ok := publicWitness.Vector.(*witness_bls24315.Witness)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*groth16_bls24315.PreparedVerifyingKey); ok {
+			return groth16_bls24315.VerifyPrepared(_proof, pvk, *w)
+		}
 		return groth16_bls24315.Verify(_proof, vk.(*groth16_bls24315.VerifyingKey), *w)
 	case *groth16_bw6633.Proof:
 		w, ok := publicWitness.Vector.(*witness_bw6633.Witness)
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*groth16_bw6633.PreparedVerifyingKey); ok {
+			return groth16_bw6633.VerifyPrepared(_proof, pvk, *w)
+		}
 		return groth16_bw6633.Verify(_proof, vk.(*groth16_bw6633.VerifyingKey), *w)
 	default:
 		panic("unrecognized R1CS curve type")
 	}
 }
 
+// Rerandomize returns a fresh proof of the same statement as proof, that verifies with vk.
+//
+// Groth16 proofs are malleable: anyone holding vk can derive from a valid proof a new valid proof
+// that can't be linked to the original. backend.WithRandomSource may provide the randomness.
+func Rerandomize(proof Proof, vk VerifyingKey, opts ...backend.ProverOption) (Proof, error) {
+
+	// apply options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch _proof := proof.(type) {
+	case *groth16_bls12377.Proof:
+		if pvk, ok := vk.(*groth16_bls12377.PreparedVerifyingKey); ok {
+			return groth16_bls12377.Rerandomize(_proof, &pvk.VerifyingKey, opt)
+		}
+		return groth16_bls12377.Rerandomize(_proof, vk.(*groth16_bls12377.VerifyingKey), opt)
+	case *groth16_bls12381.Proof:
+		if pvk, ok := vk.(*groth16_bls12381.PreparedVerifyingKey); ok {
+			return groth16_bls12381.Rerandomize(_proof, &pvk.VerifyingKey, opt)
+		}
+		return groth16_bls12381.Rerandomize(_proof, vk.(*groth16_bls12381.VerifyingKey), opt)
+	case *groth16_bn254.Proof:
+		if pvk, ok := vk.(*groth16_bn254.PreparedVerifyingKey); ok {
+			return groth16_bn254.Rerandomize(_proof, &pvk.VerifyingKey, opt)
+		}
+		return groth16_bn254.Rerandomize(_proof, vk.(*groth16_bn254.VerifyingKey), opt)
+	case *groth16_bw6761.Proof:
+		if pvk, ok := vk.(*groth16_bw6761.PreparedVerifyingKey); ok {
+			return groth16_bw6761.Rerandomize(_proof, &pvk.VerifyingKey, opt)
+		}
+		return groth16_bw6761.Rerandomize(_proof, vk.(*groth16_bw6761.VerifyingKey), opt)
+	case *groth16_bls24315.Proof:
+		if pvk, ok := vk.(*groth16_bls24315.PreparedVerifyingKey); ok {
+			return groth16_bls24315.Rerandomize(_proof, &pvk.VerifyingKey, opt)
+		}
+		return groth16_bls24315.Rerandomize(_proof, vk.(*groth16_bls24315.VerifyingKey), opt)
+	case *groth16_bw6633.Proof:
+		if pvk, ok := vk.(*groth16_bw6633.PreparedVerifyingKey); ok {
+			return groth16_bw6633.Rerandomize(_proof, &pvk.VerifyingKey, opt)
+		}
+		return groth16_bw6633.Rerandomize(_proof, vk.(*groth16_bw6633.VerifyingKey), opt)
+	default:
+		panic("unrecognized Proof curve type")
+	}
+}
+
 // Prove runs the groth16.Prove algorithm.
 //
 // if the force flag is set:
@@ -206,6 +275,125 @@ func Prove(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, fullWitness *w
 	}
 }
 
+// Solution represents a solved witness, produced by groth16.Solve and consumed by groth16.ProveFromSolution
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type Solution interface {
+	io.WriterTo
+	io.ReaderFrom
+	CurveID() ecc.ID
+}
+
+// Solve solves the constraint system with the full witness and returns a Solution, that
+// can be serialized and proven (possibly on another machine) with ProveFromSolution.
+//
+// if the force flag is set, the solution is filled with random values if the witness is invalid
+func Solve(r1cs frontend.CompiledConstraintSystem, fullWitness *witness.Witness, opts ...backend.ProverOption) (Solution, error) {
+
+	// apply options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls12377.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bls12377.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return groth16_bls12377.Solve(_r1cs, *w, opt)
+	case *backend_bls12381.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bls12381.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return groth16_bls12381.Solve(_r1cs, *w, opt)
+	case *backend_bn254.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bn254.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return groth16_bn254.Solve(_r1cs, *w, opt)
+	case *backend_bw6761.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bw6761.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return groth16_bw6761.Solve(_r1cs, *w, opt)
+	case *backend_bls24315.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bls24315.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return groth16_bls24315.Solve(_r1cs, *w, opt)
+	case *backend_bw6633.R1CS:
+		w, ok := fullWitness.Vector.(*witness_bw6633.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return groth16_bw6633.Solve(_r1cs, *w, opt)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// ProveFromSolution runs the groth16.Prove algorithm from a Solution returned by Solve, skipping the solver.
+//
+// It returns an error wrapping backend.ErrCircuitMismatch if the solution or the proving key
+// don't belong to the constraint system.
+func ProveFromSolution(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, solution Solution, opts ...backend.ProverOption) (Proof, error) {
+
+	// apply options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls12377.R1CS:
+		s, ok := solution.(*groth16_bls12377.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return groth16_bls12377.ProveFromSolution(_r1cs, pk.(*groth16_bls12377.ProvingKey), s, opt)
+	case *backend_bls12381.R1CS:
+		s, ok := solution.(*groth16_bls12381.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return groth16_bls12381.ProveFromSolution(_r1cs, pk.(*groth16_bls12381.ProvingKey), s, opt)
+	case *backend_bn254.R1CS:
+		s, ok := solution.(*groth16_bn254.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return groth16_bn254.ProveFromSolution(_r1cs, pk.(*groth16_bn254.ProvingKey), s, opt)
+	case *backend_bw6761.R1CS:
+		s, ok := solution.(*groth16_bw6761.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return groth16_bw6761.ProveFromSolution(_r1cs, pk.(*groth16_bw6761.ProvingKey), s, opt)
+	case *backend_bls24315.R1CS:
+		s, ok := solution.(*groth16_bls24315.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return groth16_bls24315.ProveFromSolution(_r1cs, pk.(*groth16_bls24315.ProvingKey), s, opt)
+	case *backend_bw6633.R1CS:
+		s, ok := solution.(*groth16_bw6633.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return groth16_bw6633.ProveFromSolution(_r1cs, pk.(*groth16_bw6633.ProvingKey), s, opt)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+var errSolutionCurve = errors.New("solution and constraint system curves don't match")
+
 // Setup runs groth16.Setup with provided R1CS and outputs a key pair associated with the circuit.
 //
 // Note that careful consideration must be given to this step in production environment.
@@ -214,48 +402,54 @@ func Prove(r1cs frontend.CompiledConstraintSystem, pk ProvingKey, fullWitness *w
 //
 // Two main solutions to this deployment issues are: running the Setup through a MPC (multi party computation)
 // or using a ZKP backend like PLONK where the per-circuit Setup is deterministic.
-func Setup(r1cs frontend.CompiledConstraintSystem) (ProvingKey, VerifyingKey, error) {
+func Setup(r1cs frontend.CompiledConstraintSystem, opts ...backend.SetupOption) (ProvingKey, VerifyingKey, error) {
+
+	// apply options
+	opt, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	switch _r1cs := r1cs.(type) {
 	case *backend_bls12377.R1CS:
 		var pk groth16_bls12377.ProvingKey
 		var vk groth16_bls12377.VerifyingKey
-		if err := groth16_bls12377.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bls12377.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bls12381.R1CS:
 		var pk groth16_bls12381.ProvingKey
 		var vk groth16_bls12381.VerifyingKey
-		if err := groth16_bls12381.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bls12381.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bn254.R1CS:
 		var pk groth16_bn254.ProvingKey
 		var vk groth16_bn254.VerifyingKey
-		if err := groth16_bn254.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bn254.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bw6761.R1CS:
 		var pk groth16_bw6761.ProvingKey
 		var vk groth16_bw6761.VerifyingKey
-		if err := groth16_bw6761.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bw6761.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bls24315.R1CS:
 		var pk groth16_bls24315.ProvingKey
 		var vk groth16_bls24315.VerifyingKey
-		if err := groth16_bls24315.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bls24315.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
 	case *backend_bw6633.R1CS:
 		var pk groth16_bw6633.ProvingKey
 		var vk groth16_bw6633.VerifyingKey
-		if err := groth16_bw6633.Setup(_r1cs, &pk, &vk); err != nil {
+		if err := groth16_bw6633.Setup(_r1cs, &pk, &vk, opt); err != nil {
 			return nil, nil, err
 		}
 		return &pk, &vk, nil
@@ -356,6 +550,54 @@ func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
 	return vk
 }
 
+// Prepare returns a VerifyingKey with the precomputations of the pairings of Verify: e(α, β) and,
+// except on BW6 curves, the lines of the Miller loops of [-γ]2 and [-δ]2.
+//
+// Verify uses them through a fast path. They are serialized with the key; they are not checked
+// when the key is read through NewPreparedVerifyingKey, so it must come from a trusted source.
+func Prepare(vk VerifyingKey) VerifyingKey {
+	switch _vk := vk.(type) {
+	case *groth16_bls12377.VerifyingKey:
+		return groth16_bls12377.Prepare(_vk)
+	case *groth16_bls12381.VerifyingKey:
+		return groth16_bls12381.Prepare(_vk)
+	case *groth16_bn254.VerifyingKey:
+		return groth16_bn254.Prepare(_vk)
+	case *groth16_bw6761.VerifyingKey:
+		return groth16_bw6761.Prepare(_vk)
+	case *groth16_bls24315.VerifyingKey:
+		return groth16_bls24315.Prepare(_vk)
+	case *groth16_bw6633.VerifyingKey:
+		return groth16_bw6633.Prepare(_vk)
+	default:
+		panic("unrecognized VerifyingKey curve type")
+	}
+}
+
+// NewPreparedVerifyingKey instantiates a curve-typed VerifyingKey returned by Prepare and returns an interface
+// This function exists for serialization purposes
+func NewPreparedVerifyingKey(curveID ecc.ID) VerifyingKey {
+	var pvk VerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		pvk = &groth16_bn254.PreparedVerifyingKey{}
+	case ecc.BLS12_377:
+		pvk = &groth16_bls12377.PreparedVerifyingKey{}
+	case ecc.BLS12_381:
+		pvk = &groth16_bls12381.PreparedVerifyingKey{}
+	case ecc.BW6_761:
+		pvk = &groth16_bw6761.PreparedVerifyingKey{}
+	case ecc.BLS24_315:
+		pvk = &groth16_bls24315.PreparedVerifyingKey{}
+	case ecc.BW6_633:
+		pvk = &groth16_bw6633.PreparedVerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return pvk
+}
+
 // NewProof instantiates a curve-typed Proof and returns an interface
 // This function exists for serialization purposes
 func NewProof(curveID ecc.ID) Proof {
@@ -380,6 +622,30 @@ func NewProof(curveID ecc.ID) Proof {
 	return proof
 }
 
+// NewSolution instantiates a curve-typed Solution and returns an interface
+// This function exists for serialization purposes
+func NewSolution(curveID ecc.ID) Solution {
+	var solution Solution
+	switch curveID {
+	case ecc.BN254:
+		solution = &groth16_bn254.Solution{}
+	case ecc.BLS12_377:
+		solution = &groth16_bls12377.Solution{}
+	case ecc.BLS12_381:
+		solution = &groth16_bls12381.Solution{}
+	case ecc.BW6_761:
+		solution = &groth16_bw6761.Solution{}
+	case ecc.BLS24_315:
+		solution = &groth16_bls24315.Solution{}
+	case ecc.BW6_633:
+		solution = &groth16_bw6633.Solution{}
+	default:
+		panic("not implemented")
+	}
+
+	return solution
+}
+
 // NewCS instantiate a concrete curved-typed R1CS and return a R1CS interface
 // This method exists for (de)serialization purposes
 func NewCS(curveID ecc.ID) frontend.CompiledConstraintSystem {
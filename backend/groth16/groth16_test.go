@@ -0,0 +1,249 @@
+package groth16
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type digestCircuit struct {
+	constant int
+	X        frontend.Variable
+	Y        frontend.Variable `gnark:",public"`
+}
+
+func (c *digestCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.constant), c.Y)
+	return nil
+}
+
+func TestCircuitDigest(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &digestCircuit{constant: 3})
+	assert.NoError(err)
+	other, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &digestCircuit{constant: 5})
+	assert.NoError(err)
+	assert.NotEqual(ccs.Digest(), other.Digest())
+
+	pk, vk, err := Setup(ccs)
+	assert.NoError(err)
+
+	// the digest survives serialization
+	var buf bytes.Buffer
+	_, err = pk.WriteTo(&buf)
+	assert.NoError(err)
+	pk = NewProvingKey(ecc.BN254)
+	_, err = pk.ReadFrom(&buf)
+	assert.NoError(err)
+
+	buf.Reset()
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	vk = NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&buf)
+	assert.NoError(err)
+
+	w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+
+	proof, err := Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(Verify(proof, vk, publicWitness))
+
+	// a proving key generated for another circuit is rejected
+	_, err = Prove(other, pk, w)
+	assert.True(errors.Is(err, backend.ErrCircuitMismatch), "expected ErrCircuitMismatch, got %v", err)
+}
+
+func TestProveFromSolution(t *testing.T) {
+	for _, curve := range gnark.Curves() {
+		assert := require.New(t)
+
+		ccs, err := frontend.Compile(curve, r1cs.NewBuilder, &digestCircuit{constant: 3})
+		assert.NoError(err)
+		other, err := frontend.Compile(curve, r1cs.NewBuilder, &digestCircuit{constant: 5})
+		assert.NoError(err)
+
+		pk, vk, err := Setup(ccs)
+		assert.NoError(err)
+
+		w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, curve)
+		assert.NoError(err)
+		publicWitness, err := w.Public()
+		assert.NoError(err)
+
+		// solve, and prove from the serialized solution
+		solution, err := Solve(ccs, w)
+		assert.NoError(err)
+		var buf bytes.Buffer
+		_, err = solution.WriteTo(&buf)
+		assert.NoError(err)
+		solution = NewSolution(curve)
+		_, err = solution.ReadFrom(&buf)
+		assert.NoError(err, curve.String())
+
+		proof, err := ProveFromSolution(ccs, pk, solution)
+		assert.NoError(err, curve.String())
+		assert.NoError(Verify(proof, vk, publicWitness), curve.String())
+
+		// a solution of another circuit is rejected
+		otherSolution, err := Solve(other, w, backend.IgnoreSolverError())
+		assert.NoError(err)
+		_, err = ProveFromSolution(ccs, pk, otherSolution)
+		assert.True(errors.Is(err, backend.ErrCircuitMismatch), "expected ErrCircuitMismatch, got %v", err)
+	}
+}
+
+func TestCancelAndProgress(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &digestCircuit{constant: 3})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, ecc.BN254)
+	assert.NoError(err)
+
+	// progress is reported for each phase, up to 100%
+	var phases []string
+	var percents []int
+	progress := func(phase string, percent int) {
+		phases = append(phases, phase)
+		percents = append(percents, percent)
+	}
+	checkProgress := func() {
+		assert.Greater(len(phases), 2)
+		assert.Equal(backend.PhaseDone, phases[len(phases)-1])
+		assert.Equal(100, percents[len(percents)-1])
+		assert.IsIncreasing(percents)
+		phases, percents = nil, nil
+	}
+
+	pk, _, err := Setup(ccs, backend.WithSetupProgress(progress))
+	assert.NoError(err)
+	checkProgress()
+
+	_, err = Prove(ccs, pk, w, backend.WithProgress(progress))
+	assert.NoError(err)
+	assert.Equal("solve", phases[0])
+	checkProgress()
+
+	// cancelled jobs return the context error
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = Setup(ccs, backend.WithSetupContext(ctx))
+	assert.True(errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+
+	_, err = Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.True(errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+}
+
+func TestProver(t *testing.T) {
+	for _, curve := range gnark.Curves() {
+		assert := require.New(t)
+
+		ccs, err := frontend.Compile(curve, r1cs.NewBuilder, &digestCircuit{constant: 3})
+		assert.NoError(err)
+		pk, vk, err := Setup(ccs)
+		assert.NoError(err)
+
+		var witnesses, publicWitnesses []*witness.Witness
+		for _, x := range []int{2, 3} {
+			w, err := frontend.NewWitness(&digestCircuit{X: x, Y: 3 * x * x}, curve)
+			assert.NoError(err)
+			publicWitness, err := w.Public()
+			assert.NoError(err)
+			witnesses = append(witnesses, w)
+			publicWitnesses = append(publicWitnesses, publicWitness)
+		}
+
+		prover, err := NewProver(ccs, pk)
+		assert.NoError(err)
+		proof, err := prover.Prove(witnesses[0])
+		assert.NoError(err, curve.String())
+		assert.NoError(Verify(proof, vk, publicWitnesses[0]), curve.String())
+
+		var phases []string
+		proofs, err := prover.ProveBatch(witnesses, backend.WithProgress(func(phase string, percent int) {
+			phases = append(phases, phase)
+		}))
+		assert.NoError(err, curve.String())
+		assert.Equal([]string{"prove", "prove", backend.PhaseDone}, phases)
+		for i := range proofs {
+			assert.NoError(Verify(proofs[i], vk, publicWitnesses[i]), curve.String())
+		}
+	}
+}
+
+func TestRerandomize(t *testing.T) {
+	for _, curve := range gnark.Curves() {
+		assert := require.New(t)
+
+		ccs, err := frontend.Compile(curve, r1cs.NewBuilder, &digestCircuit{constant: 3})
+		assert.NoError(err)
+		pk, vk, err := Setup(ccs)
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, curve)
+		assert.NoError(err)
+		publicWitness, err := w.Public()
+		assert.NoError(err)
+
+		proof, err := Prove(ccs, pk, w)
+		assert.NoError(err)
+		rerandomized, err := Rerandomize(proof, vk)
+		assert.NoError(err, curve.String())
+		assert.NoError(Verify(rerandomized, vk, publicWitness), curve.String())
+
+		var buf, rerandomizedBuf bytes.Buffer
+		_, err = proof.WriteTo(&buf)
+		assert.NoError(err)
+		_, err = rerandomized.WriteTo(&rerandomizedBuf)
+		assert.NoError(err)
+		assert.NotEqual(buf.Bytes(), rerandomizedBuf.Bytes(), curve.String())
+	}
+}
+
+func TestPreparedVerifyingKey(t *testing.T) {
+	for _, curve := range gnark.Curves() {
+		assert := require.New(t)
+
+		ccs, err := frontend.Compile(curve, r1cs.NewBuilder, &digestCircuit{constant: 3})
+		assert.NoError(err)
+		pk, vk, err := Setup(ccs)
+		assert.NoError(err)
+		w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, curve)
+		assert.NoError(err)
+		publicWitness, err := w.Public()
+		assert.NoError(err)
+		proof, err := Prove(ccs, pk, w)
+		assert.NoError(err)
+
+		// the prepared key survives serialization
+		var buf bytes.Buffer
+		_, err = Prepare(vk).WriteTo(&buf)
+		assert.NoError(err)
+		pvk := NewPreparedVerifyingKey(curve)
+		_, err = pvk.ReadFrom(&buf)
+		assert.NoError(err)
+
+		assert.NoError(Verify(proof, pvk, publicWitness), curve.String())
+		rerandomized, err := Rerandomize(proof, pvk)
+		assert.NoError(err)
+		assert.NoError(Verify(rerandomized, pvk, publicWitness), curve.String())
+
+		wrong, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 13}, curve, frontend.PublicOnly())
+		assert.NoError(err)
+		assert.Error(Verify(proof, pvk, wrong), curve.String())
+	}
+}
@@ -20,12 +20,14 @@
 package plonk
 
 import (
+	"errors"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
+	gnarkio "github.com/consensys/gnark/io"
 
 	"github.com/consensys/gnark/backend/witness"
 	cs_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
@@ -68,9 +70,13 @@ type Proof interface {
 // ProvingKey represents a plonk ProvingKey
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
+//
+// It references the KZG SRS used by the prover, which is serialized with it.
 type ProvingKey interface {
 	io.WriterTo
 	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 	InitKZG(srs kzg.SRS) error
 	VerifyingKey() interface{}
 }
@@ -78,29 +84,40 @@ type ProvingKey interface {
 // VerifyingKey represents a plonk VerifyingKey
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
+//
+// It only keeps the points of the KZG SRS used by the verifier, [1]1, [1]2 and [α]2,
+// which are serialized with it.
 type VerifyingKey interface {
 	io.WriterTo
 	io.ReaderFrom
+	gnarkio.WriterRawTo
+	gnarkio.UnsafeReaderFrom
 	InitKZG(srs kzg.SRS) error
 	NbPublicWitness() int // number of elements expected in the public witness
 }
 
 // Setup prepares the public data associated to a circuit + public inputs.
-func Setup(ccs frontend.CompiledConstraintSystem, kzgSRS kzg.SRS) (ProvingKey, VerifyingKey, error) {
+func Setup(ccs frontend.CompiledConstraintSystem, kzgSRS kzg.SRS, opts ...backend.SetupOption) (ProvingKey, VerifyingKey, error) {
+
+	// apply options
+	opt, err := backend.NewSetupConfig(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	switch tccs := ccs.(type) {
 	case *cs_bn254.SparseR1CS:
-		return plonk_bn254.Setup(tccs, kzgSRS.(*kzg_bn254.SRS))
+		return plonk_bn254.Setup(tccs, kzgSRS.(*kzg_bn254.SRS), opt)
 	case *cs_bls12381.SparseR1CS:
-		return plonk_bls12381.Setup(tccs, kzgSRS.(*kzg_bls12381.SRS))
+		return plonk_bls12381.Setup(tccs, kzgSRS.(*kzg_bls12381.SRS), opt)
 	case *cs_bls12377.SparseR1CS:
-		return plonk_bls12377.Setup(tccs, kzgSRS.(*kzg_bls12377.SRS))
+		return plonk_bls12377.Setup(tccs, kzgSRS.(*kzg_bls12377.SRS), opt)
 	case *cs_bw6761.SparseR1CS:
-		return plonk_bw6761.Setup(tccs, kzgSRS.(*kzg_bw6761.SRS))
+		return plonk_bw6761.Setup(tccs, kzgSRS.(*kzg_bw6761.SRS), opt)
 	case *cs_bls24315.SparseR1CS:
-		return plonk_bls24315.Setup(tccs, kzgSRS.(*kzg_bls24315.SRS))
+		return plonk_bls24315.Setup(tccs, kzgSRS.(*kzg_bls24315.SRS), opt)
 	case *cs_bw6633.SparseR1CS:
-		return plonk_bw6633.Setup(tccs, kzgSRS.(*kzg_bw6633.SRS))
+		return plonk_bw6633.Setup(tccs, kzgSRS.(*kzg_bw6633.SRS), opt)
 	default:
 		panic("unrecognized SparseR1CS curve type")
 	}
@@ -168,7 +185,140 @@ func Prove(ccs frontend.CompiledConstraintSystem, pk ProvingKey, fullWitness *wi
 	}
 }
 
+// Solution represents a solved witness, produced by plonk.Solve and consumed by plonk.ProveFromSolution
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type Solution interface {
+	io.WriterTo
+	io.ReaderFrom
+	CurveID() ecc.ID
+}
+
+// Solve solves the constraint system with the full witness and returns a Solution, that
+// can be serialized and proven (possibly on another machine) with ProveFromSolution.
+//
+// if the force flag is set, the solution is filled with random values if the witness is invalid
+func Solve(ccs frontend.CompiledConstraintSystem, fullWitness *witness.Witness, opts ...backend.ProverOption) (Solution, error) {
+
+	// apply options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.SparseR1CS:
+		w, ok := fullWitness.Vector.(*witness_bn254.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return plonk_bn254.Solve(tccs, *w, opt)
+
+	case *cs_bls12381.SparseR1CS:
+		w, ok := fullWitness.Vector.(*witness_bls12381.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return plonk_bls12381.Solve(tccs, *w, opt)
+
+	case *cs_bls12377.SparseR1CS:
+		w, ok := fullWitness.Vector.(*witness_bls12377.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return plonk_bls12377.Solve(tccs, *w, opt)
+
+	case *cs_bw6761.SparseR1CS:
+		w, ok := fullWitness.Vector.(*witness_bw6761.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return plonk_bw6761.Solve(tccs, *w, opt)
+
+	case *cs_bls24315.SparseR1CS:
+		w, ok := fullWitness.Vector.(*witness_bls24315.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return plonk_bls24315.Solve(tccs, *w, opt)
+
+	case *cs_bw6633.SparseR1CS:
+		w, ok := fullWitness.Vector.(*witness_bw6633.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return plonk_bw6633.Solve(tccs, *w, opt)
+
+	default:
+		panic("unrecognized SparseR1CS curve type")
+	}
+}
+
+// ProveFromSolution generates PLONK proof from a Solution returned by Solve, skipping the solver.
+//
+// It returns an error wrapping backend.ErrCircuitMismatch if the solution or the proving key
+// don't belong to the constraint system.
+func ProveFromSolution(ccs frontend.CompiledConstraintSystem, pk ProvingKey, solution Solution, opts ...backend.ProverOption) (Proof, error) {
+
+	// apply options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tccs := ccs.(type) {
+	case *cs_bn254.SparseR1CS:
+		s, ok := solution.(*plonk_bn254.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return plonk_bn254.ProveFromSolution(tccs, pk.(*plonk_bn254.ProvingKey), s, opt)
+
+	case *cs_bls12381.SparseR1CS:
+		s, ok := solution.(*plonk_bls12381.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return plonk_bls12381.ProveFromSolution(tccs, pk.(*plonk_bls12381.ProvingKey), s, opt)
+
+	case *cs_bls12377.SparseR1CS:
+		s, ok := solution.(*plonk_bls12377.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return plonk_bls12377.ProveFromSolution(tccs, pk.(*plonk_bls12377.ProvingKey), s, opt)
+
+	case *cs_bw6761.SparseR1CS:
+		s, ok := solution.(*plonk_bw6761.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return plonk_bw6761.ProveFromSolution(tccs, pk.(*plonk_bw6761.ProvingKey), s, opt)
+
+	case *cs_bls24315.SparseR1CS:
+		s, ok := solution.(*plonk_bls24315.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return plonk_bls24315.ProveFromSolution(tccs, pk.(*plonk_bls24315.ProvingKey), s, opt)
+
+	case *cs_bw6633.SparseR1CS:
+		s, ok := solution.(*plonk_bw6633.Solution)
+		if !ok {
+			return nil, errSolutionCurve
+		}
+		return plonk_bw6633.ProveFromSolution(tccs, pk.(*plonk_bw6633.ProvingKey), s, opt)
+
+	default:
+		panic("unrecognized SparseR1CS curve type")
+	}
+}
+
+var errSolutionCurve = errors.New("solution and constraint system curves don't match")
+
 // Verify verifies a PLONK proof, from the proof, preprocessed public data, and public witness.
+//
+// If vk was returned by Prepare, the pairings use its precomputations.
 func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error {
 
 	switch _proof := proof.(type) {
@@ -178,6 +328,9 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*plonk_bn254.PreparedVerifyingKey); ok {
+			return plonk_bn254.VerifyPrepared(_proof, pvk, *w)
+		}
 		return plonk_bn254.Verify(_proof, vk.(*plonk_bn254.VerifyingKey), *w)
 
 	case *plonk_bls12381.Proof:
@@ -185,6 +338,9 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*plonk_bls12381.PreparedVerifyingKey); ok {
+			return plonk_bls12381.VerifyPrepared(_proof, pvk, *w)
+		}
 		return plonk_bls12381.Verify(_proof, vk.(*plonk_bls12381.VerifyingKey), *w)
 
 	case *plonk_bls12377.Proof:
@@ -192,6 +348,9 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*plonk_bls12377.PreparedVerifyingKey); ok {
+			return plonk_bls12377.VerifyPrepared(_proof, pvk, *w)
+		}
 		return plonk_bls12377.Verify(_proof, vk.(*plonk_bls12377.VerifyingKey), *w)
 
 	case *plonk_bw6761.Proof:
@@ -199,6 +358,9 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*plonk_bw6761.PreparedVerifyingKey); ok {
+			return plonk_bw6761.VerifyPrepared(_proof, pvk, *w)
+		}
 		return plonk_bw6761.Verify(_proof, vk.(*plonk_bw6761.VerifyingKey), *w)
 
 	case *plonk_bw6633.Proof:
@@ -206,6 +368,9 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*plonk_bw6633.PreparedVerifyingKey); ok {
+			return plonk_bw6633.VerifyPrepared(_proof, pvk, *w)
+		}
 		return plonk_bw6633.Verify(_proof, vk.(*plonk_bw6633.VerifyingKey), *w)
 
 	case *plonk_bls24315.Proof:
@@ -213,6 +378,9 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error
 		if !ok {
 			return witness.ErrInvalidWitness
 		}
+		if pvk, ok := vk.(*plonk_bls24315.PreparedVerifyingKey); ok {
+			return plonk_bls24315.VerifyPrepared(_proof, pvk, *w)
+		}
 		return plonk_bls24315.Verify(_proof, vk.(*plonk_bls24315.VerifyingKey), *w)
 
 	default:
@@ -220,6 +388,30 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness *witness.Witness) error
 	}
 }
 
+// NewSolution instantiates a curve-typed Solution and returns an interface
+// This function exists for serialization purposes
+func NewSolution(curveID ecc.ID) Solution {
+	var solution Solution
+	switch curveID {
+	case ecc.BN254:
+		solution = &plonk_bn254.Solution{}
+	case ecc.BLS12_381:
+		solution = &plonk_bls12381.Solution{}
+	case ecc.BLS12_377:
+		solution = &plonk_bls12377.Solution{}
+	case ecc.BW6_761:
+		solution = &plonk_bw6761.Solution{}
+	case ecc.BLS24_315:
+		solution = &plonk_bls24315.Solution{}
+	case ecc.BW6_633:
+		solution = &plonk_bw6633.Solution{}
+	default:
+		panic("not implemented")
+	}
+
+	return solution
+}
+
 // NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
 // This method exists for (de)serialization purposes
 func NewCS(curveID ecc.ID) frontend.CompiledConstraintSystem {
@@ -291,6 +483,54 @@ func NewProof(curveID ecc.ID) Proof {
 	return proof
 }
 
+// Prepare returns a VerifyingKey with the precomputations of the pairings of Verify: except on BW6 curves,
+// the lines of the Miller loops of the G2 points of the KZG SRS, which must be set (see InitKZG).
+//
+// Verify uses them through a fast path. They are serialized with the key; they are not checked
+// when the key is read through NewPreparedVerifyingKey, so it must come from a trusted source.
+func Prepare(vk VerifyingKey) (VerifyingKey, error) {
+	switch _vk := vk.(type) {
+	case *plonk_bn254.VerifyingKey:
+		return plonk_bn254.Prepare(_vk)
+	case *plonk_bls12381.VerifyingKey:
+		return plonk_bls12381.Prepare(_vk)
+	case *plonk_bls12377.VerifyingKey:
+		return plonk_bls12377.Prepare(_vk)
+	case *plonk_bw6761.VerifyingKey:
+		return plonk_bw6761.Prepare(_vk)
+	case *plonk_bw6633.VerifyingKey:
+		return plonk_bw6633.Prepare(_vk)
+	case *plonk_bls24315.VerifyingKey:
+		return plonk_bls24315.Prepare(_vk)
+	default:
+		panic("unrecognized VerifyingKey curve type")
+	}
+}
+
+// NewPreparedVerifyingKey instantiates a curve-typed VerifyingKey returned by Prepare and returns an interface
+// This function exists for serialization purposes
+func NewPreparedVerifyingKey(curveID ecc.ID) VerifyingKey {
+	var pvk VerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		pvk = &plonk_bn254.PreparedVerifyingKey{}
+	case ecc.BLS12_377:
+		pvk = &plonk_bls12377.PreparedVerifyingKey{}
+	case ecc.BLS12_381:
+		pvk = &plonk_bls12381.PreparedVerifyingKey{}
+	case ecc.BW6_761:
+		pvk = &plonk_bw6761.PreparedVerifyingKey{}
+	case ecc.BLS24_315:
+		pvk = &plonk_bls24315.PreparedVerifyingKey{}
+	case ecc.BW6_633:
+		pvk = &plonk_bw6633.PreparedVerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+
+	return pvk
+}
+
 // NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
 // This function exists for serialization purposes
 func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
@@ -314,3 +554,27 @@ func NewVerifyingKey(curveID ecc.ID) VerifyingKey {
 
 	return vk
 }
+
+// NewSRS instantiates a curve-typed KZG SRS and returns an interface
+// This function exists for serialization purposes
+func NewSRS(curveID ecc.ID) kzg.SRS {
+	var srs kzg.SRS
+	switch curveID {
+	case ecc.BN254:
+		srs = &kzg_bn254.SRS{}
+	case ecc.BLS12_377:
+		srs = &kzg_bls12377.SRS{}
+	case ecc.BLS12_381:
+		srs = &kzg_bls12381.SRS{}
+	case ecc.BW6_761:
+		srs = &kzg_bw6761.SRS{}
+	case ecc.BLS24_315:
+		srs = &kzg_bls24315.SRS{}
+	case ecc.BW6_633:
+		srs = &kzg_bw6633.SRS{}
+	default:
+		panic("not implemented")
+	}
+
+	return srs
+}
@@ -0,0 +1,277 @@
+package plonk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	plonk_bn254 "github.com/consensys/gnark/internal/backend/bn254/plonk"
+	"github.com/stretchr/testify/require"
+)
+
+type digestCircuit struct {
+	constant int
+	X        frontend.Variable
+	Y        frontend.Variable `gnark:",public"`
+}
+
+func (c *digestCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X, c.constant), c.Y)
+	return nil
+}
+
+func TestCircuitDigest(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 3})
+	assert.NoError(err)
+	other, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 5})
+	assert.NoError(err)
+	assert.NotEqual(ccs.Digest(), other.Digest())
+
+	srs, err := kzg.NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	pk, vk, err := Setup(ccs, srs)
+	assert.NoError(err)
+
+	// the digest survives serialization
+	var buf bytes.Buffer
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	vkRead := NewVerifyingKey(ecc.BN254)
+	_, err = vkRead.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(ccs.Digest(), vkRead.(*plonk_bn254.VerifyingKey).CircuitDigest)
+
+	buf.Reset()
+	_, err = pk.WriteTo(&buf)
+	assert.NoError(err)
+	pkRead := NewProvingKey(ecc.BN254)
+	_, err = pkRead.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(ccs.Digest(), pkRead.(*plonk_bn254.ProvingKey).Vk.CircuitDigest)
+
+	w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+
+	proof, err := Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(Verify(proof, vk, publicWitness))
+
+	// a proving key generated for another circuit is rejected
+	_, err = Prove(other, pk, w)
+	assert.True(errors.Is(err, backend.ErrCircuitMismatch), "expected ErrCircuitMismatch, got %v", err)
+}
+
+func TestProveFromSolution(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 3})
+	assert.NoError(err)
+	other, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 5})
+	assert.NoError(err)
+
+	srs, err := kzg.NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	pk, vk, err := Setup(ccs, srs)
+	assert.NoError(err)
+
+	w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+
+	// solve, and prove from the serialized solution
+	solution, err := Solve(ccs, w)
+	assert.NoError(err)
+	var buf bytes.Buffer
+	_, err = solution.WriteTo(&buf)
+	assert.NoError(err)
+	solution = NewSolution(ecc.BN254)
+	_, err = solution.ReadFrom(&buf)
+	assert.NoError(err)
+
+	proof, err := ProveFromSolution(ccs, pk, solution)
+	assert.NoError(err)
+	assert.NoError(Verify(proof, vk, publicWitness))
+
+	// a solution of another circuit is rejected
+	otherSolution, err := Solve(other, w, backend.IgnoreSolverError())
+	assert.NoError(err)
+	_, err = ProveFromSolution(ccs, pk, otherSolution)
+	assert.True(errors.Is(err, backend.ErrCircuitMismatch), "expected ErrCircuitMismatch, got %v", err)
+}
+
+func TestCancelAndProgress(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 3})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, ecc.BN254)
+	assert.NoError(err)
+
+	srs, err := kzg.NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+
+	// progress is reported for each phase, up to 100%
+	var phases []string
+	var percents []int
+	progress := func(phase string, percent int) {
+		phases = append(phases, phase)
+		percents = append(percents, percent)
+	}
+	checkProgress := func() {
+		assert.Greater(len(phases), 2)
+		assert.Equal(backend.PhaseDone, phases[len(phases)-1])
+		assert.Equal(100, percents[len(percents)-1])
+		assert.IsIncreasing(percents)
+		phases, percents = nil, nil
+	}
+
+	pk, _, err := Setup(ccs, srs, backend.WithSetupProgress(progress))
+	assert.NoError(err)
+	checkProgress()
+
+	_, err = Prove(ccs, pk, w, backend.WithProgress(progress))
+	assert.NoError(err)
+	assert.Equal("solve", phases[0])
+	checkProgress()
+
+	// cancelled jobs return the context error
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = Setup(ccs, srs, backend.WithSetupContext(ctx))
+	assert.True(errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+
+	_, err = Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.True(errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+}
+
+func TestProver(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 3})
+	assert.NoError(err)
+	other, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 5})
+	assert.NoError(err)
+	srs, err := kzg.NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	pk, vk, err := Setup(ccs, srs)
+	assert.NoError(err)
+
+	var witnesses, publicWitnesses []*witness.Witness
+	for _, x := range []int{2, 3} {
+		w, err := frontend.NewWitness(&digestCircuit{X: x, Y: 3 * x * x}, ecc.BN254)
+		assert.NoError(err)
+		publicWitness, err := w.Public()
+		assert.NoError(err)
+		witnesses = append(witnesses, w)
+		publicWitnesses = append(publicWitnesses, publicWitness)
+	}
+
+	prover, err := NewProver(ccs, pk)
+	assert.NoError(err)
+	proof, err := prover.Prove(witnesses[0])
+	assert.NoError(err)
+	assert.NoError(Verify(proof, vk, publicWitnesses[0]))
+
+	proofs, err := prover.ProveBatch(witnesses)
+	assert.NoError(err)
+	for i := range proofs {
+		assert.NoError(Verify(proofs[i], vk, publicWitnesses[i]))
+	}
+
+	// a proving key generated for another circuit is rejected
+	_, err = NewProver(other, pk)
+	assert.True(errors.Is(err, backend.ErrCircuitMismatch), "expected ErrCircuitMismatch, got %v", err)
+}
+
+func TestPreparedVerifyingKey(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 3})
+	assert.NoError(err)
+	srs, err := kzg.NewSRS(16, big.NewInt(42))
+	assert.NoError(err)
+	pk, vk, err := Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	proof, err := Prove(ccs, pk, w)
+	assert.NoError(err)
+
+	// the prepared key survives serialization, without InitKZG
+	pvk, err := Prepare(vk)
+	assert.NoError(err)
+	var buf bytes.Buffer
+	_, err = pvk.WriteTo(&buf)
+	assert.NoError(err)
+	pvk = NewPreparedVerifyingKey(ecc.BN254)
+	_, err = pvk.ReadFrom(&buf)
+	assert.NoError(err)
+
+	assert.NoError(Verify(proof, pvk, publicWitness))
+	wrong, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 13}, ecc.BN254, frontend.PublicOnly())
+	assert.NoError(err)
+	assert.Error(Verify(proof, pvk, wrong))
+}
+
+func TestKeysCarrySRS(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &digestCircuit{constant: 3})
+	assert.NoError(err)
+	srs, err := kzg.NewSRS(1<<10, big.NewInt(42))
+	assert.NoError(err)
+	pk, vk, err := Setup(ccs, srs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&digestCircuit{X: 2, Y: 12}, ecc.BN254)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+
+	// the verifying key only keeps the points of the SRS used by the verifier
+	assert.Len(vk.(*plonk_bn254.VerifyingKey).KZGSRS.G1, 1)
+
+	// the keys are read without InitKZG, with or without point compression
+	var buf bytes.Buffer
+	_, err = pk.WriteTo(&buf)
+	assert.NoError(err)
+	compressedSize := buf.Len()
+	_, err = pk.WriteRawTo(&buf)
+	assert.NoError(err)
+	assert.Greater(buf.Len()-compressedSize, compressedSize)
+
+	pkRead := NewProvingKey(ecc.BN254)
+	_, err = pkRead.ReadFrom(&buf)
+	assert.NoError(err)
+	pkRawRead := NewProvingKey(ecc.BN254)
+	_, err = pkRawRead.UnsafeReadFrom(&buf)
+	assert.NoError(err)
+
+	buf.Reset()
+	_, err = vk.WriteRawTo(&buf)
+	assert.NoError(err)
+	vkRead := NewVerifyingKey(ecc.BN254)
+	_, err = vkRead.UnsafeReadFrom(&buf)
+	assert.NoError(err)
+
+	for _, pk := range []ProvingKey{pkRead, pkRawRead} {
+		proof, err := Prove(ccs, pk, w)
+		assert.NoError(err)
+		assert.NoError(Verify(proof, vkRead, publicWitness))
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonk
+
+import (
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	cs_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/cs"
+	cs_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/cs"
+	cs_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/cs"
+	cs_bn254 "github.com/consensys/gnark/internal/backend/bn254/cs"
+	cs_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/cs"
+	cs_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+
+	witness_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/witness"
+	witness_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/witness"
+	witness_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/witness"
+	witness_bn254 "github.com/consensys/gnark/internal/backend/bn254/witness"
+	witness_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/witness"
+	witness_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/witness"
+
+	plonk_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/plonk"
+	plonk_bls12381 "github.com/consensys/gnark/internal/backend/bls12-381/plonk"
+	plonk_bls24315 "github.com/consensys/gnark/internal/backend/bls24-315/plonk"
+	plonk_bn254 "github.com/consensys/gnark/internal/backend/bn254/plonk"
+	plonk_bw6633 "github.com/consensys/gnark/internal/backend/bw6-633/plonk"
+	plonk_bw6761 "github.com/consensys/gnark/internal/backend/bw6-761/plonk"
+)
+
+// Prover proves many witnesses of the same circuit with the same proving key (see NewProver).
+//
+// It checks that the proving key matches the circuit once, and reuses its vectors and the
+// precomputations derived from the proving key from one proof to the next. A Prover can be
+// used by concurrent go routines.
+type Prover interface {
+	// Prove runs the plonk.Prove algorithm on the full witness
+	Prove(fullWitness *witness.Witness, opts ...backend.ProverOption) (Proof, error)
+
+	// ProveBatch runs the plonk.Prove algorithm on the full witnesses, in order. Each witness
+	// is solved while the previous one is being proven, unless backend.WithNbTasks bounds the prover.
+	//
+	// backend.WithProgress reports the phase "prove" at the start of each proof, with the
+	// percentage of the proofs done. backend.WithRandomSource only provides the blinding factors,
+	// so that the proofs are those of successive Prove calls; with backend.IgnoreSolverError, the
+	// wires of an invalid witness are filled with values from crypto/rand.
+	ProveBatch(fullWitnesses []*witness.Witness, opts ...backend.ProverOption) ([]Proof, error)
+}
+
+type prover struct {
+	p interface{}
+}
+
+// NewProver returns a Prover of the SparseR1CS with pk.
+//
+// It returns an error wrapping backend.ErrCircuitMismatch if pk was not generated for ccs.
+func NewProver(ccs frontend.CompiledConstraintSystem, pk ProvingKey) (Prover, error) {
+	var p interface{}
+	var err error
+	switch _ccs := ccs.(type) {
+	case *cs_bls12377.SparseR1CS:
+		p, err = plonk_bls12377.NewProver(_ccs, pk.(*plonk_bls12377.ProvingKey))
+	case *cs_bls12381.SparseR1CS:
+		p, err = plonk_bls12381.NewProver(_ccs, pk.(*plonk_bls12381.ProvingKey))
+	case *cs_bn254.SparseR1CS:
+		p, err = plonk_bn254.NewProver(_ccs, pk.(*plonk_bn254.ProvingKey))
+	case *cs_bw6761.SparseR1CS:
+		p, err = plonk_bw6761.NewProver(_ccs, pk.(*plonk_bw6761.ProvingKey))
+	case *cs_bls24315.SparseR1CS:
+		p, err = plonk_bls24315.NewProver(_ccs, pk.(*plonk_bls24315.ProvingKey))
+	case *cs_bw6633.SparseR1CS:
+		p, err = plonk_bw6633.NewProver(_ccs, pk.(*plonk_bw6633.ProvingKey))
+	default:
+		panic("unrecognized SparseR1CS curve type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &prover{p: p}, nil
+}
+
+func (p *prover) Prove(fullWitness *witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+
+	// apply options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch _p := p.p.(type) {
+	case *plonk_bls12377.Prover:
+		w, ok := fullWitness.Vector.(*witness_bls12377.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return _p.Prove(*w, opt)
+	case *plonk_bls12381.Prover:
+		w, ok := fullWitness.Vector.(*witness_bls12381.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return _p.Prove(*w, opt)
+	case *plonk_bn254.Prover:
+		w, ok := fullWitness.Vector.(*witness_bn254.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return _p.Prove(*w, opt)
+	case *plonk_bw6761.Prover:
+		w, ok := fullWitness.Vector.(*witness_bw6761.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return _p.Prove(*w, opt)
+	case *plonk_bls24315.Prover:
+		w, ok := fullWitness.Vector.(*witness_bls24315.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return _p.Prove(*w, opt)
+	case *plonk_bw6633.Prover:
+		w, ok := fullWitness.Vector.(*witness_bw6633.Witness)
+		if !ok {
+			return nil, witness.ErrInvalidWitness
+		}
+		return _p.Prove(*w, opt)
+	default:
+		panic("unrecognized Prover curve type")
+	}
+}
+
+func (p *prover) ProveBatch(fullWitnesses []*witness.Witness, opts ...backend.ProverOption) ([]Proof, error) {
+
+	// apply options
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	proofs := make([]Proof, 0, len(fullWitnesses))
+	switch _p := p.p.(type) {
+	case *plonk_bls12377.Prover:
+		ws := make([]witness_bls12377.Witness, len(fullWitnesses))
+		for i := range fullWitnesses {
+			w, ok := fullWitnesses[i].Vector.(*witness_bls12377.Witness)
+			if !ok {
+				return nil, witness.ErrInvalidWitness
+			}
+			ws[i] = *w
+		}
+		_proofs, err := _p.ProveBatch(ws, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, proof := range _proofs {
+			proofs = append(proofs, proof)
+		}
+	case *plonk_bls12381.Prover:
+		ws := make([]witness_bls12381.Witness, len(fullWitnesses))
+		for i := range fullWitnesses {
+			w, ok := fullWitnesses[i].Vector.(*witness_bls12381.Witness)
+			if !ok {
+				return nil, witness.ErrInvalidWitness
+			}
+			ws[i] = *w
+		}
+		_proofs, err := _p.ProveBatch(ws, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, proof := range _proofs {
+			proofs = append(proofs, proof)
+		}
+	case *plonk_bn254.Prover:
+		ws := make([]witness_bn254.Witness, len(fullWitnesses))
+		for i := range fullWitnesses {
+			w, ok := fullWitnesses[i].Vector.(*witness_bn254.Witness)
+			if !ok {
+				return nil, witness.ErrInvalidWitness
+			}
+			ws[i] = *w
+		}
+		_proofs, err := _p.ProveBatch(ws, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, proof := range _proofs {
+			proofs = append(proofs, proof)
+		}
+	case *plonk_bw6761.Prover:
+		ws := make([]witness_bw6761.Witness, len(fullWitnesses))
+		for i := range fullWitnesses {
+			w, ok := fullWitnesses[i].Vector.(*witness_bw6761.Witness)
+			if !ok {
+				return nil, witness.ErrInvalidWitness
+			}
+			ws[i] = *w
+		}
+		_proofs, err := _p.ProveBatch(ws, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, proof := range _proofs {
+			proofs = append(proofs, proof)
+		}
+	case *plonk_bls24315.Prover:
+		ws := make([]witness_bls24315.Witness, len(fullWitnesses))
+		for i := range fullWitnesses {
+			w, ok := fullWitnesses[i].Vector.(*witness_bls24315.Witness)
+			if !ok {
+				return nil, witness.ErrInvalidWitness
+			}
+			ws[i] = *w
+		}
+		_proofs, err := _p.ProveBatch(ws, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, proof := range _proofs {
+			proofs = append(proofs, proof)
+		}
+	case *plonk_bw6633.Prover:
+		ws := make([]witness_bw6633.Witness, len(fullWitnesses))
+		for i := range fullWitnesses {
+			w, ok := fullWitnesses[i].Vector.(*witness_bw6633.Witness)
+			if !ok {
+				return nil, witness.ErrInvalidWitness
+			}
+			ws[i] = *w
+		}
+		_proofs, err := _p.ProveBatch(ws, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, proof := range _proofs {
+			proofs = append(proofs, proof)
+		}
+	default:
+		panic("unrecognized Prover curve type")
+	}
+	return proofs, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"plugin"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+// compileCmd compiles the circuit exported by a Go plugin
+func compileCmd(args []string, stdout io.Writer) error {
+	fs := newFlagSet("compile")
+	var sys system
+	sys.register(fs)
+	pluginPath := fs.String("plugin", "", "circuit plugin (go build -buildmode=plugin)")
+	symbol := fs.String("symbol", "Circuit", "name of the circuit variable exported by the plugin")
+	out := fs.String("o", "", "output constraint system file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"plugin": *pluginPath, "o": *out}); err != nil {
+		return err
+	}
+	backendID, curveID, err := sys.ids()
+	if err != nil {
+		return err
+	}
+
+	circuit, err := loadCircuit(*pluginPath, *symbol)
+	if err != nil {
+		return err
+	}
+
+	newBuilder := r1cs.NewBuilder
+	if backendID == backend.PLONK {
+		newBuilder = scs.NewBuilder
+	}
+	ccs, err := frontend.Compile(curveID, newBuilder, circuit)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(*out, ccs); err != nil {
+		return err
+	}
+
+	return printCCS(stdout, backendID, ccs)
+}
+
+// loadCircuit returns the circuit variable exported by a plugin, either as
+// var Circuit frontend.Circuit = &MyCircuit{} or var Circuit MyCircuit
+func loadCircuit(path, symbol string) (frontend.Circuit, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := p.Lookup(symbol)
+	if err != nil {
+		return nil, err
+	}
+	switch c := s.(type) {
+	case *frontend.Circuit:
+		return *c, nil
+	case frontend.Circuit:
+		return c, nil
+	default:
+		return nil, fmt.Errorf("%s.%s (%T) doesn't implement frontend.Circuit", path, symbol, s)
+	}
+}
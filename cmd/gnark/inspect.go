@@ -0,0 +1,116 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+)
+
+// inspectCmd prints the curve and the sizes of a serialized object
+func inspectCmd(args []string, stdout io.Writer) error {
+	fs := newFlagSet("inspect")
+	var sys system
+	sys.register(fs)
+	typ := fs.String("type", "", "type of the object (ccs, pk, vk or proof)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: gnark inspect -type ccs|pk|vk|proof [flags] file")
+	}
+	path := fs.Arg(0)
+	backendID, curveID, err := sys.ids()
+	if err != nil {
+		return err
+	}
+
+	var obj interface{}
+	switch *typ {
+	case "ccs":
+		obj, err = readCCS(path, backendID, curveID)
+	case "pk":
+		obj, err = readPK(path, backendID, curveID, "")
+	case "vk":
+		obj, err = readVK(path, backendID, curveID, "")
+	case "proof":
+		obj, err = readProof(path, backendID, curveID)
+	default:
+		return fmt.Errorf("unknown type %q, expected ccs, pk, vk or proof", *typ)
+	}
+	if err != nil {
+		return err
+	}
+
+	if ccs, ok := obj.(frontend.CompiledConstraintSystem); ok {
+		return printCCS(stdout, backendID, ccs)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	printField(stdout, "backend", backendID)
+	printField(stdout, "curve", curveID)
+	printField(stdout, "type", *typ)
+	printField(stdout, "bytes", info.Size())
+
+	// plonk proving keys embed their verifying key
+	if pk, ok := obj.(interface{ VerifyingKey() interface{} }); ok {
+		obj = pk.VerifyingKey()
+	}
+	if v, ok := obj.(interface{ NbPublicWitness() int }); ok {
+		printField(stdout, "nbPublicWitness", v.NbPublicWitness())
+	}
+	switch v := obj.(type) {
+	case groth16.ProvingKey:
+		printField(stdout, "nbG1", v.NbG1())
+		printField(stdout, "nbG2", v.NbG2())
+	case groth16.VerifyingKey:
+		printField(stdout, "nbG1", v.NbG1())
+		printField(stdout, "nbG2", v.NbG2())
+	}
+	return nil
+}
+
+// printCCS prints the curve and the sizes of a constraint system
+func printCCS(w io.Writer, backendID backend.ID, ccs frontend.CompiledConstraintSystem) error {
+	internal, secret, public := ccs.GetNbVariables()
+	printField(w, "backend", backendID)
+	printField(w, "curve", ccs.CurveID())
+	printField(w, "type", "ccs")
+	printField(w, "nbConstraints", ccs.GetNbConstraints())
+	printField(w, "nbCoefficients", ccs.GetNbCoefficients())
+	printField(w, "nbInternal", internal)
+	printField(w, "nbSecret", secret)
+	printField(w, "nbPublic", public)
+	if schema := ccs.GetSchema(); schema != nil {
+		printField(w, "nbPublicWitness", schema.NbPublic)
+	}
+	printField(w, "digest", fmt.Sprintf("%x", ccs.Digest()))
+	return nil
+}
+
+func printField(w io.Writer, name string, value interface{}) {
+	fmt.Fprintf(w, "%-16s %v\n", name+":", value)
+}
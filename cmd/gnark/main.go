@@ -0,0 +1,161 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gnark compiles circuits, runs the setup, proves and verifies from serialized files.
+//
+// Usage:
+//
+//	gnark compile  -plugin circuit.so -o circuit.ccs    compile a circuit from a Go plugin
+//	gnark srs      -size n -o kzg.srs                   generate an unsafe KZG SRS, for tests (plonk)
+//	gnark inspect  -type ccs|pk|vk|proof file           print the curve and sizes of a serialized object
+//	gnark setup    -ccs circuit.ccs -pk pk -vk vk       run the setup
+//	gnark prove    -ccs circuit.ccs -pk pk -witness witness.json -proof proof
+//	gnark verify   -ccs circuit.ccs -vk vk -proof proof -public public.json
+//	gnark solidity -vk vk -o Verifier.sol               export the solidity verifier (groth16, BN254)
+//
+// Every command takes -backend (groth16 or plonk, defaults to groth16) and -curve (defaults to BN254);
+// plonk setup takes -srs; the keys carry the KZG SRS, which prove and verify may replace with -srs.
+// Witnesses are in the JSON format of backend/witness, with the schema of the constraint system.
+//
+// A circuit plugin is built with go build -buildmode=plugin, and exports a variable Circuit
+// implementing frontend.Circuit.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/cmd/gnarkd/server"
+)
+
+// command runs a sub command with its arguments, and writes its output to stdout
+type command func(args []string, stdout io.Writer) error
+
+var commands = map[string]command{
+	"compile":  compileCmd,
+	"srs":      srsCmd,
+	"inspect":  inspectCmd,
+	"setup":    setupCmd,
+	"prove":    proveCmd,
+	"verify":   verifyCmd,
+	"solidity": solidityCmd,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "gnark:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage()
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return errUsage()
+	}
+	return cmd(args[1:], stdout)
+}
+
+func errUsage() error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("usage: gnark <%s> [flags]", strings.Join(names, "|"))
+}
+
+// system holds the -backend and -curve flags common to all commands
+type system struct {
+	backend string
+	curve   string
+}
+
+func (s *system) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.backend, "backend", "groth16", "proof system (groth16 or plonk)")
+	fs.StringVar(&s.curve, "curve", ecc.BN254.String(), "elliptic curve")
+}
+
+func (s *system) ids() (backend.ID, ecc.ID, error) {
+	backendID, err := server.ParseBackend(s.backend)
+	if err != nil {
+		return backend.UNKNOWN, ecc.UNKNOWN, err
+	}
+	curveID, err := server.ParseCurve(s.curve)
+	if err != nil {
+		return backend.UNKNOWN, ecc.UNKNOWN, err
+	}
+	return backendID, curveID, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("gnark "+name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+// required returns an error naming the first empty flag
+func required(flags map[string]string) error {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if flags[name] == "" {
+			return fmt.Errorf("missing -%s", name)
+		}
+	}
+	return nil
+}
+
+// readFile reads the object v from the file at path
+func readFile(path string, v io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := v.ReadFrom(f); err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeFile writes the object v to the file at path
+func writeFile(path string, v io.WriterTo) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := v.WriteTo(f); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Close()
+}
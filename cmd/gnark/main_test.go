@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestGroth16(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	file := func(name string) string { return filepath.Join(dir, name) }
+
+	writeCCS(t, backend.GROTH16, file("circuit.ccs"))
+	writeWitness(t, file("witness.json"), &cubicCircuit{X: 3, Y: 35})
+
+	out := runOK(t, "inspect", "-type", "ccs", file("circuit.ccs"))
+	assert.Contains(out, "nbConstraints:")
+	assert.Contains(out, "nbPublicWitness: 1")
+
+	runOK(t, "setup", "-ccs", file("circuit.ccs"), "-pk", file("pk"), "-vk", file("vk"))
+	out = runOK(t, "inspect", "-type", "vk", file("vk"))
+	assert.Contains(out, "nbPublicWitness: 1")
+	out = runOK(t, "inspect", "-type", "pk", file("pk"))
+	assert.Contains(out, "nbG1:")
+
+	runOK(t, "prove", "-ccs", file("circuit.ccs"), "-pk", file("pk"), "-witness", file("witness.json"),
+		"-proof", file("proof"), "-public", file("public.json"))
+	out = runOK(t, "inspect", "-type", "proof", file("proof"))
+	assert.Contains(out, "curve:           "+ecc.BN254.String())
+
+	out = runOK(t, "verify", "-ccs", file("circuit.ccs"), "-vk", file("vk"), "-proof", file("proof"), "-public", file("public.json"))
+	assert.Contains(out, "proof is valid")
+
+	// a wrong public witness is rejected
+	writeWitness(t, file("wrong.json"), &cubicCircuit{Y: 36})
+	_, err := runCmd("verify", "-ccs", file("circuit.ccs"), "-vk", file("vk"), "-proof", file("proof"), "-public", file("wrong.json"))
+	assert.Error(err)
+
+	runOK(t, "solidity", "-vk", file("vk"), "-o", file("Verifier.sol"))
+	sol, err := os.ReadFile(file("Verifier.sol"))
+	assert.NoError(err)
+	assert.Contains(string(sol), "contract Verifier")
+}
+
+func TestPlonk(t *testing.T) {
+	assert := require.New(t)
+	dir := t.TempDir()
+	file := func(name string) string { return filepath.Join(dir, name) }
+
+	writeCCS(t, backend.PLONK, file("circuit.ccs"))
+	writeWitness(t, file("witness.json"), &cubicCircuit{X: 3, Y: 35})
+
+	runOK(t, "srs", "-size", "64", "-o", file("kzg.srs"))
+	runOK(t, "setup", "-backend", "plonk", "-ccs", file("circuit.ccs"), "-srs", file("kzg.srs"), "-pk", file("pk"), "-vk", file("vk"))
+	out := runOK(t, "inspect", "-backend", "plonk", "-type", "pk", file("pk"))
+	assert.Contains(out, "nbPublicWitness: 1")
+
+	runOK(t, "prove", "-backend", "plonk", "-ccs", file("circuit.ccs"), "-pk", file("pk"), "-srs", file("kzg.srs"),
+		"-witness", file("witness.json"), "-proof", file("proof"), "-public", file("public.json"))
+	out = runOK(t, "verify", "-backend", "plonk", "-ccs", file("circuit.ccs"), "-vk", file("vk"), "-srs", file("kzg.srs"),
+		"-proof", file("proof"), "-public", file("public.json"))
+	assert.Contains(out, "proof is valid")
+
+	// plonk keys carry the srs
+	runOK(t, "prove", "-backend", "plonk", "-ccs", file("circuit.ccs"), "-pk", file("pk"),
+		"-witness", file("witness.json"), "-proof", file("proof"), "-public", file("public.json"))
+	out = runOK(t, "verify", "-backend", "plonk", "-ccs", file("circuit.ccs"), "-vk", file("vk"),
+		"-proof", file("proof"), "-public", file("public.json"))
+	assert.Contains(out, "proof is valid")
+
+	_, err := runCmd("solidity", "-backend", "plonk", "-vk", file("vk"), "-o", file("Verifier.sol"))
+	assert.Error(err)
+}
+
+func TestUsage(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := runCmd()
+	assert.Error(err)
+	_, err = runCmd("nope")
+	assert.Error(err)
+	_, err = runCmd("setup", "-curve", "nope", "-ccs", "a", "-pk", "b", "-vk", "c")
+	assert.Error(err)
+	_, err = runCmd("compile", "-plugin", filepath.Join(t.TempDir(), "missing.so"), "-o", "circuit.ccs")
+	assert.Error(err)
+}
+
+func runCmd(args ...string) (string, error) {
+	var stdout bytes.Buffer
+	err := run(args, &stdout)
+	return stdout.String(), err
+}
+
+func runOK(t *testing.T, args ...string) string {
+	out, err := runCmd(args...)
+	require.NoError(t, err, strings.Join(args, " "))
+	return out
+}
+
+func writeCCS(t *testing.T, backendID backend.ID, path string) {
+	newBuilder := r1cs.NewBuilder
+	if backendID == backend.PLONK {
+		newBuilder = scs.NewBuilder
+	}
+	ccs, err := frontend.Compile(ecc.BN254, newBuilder, &cubicCircuit{})
+	require.NoError(t, err)
+	require.NoError(t, writeFile(path, ccs))
+}
+
+func writeWitness(t *testing.T, path string, assignment *cubicCircuit) {
+	opts := []frontend.WitnessOption{}
+	if assignment.X == nil {
+		opts = append(opts, frontend.PublicOnly())
+	}
+	w, err := frontend.NewWitness(assignment, ecc.BN254, opts...)
+	require.NoError(t, err)
+	data, err := w.MarshalJSON()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+}
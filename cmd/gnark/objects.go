@@ -0,0 +1,147 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+)
+
+// readCCS reads a compiled constraint system
+func readCCS(path string, backendID backend.ID, curveID ecc.ID) (frontend.CompiledConstraintSystem, error) {
+	var ccs frontend.CompiledConstraintSystem
+	switch backendID {
+	case backend.GROTH16:
+		ccs = groth16.NewCS(curveID)
+	case backend.PLONK:
+		ccs = plonk.NewCS(curveID)
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", backendID)
+	}
+	if err := readFile(path, ccs); err != nil {
+		return nil, err
+	}
+	return ccs, nil
+}
+
+// readPK reads a proving key; plonk proving keys are initialized with the KZG SRS at srsPath, if any
+func readPK(path string, backendID backend.ID, curveID ecc.ID, srsPath string) (io.WriterTo, error) {
+	switch backendID {
+	case backend.GROTH16:
+		pk := groth16.NewProvingKey(curveID)
+		if err := readFile(path, pk); err != nil {
+			return nil, err
+		}
+		return pk, nil
+	case backend.PLONK:
+		pk := plonk.NewProvingKey(curveID)
+		if err := readFile(path, pk); err != nil {
+			return nil, err
+		}
+		if srsPath == "" {
+			return pk, nil
+		}
+		srs, err := readSRS(srsPath, curveID)
+		if err != nil {
+			return nil, err
+		}
+		if err := pk.InitKZG(srs); err != nil {
+			return nil, err
+		}
+		return pk, nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", backendID)
+	}
+}
+
+// readVK reads a verifying key; plonk verifying keys are initialized with the KZG SRS at srsPath, if any
+func readVK(path string, backendID backend.ID, curveID ecc.ID, srsPath string) (io.WriterTo, error) {
+	switch backendID {
+	case backend.GROTH16:
+		vk := groth16.NewVerifyingKey(curveID)
+		if err := readFile(path, vk); err != nil {
+			return nil, err
+		}
+		return vk, nil
+	case backend.PLONK:
+		vk := plonk.NewVerifyingKey(curveID)
+		if err := readFile(path, vk); err != nil {
+			return nil, err
+		}
+		if srsPath == "" {
+			return vk, nil
+		}
+		srs, err := readSRS(srsPath, curveID)
+		if err != nil {
+			return nil, err
+		}
+		if err := vk.InitKZG(srs); err != nil {
+			return nil, err
+		}
+		return vk, nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", backendID)
+	}
+}
+
+// readProof reads a proof
+func readProof(path string, backendID backend.ID, curveID ecc.ID) (io.WriterTo, error) {
+	switch backendID {
+	case backend.GROTH16:
+		proof := groth16.NewProof(curveID)
+		if err := readFile(path, proof); err != nil {
+			return nil, err
+		}
+		return proof, nil
+	case backend.PLONK:
+		proof := plonk.NewProof(curveID)
+		if err := readFile(path, proof); err != nil {
+			return nil, err
+		}
+		return proof, nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %s", backendID)
+	}
+}
+
+// readWitness reads a JSON witness with the schema of ccs
+func readWitness(path string, ccs frontend.CompiledConstraintSystem) (*witness.Witness, error) {
+	if ccs.GetSchema() == nil {
+		return nil, errors.New("the constraint system has no schema")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	w, err := witness.New(ccs.CurveID(), ccs.GetSchema())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return w, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+)
+
+// proveCmd proves a JSON witness, and writes the proof and optionally the public witness
+func proveCmd(args []string, stdout io.Writer) error {
+	fs := newFlagSet("prove")
+	var sys system
+	sys.register(fs)
+	ccsPath := fs.String("ccs", "", "constraint system file")
+	pkPath := fs.String("pk", "", "proving key file")
+	srsPath := fs.String("srs", "", "KZG SRS file replacing the one of the proving key (plonk, optional)")
+	witnessPath := fs.String("witness", "", "full witness JSON file")
+	proofPath := fs.String("proof", "", "output proof file")
+	publicPath := fs.String("public", "", "output public witness JSON file (optional)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"ccs": *ccsPath, "pk": *pkPath, "witness": *witnessPath, "proof": *proofPath}); err != nil {
+		return err
+	}
+	backendID, curveID, err := sys.ids()
+	if err != nil {
+		return err
+	}
+	ccs, err := readCCS(*ccsPath, backendID, curveID)
+	if err != nil {
+		return err
+	}
+	pk, err := readPK(*pkPath, backendID, curveID, *srsPath)
+	if err != nil {
+		return err
+	}
+	fullWitness, err := readWitness(*witnessPath, ccs)
+	if err != nil {
+		return err
+	}
+
+	var proof io.WriterTo
+	switch backendID {
+	case backend.GROTH16:
+		proof, err = groth16.Prove(ccs, pk.(groth16.ProvingKey), fullWitness)
+	case backend.PLONK:
+		proof, err = plonk.Prove(ccs, pk.(plonk.ProvingKey), fullWitness)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeFile(*proofPath, proof); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "proof written to %s\n", *proofPath)
+
+	if *publicPath == "" {
+		return nil
+	}
+	publicWitness, err := fullWitness.Public()
+	if err != nil {
+		return err
+	}
+	data, err := publicWitness.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*publicPath, data, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "public witness written to %s\n", *publicPath)
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+)
+
+// setupCmd runs the setup of a constraint system, and writes the proving and verifying keys
+func setupCmd(args []string, stdout io.Writer) error {
+	fs := newFlagSet("setup")
+	var sys system
+	sys.register(fs)
+	ccsPath := fs.String("ccs", "", "constraint system file")
+	srsPath := fs.String("srs", "", "KZG SRS file (plonk)")
+	pkPath := fs.String("pk", "", "output proving key file")
+	vkPath := fs.String("vk", "", "output verifying key file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"ccs": *ccsPath, "pk": *pkPath, "vk": *vkPath}); err != nil {
+		return err
+	}
+	backendID, curveID, err := sys.ids()
+	if err != nil {
+		return err
+	}
+
+	ccs, err := readCCS(*ccsPath, backendID, curveID)
+	if err != nil {
+		return err
+	}
+
+	var pk, vk io.WriterTo
+	switch backendID {
+	case backend.GROTH16:
+		pk, vk, err = groth16.Setup(ccs)
+	case backend.PLONK:
+		if err := required(map[string]string{"srs": *srsPath}); err != nil {
+			return err
+		}
+		var srs kzg.SRS
+		if srs, err = readSRS(*srsPath, curveID); err != nil {
+			return err
+		}
+		pk, vk, err = plonk.Setup(ccs, srs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(*pkPath, pk); err != nil {
+		return err
+	}
+	if err := writeFile(*vkPath, vk); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "proving key written to %s, verifying key written to %s\n", *pkPath, *vkPath)
+	return nil
+}
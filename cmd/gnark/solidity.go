@@ -0,0 +1,66 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+)
+
+// solidityCmd exports the solidity verifier of a groth16 verifying key
+func solidityCmd(args []string, stdout io.Writer) error {
+	fs := newFlagSet("solidity")
+	var sys system
+	sys.register(fs)
+	vkPath := fs.String("vk", "", "verifying key file")
+	out := fs.String("o", "", "output solidity file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"vk": *vkPath, "o": *out}); err != nil {
+		return err
+	}
+	backendID, curveID, err := sys.ids()
+	if err != nil {
+		return err
+	}
+	if backendID != backend.GROTH16 {
+		return fmt.Errorf("solidity export is not supported for %s", backendID)
+	}
+
+	vk, err := readVK(*vkPath, backendID, curveID, "")
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := vk.(groth16.VerifyingKey).ExportSolidity(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "solidity verifier written to %s\n", *out)
+	return nil
+}
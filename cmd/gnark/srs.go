@@ -0,0 +1,99 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	kzg_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/kzg"
+	kzg_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/kzg"
+	kzg_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/kzg"
+	kzg_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	kzg_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/kzg"
+	kzg_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/kzg"
+	"github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/cmd/gnarkd/server"
+)
+
+// srsCmd generates a KZG SRS from a random secret.
+//
+// The secret is known to this process while it runs: the SRS is for tests only, production
+// circuits must use the output of a multi-party ceremony.
+func srsCmd(args []string, stdout io.Writer) error {
+	fs := newFlagSet("srs")
+	var sys system
+	sys.register(fs)
+	size := fs.Uint64("size", 0, "size of the SRS, at least the number of constraints + 3")
+	out := fs.String("o", "", "output SRS file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"o": *out}); err != nil {
+		return err
+	}
+	if *size == 0 {
+		return errors.New("missing -size")
+	}
+	curveID, err := server.ParseCurve(sys.curve)
+	if err != nil {
+		return err
+	}
+
+	alpha, err := rand.Int(rand.Reader, curveID.Info().Fr.Modulus())
+	if err != nil {
+		return err
+	}
+	var srs kzg.SRS
+	switch curveID {
+	case ecc.BN254:
+		srs, err = kzg_bn254.NewSRS(*size, alpha)
+	case ecc.BLS12_377:
+		srs, err = kzg_bls12377.NewSRS(*size, alpha)
+	case ecc.BLS12_381:
+		srs, err = kzg_bls12381.NewSRS(*size, alpha)
+	case ecc.BW6_761:
+		srs, err = kzg_bw6761.NewSRS(*size, alpha)
+	case ecc.BLS24_315:
+		srs, err = kzg_bls24315.NewSRS(*size, alpha)
+	case ecc.BW6_633:
+		srs, err = kzg_bw6633.NewSRS(*size, alpha)
+	default:
+		return fmt.Errorf("unsupported curve %s", curveID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeFile(*out, srs); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "unsafe kzg srs of size %d written to %s\n", *size, *out)
+	return nil
+}
+
+// readSRS reads a KZG SRS
+func readSRS(path string, curveID ecc.ID) (kzg.SRS, error) {
+	srs := plonk.NewSRS(curveID)
+	if err := readFile(path, srs); err != nil {
+		return nil, err
+	}
+	return srs, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+)
+
+// verifyCmd verifies a proof against a JSON public witness.
+//
+// The constraint system is only read for the schema of the public witness.
+func verifyCmd(args []string, stdout io.Writer) error {
+	fs := newFlagSet("verify")
+	var sys system
+	sys.register(fs)
+	ccsPath := fs.String("ccs", "", "constraint system file, for the schema of the public witness")
+	vkPath := fs.String("vk", "", "verifying key file")
+	srsPath := fs.String("srs", "", "KZG SRS file replacing the one of the verifying key (plonk, optional)")
+	proofPath := fs.String("proof", "", "proof file")
+	publicPath := fs.String("public", "", "public witness JSON file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"ccs": *ccsPath, "vk": *vkPath, "proof": *proofPath, "public": *publicPath}); err != nil {
+		return err
+	}
+	backendID, curveID, err := sys.ids()
+	if err != nil {
+		return err
+	}
+	ccs, err := readCCS(*ccsPath, backendID, curveID)
+	if err != nil {
+		return err
+	}
+	vk, err := readVK(*vkPath, backendID, curveID, *srsPath)
+	if err != nil {
+		return err
+	}
+	proof, err := readProof(*proofPath, backendID, curveID)
+	if err != nil {
+		return err
+	}
+	publicWitness, err := readWitness(*publicPath, ccs)
+	if err != nil {
+		return err
+	}
+
+	switch backendID {
+	case backend.GROTH16:
+		err = groth16.Verify(proof.(groth16.Proof), vk.(groth16.VerifyingKey), publicWitness)
+	case backend.PLONK:
+		err = plonk.Verify(proof.(plonk.Proof), vk.(plonk.VerifyingKey), publicWitness)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, "proof is valid")
+	return nil
+}
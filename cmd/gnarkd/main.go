@@ -0,0 +1,135 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gnarkd is a proving service: it holds compiled circuits with their proving keys, and proves
+// witnesses submitted over HTTP (see server.ServeHTTP for the API).
+//
+// Circuits can be registered at startup from a directory, where each sub-directory <id> contains
+//
+//	circuit.json  {"backend": "groth16" | "plonk", "curve": "BN254" | ...}
+//	circuit.ccs   the serialized compiled constraint system
+//	circuit.pk    the serialized proving key
+//	circuit.srs   the serialized KZG SRS replacing the one of the proving key (plonk only, optional)
+//
+// or at runtime with PUT /v1/circuits/{id}.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/consensys/gnark/cmd/gnarkd/server"
+	"github.com/consensys/gnark/logger"
+)
+
+var (
+	fAddr        = flag.String("addr", "localhost:9002", "listening address")
+	fCircuits    = flag.String("circuits", "", "directory of the circuits to register at startup")
+	fConcurrency = flag.Int("concurrency", 1, "number of proofs computed concurrently")
+	fQueue       = flag.Int("queue", 16, "number of proofs waiting for a worker")
+	fTasks       = flag.Int("tasks", 0, "number of tasks per proof (defaults to NumCPU / concurrency)")
+	fJobTTL      = flag.Duration("job-ttl", 10*time.Minute, "how long the result of an asynchronous proof is kept")
+)
+
+func main() {
+	flag.Parse()
+	log := logger.Logger()
+
+	s := server.New(server.Config{
+		MaxConcurrency: *fConcurrency,
+		QueueSize:      *fQueue,
+		NbTasks:        *fTasks,
+		JobTTL:         *fJobTTL,
+	})
+	defer s.Close()
+
+	if *fCircuits != "" {
+		if err := loadCircuits(s, *fCircuits); err != nil {
+			log.Fatal().Err(err).Msg("loading circuits")
+		}
+	}
+
+	log.Info().Str("addr", *fAddr).Msg("gnarkd listening")
+	if err := http.ListenAndServe(*fAddr, s); err != nil {
+		log.Fatal().Err(err).Msg("serving")
+	}
+}
+
+// loadCircuits registers the circuits of each sub-directory of dir
+func loadCircuits(s *server.Server, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if err := loadCircuit(s, e.Name(), filepath.Join(dir, e.Name())); err != nil {
+			return fmt.Errorf("circuit %s: %w", e.Name(), err)
+		}
+	}
+	return nil
+}
+
+func loadCircuit(s *server.Server, id, dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, "circuit.json"))
+	if err != nil {
+		return err
+	}
+	var desc struct {
+		Backend string `json:"backend"`
+		Curve   string `json:"curve"`
+	}
+	if err := json.Unmarshal(data, &desc); err != nil {
+		return fmt.Errorf("circuit.json: %w", err)
+	}
+	backendID, err := server.ParseBackend(desc.Backend)
+	if err != nil {
+		return err
+	}
+	curveID, err := server.ParseCurve(desc.Curve)
+	if err != nil {
+		return err
+	}
+
+	ccs, err := os.Open(filepath.Join(dir, "circuit.ccs"))
+	if err != nil {
+		return err
+	}
+	defer ccs.Close()
+	pk, err := os.Open(filepath.Join(dir, "circuit.pk"))
+	if err != nil {
+		return err
+	}
+	defer pk.Close()
+
+	// the srs is only read for plonk circuits
+	srs, err := os.Open(filepath.Join(dir, "circuit.srs"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if srs != nil {
+		defer srs.Close()
+		return s.Load(id, backendID, curveID, ccs, pk, srs)
+	}
+	return s.Load(id, backendID, curveID, ccs, pk, nil)
+}
@@ -0,0 +1,306 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/witness"
+)
+
+// maxWitnessSize bounds the size of a witness in a prove request
+const maxWitnessSize = 64 << 20
+
+// CircuitInfo describes a registered circuit
+type CircuitInfo struct {
+	ID            string `json:"id"`
+	Backend       string `json:"backend"`
+	Curve         string `json:"curve"`
+	NbConstraints int    `json:"nbConstraints"`
+	NbPublic      int    `json:"nbPublic"`
+	NbSecret      int    `json:"nbSecret"`
+}
+
+// JobResponse is the result of a prove request, or the status of an asynchronous one
+type JobResponse struct {
+	ID      string    `json:"id"`
+	Circuit string    `json:"circuit"`
+	Status  JobStatus `json:"status"`
+
+	// Proof is the binary encoding of the proof (see WriteTo)
+	Proof []byte `json:"proof,omitempty"`
+
+	// PublicWitness is the binary encoding of the public witness (see witness.MarshalBinary)
+	PublicWitness []byte `json:"publicWitness,omitempty"`
+
+	// PublicWitnessJSON is the JSON encoding of the public witness (see witness.MarshalJSON)
+	PublicWitnessJSON json.RawMessage `json:"publicWitnessJSON,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// ServeHTTP implements http.Handler.
+//
+//	GET  /v1/circuits                   lists the registered circuits
+//	GET  /v1/circuits/{id}              describes a circuit
+//	PUT  /v1/circuits/{id}              registers a circuit, from a multipart form with the
+//	                                    "backend" and "curve" values and the "ccs", "pk" (and
+//	                                    optionally "srs" for plonk) binary files
+//	POST /v1/circuits/{id}/prove        proves the full witness in the body, and returns a JobResponse
+//	POST /v1/circuits/{id}/prove?async  queues the proof, and returns a JobResponse with its ID
+//	GET  /v1/jobs/{id}                  returns the JobResponse of an asynchronous proof
+//
+// The witness is in the JSON format of backend/witness if the Content-Type is application/json,
+// and in its binary format otherwise. The result of an asynchronous proof is returned once,
+// and expires after Config.JobTTL if it is not fetched.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(path) < 2 || path[0] != "v1" {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+
+	switch {
+	case path[1] == "circuits" && len(path) == 2 && r.Method == http.MethodGet:
+		s.listCircuits(w)
+	case path[1] == "circuits" && len(path) == 3 && r.Method == http.MethodGet:
+		s.getCircuit(w, path[2])
+	case path[1] == "circuits" && len(path) == 3 && r.Method == http.MethodPut:
+		s.putCircuit(w, r, path[2])
+	case path[1] == "circuits" && len(path) == 4 && path[3] == "prove" && r.Method == http.MethodPost:
+		s.prove(w, r, path[2])
+	case path[1] == "jobs" && len(path) == 3 && r.Method == http.MethodGet:
+		s.getJob(w, path[2])
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (s *Server) listCircuits(w http.ResponseWriter) {
+	circuits := s.Circuits()
+	infos := make([]CircuitInfo, len(circuits))
+	for i, c := range circuits {
+		infos[i] = c.Info()
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+func (s *Server) getCircuit(w http.ResponseWriter, id string) {
+	c, ok := s.Circuit(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %q", ErrUnknownCircuit, id))
+		return
+	}
+	writeJSON(w, http.StatusOK, c.Info())
+}
+
+func (s *Server) putCircuit(w http.ResponseWriter, r *http.Request, id string) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	backendID, err := ParseBackend(r.FormValue("backend"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	curveID, err := ParseCurve(r.FormValue("curve"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	files := make(map[string]io.Reader)
+	for _, name := range []string{"ccs", "pk", "srs"} {
+		f, _, err := r.FormFile(name)
+		if err == http.ErrMissingFile && name == "srs" {
+			continue
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("%s: %w", name, err))
+			return
+		}
+		defer f.Close()
+		files[name] = f
+	}
+
+	if err := s.Load(id, backendID, curveID, files["ccs"], files["pk"], files["srs"]); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	c, _ := s.Circuit(id)
+	writeJSON(w, http.StatusCreated, c.Info())
+}
+
+func (s *Server) prove(w http.ResponseWriter, r *http.Request, id string) {
+	c, ok := s.Circuit(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: %q", ErrUnknownCircuit, id))
+		return
+	}
+
+	// read the full witness
+	fullWitness, err := witness.New(c.CCS.CurveID(), c.CCS.GetSchema())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWitnessSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+		err = fullWitness.UnmarshalJSON(data)
+	} else {
+		err = fullWitness.UnmarshalBinary(data)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %s", witness.ErrInvalidWitness, err))
+		return
+	}
+
+	// asynchronous jobs are not bound to the request
+	_, async := r.URL.Query()["async"]
+	ctx := r.Context()
+	if async {
+		ctx = s.ctx
+	}
+
+	job, err := s.Submit(ctx, id, fullWitness)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrQueueFull) || errors.Is(err, ErrClosed) {
+			status = http.StatusServiceUnavailable
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	if async {
+		writeJSON(w, http.StatusAccepted, JobResponse{ID: job.ID, Circuit: id, Status: job.Status()})
+		return
+	}
+
+	defer s.forget(job.ID)
+	select {
+	case <-job.Done():
+	case <-r.Context().Done():
+		// the client is gone, the job fails with the context error
+		return
+	}
+	s.writeJob(w, job)
+}
+
+func (s *Server) getJob(w http.ResponseWriter, id string) {
+	job, err := s.Job(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	select {
+	case <-job.Done():
+		// the result is delivered once
+		s.forget(job.ID)
+		s.writeJob(w, job)
+	default:
+		writeJSON(w, http.StatusOK, JobResponse{ID: job.ID, Circuit: job.Circuit.ID, Status: job.Status()})
+	}
+}
+
+// writeJob writes the JobResponse of a done or failed job
+func (s *Server) writeJob(w http.ResponseWriter, job *Job) {
+	res := JobResponse{ID: job.ID, Circuit: job.Circuit.ID, Status: job.Status()}
+
+	proof, publicWitness, err := job.Result()
+	if err == nil {
+		var buf bytes.Buffer
+		if _, err = proof.WriteTo(&buf); err == nil {
+			res.Proof = buf.Bytes()
+			if res.PublicWitness, err = publicWitness.MarshalBinary(); err == nil {
+				res.PublicWitnessJSON, err = publicWitness.MarshalJSON()
+			}
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	res.Status = StatusFailed
+	res.Error = err.Error()
+	writeJSON(w, http.StatusUnprocessableEntity, res)
+}
+
+// Info returns the description of the circuit
+func (c *Circuit) Info() CircuitInfo {
+	info := CircuitInfo{
+		ID:            c.ID,
+		Backend:       c.Backend.String(),
+		Curve:         c.CCS.CurveID().String(),
+		NbConstraints: c.CCS.GetNbConstraints(),
+	}
+	if schema := c.CCS.GetSchema(); schema != nil {
+		info.NbPublic, info.NbSecret = schema.NbPublic, schema.NbSecret
+	}
+	return info
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+// ParseBackend returns the backend.ID whose String() is s, ignoring case
+func ParseBackend(s string) (backend.ID, error) {
+	for _, id := range backend.Implemented() {
+		if strings.EqualFold(id.String(), s) {
+			return id, nil
+		}
+	}
+	return backend.UNKNOWN, fmt.Errorf("unknown backend %q", s)
+}
+
+// ParseCurve returns the ecc.ID whose String() is s, ignoring case
+func ParseCurve(s string) (ecc.ID, error) {
+	for _, id := range ecc.Implemented() {
+		if strings.EqualFold(id.String(), s) {
+			return id, nil
+		}
+	}
+	return ecc.UNKNOWN, fmt.Errorf("unknown curve %q", s)
+}
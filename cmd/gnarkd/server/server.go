@@ -0,0 +1,443 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package server implements the gnarkd proving service.
+//
+// A Server holds compiled circuits registered with their proving keys, and proves witnesses
+// submitted for them. Proofs are queued and run by a bounded number of workers; a full queue
+// rejects new proofs with ErrQueueFull instead of accepting an unbounded backlog.
+//
+// Server implements http.Handler, see ServeHTTP for the API.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/logger"
+	"github.com/rs/zerolog"
+)
+
+var (
+	// ErrQueueFull is returned when a proof is submitted while the queue is full
+	ErrQueueFull = errors.New("proof queue is full")
+
+	// ErrUnknownCircuit is returned when a proof is submitted for a circuit that is not registered
+	ErrUnknownCircuit = errors.New("unknown circuit")
+
+	// ErrUnknownJob is returned when a job ID doesn't match a pending or finished job
+	ErrUnknownJob = errors.New("unknown job")
+
+	// ErrClosed is returned when a proof is submitted to a closed Server
+	ErrClosed = errors.New("server is closed")
+)
+
+// Config holds the configuration of a Server
+type Config struct {
+	// MaxConcurrency is the number of proofs computed concurrently, defaults to 1
+	MaxConcurrency int
+
+	// QueueSize is the number of proofs waiting for a worker, defaults to 16
+	QueueSize int
+
+	// NbTasks bounds the number of tasks of each proof (see backend.WithNbTasks),
+	// defaults to runtime.NumCPU() / MaxConcurrency
+	NbTasks int
+
+	// JobTTL is how long the result of a job is kept once the job is done or failed,
+	// waiting to be fetched, defaults to 10 minutes
+	JobTTL time.Duration
+
+	// Hints are the hint functions of the registered circuits, in addition to the
+	// registered ones (see hint.Register)
+	Hints []hint.Function
+}
+
+// Server is a proving service
+type Server struct {
+	cfg Config
+	log zerolog.Logger
+
+	lock     sync.RWMutex
+	circuits map[string]*Circuit
+	jobs     map[string]*Job
+	nextJob  uint64
+
+	queue  chan *Job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Circuit is a compiled circuit registered with its proving key
+type Circuit struct {
+	ID      string
+	Backend backend.ID
+	CCS     frontend.CompiledConstraintSystem
+
+	groth16PK groth16.ProvingKey
+	plonkPK   plonk.ProvingKey
+}
+
+// New returns a Server and starts its workers
+func New(cfg Config) *Server {
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 16
+	}
+	if cfg.NbTasks <= 0 {
+		cfg.NbTasks = runtime.NumCPU() / cfg.MaxConcurrency
+		if cfg.NbTasks < 1 {
+			cfg.NbTasks = 1
+		}
+	}
+	if cfg.JobTTL <= 0 {
+		cfg.JobTTL = 10 * time.Minute
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		log:      logger.Logger().With().Str("service", "gnarkd").Logger(),
+		circuits: make(map[string]*Circuit),
+		jobs:     make(map[string]*Job),
+		queue:    make(chan *Job, cfg.QueueSize),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	s.wg.Add(cfg.MaxConcurrency)
+	for i := 0; i < cfg.MaxConcurrency; i++ {
+		go s.worker()
+	}
+
+	return s
+}
+
+// Close stops the workers; queued and running jobs fail with ErrClosed or the
+// context error.
+func (s *Server) Close() {
+	// no job is queued once the server is cancelled, see Submit
+	s.lock.Lock()
+	s.cancel()
+	s.lock.Unlock()
+	s.wg.Wait()
+
+	// fail the jobs the workers left in the queue
+	for {
+		select {
+		case job := <-s.queue:
+			s.finish(job, nil, nil, ErrClosed)
+		default:
+			return
+		}
+	}
+}
+
+// Register registers a compiled circuit with its proving key under id,
+// replacing any circuit with the same id.
+//
+// pk must be a groth16.ProvingKey or a plonk.ProvingKey with its KZG SRS set.
+func (s *Server) Register(id string, ccs frontend.CompiledConstraintSystem, pk interface{}) error {
+	if id == "" {
+		return errors.New("empty circuit id")
+	}
+	c := &Circuit{ID: id, CCS: ccs}
+	switch pk := pk.(type) {
+	case groth16.ProvingKey:
+		c.Backend = backend.GROTH16
+		c.groth16PK = pk
+	case plonk.ProvingKey:
+		c.Backend = backend.PLONK
+		c.plonkPK = pk
+	default:
+		return fmt.Errorf("unsupported proving key type %T", pk)
+	}
+
+	s.lock.Lock()
+	s.circuits[id] = c
+	s.lock.Unlock()
+
+	s.log.Info().Str("circuit", id).Str("backend", c.Backend.String()).Str("curve", ccs.CurveID().String()).
+		Int("nbConstraints", ccs.GetNbConstraints()).Msg("circuit registered")
+	return nil
+}
+
+// Load reads a serialized compiled circuit and proving key and registers them under id.
+// srs optionally replaces the KZG SRS of the proving key of plonk circuits, and is ignored for groth16.
+func (s *Server) Load(id string, backendID backend.ID, curveID ecc.ID, ccs, pk, srs io.Reader) error {
+	var (
+		_ccs frontend.CompiledConstraintSystem
+		_pk  io.ReaderFrom
+	)
+	switch backendID {
+	case backend.GROTH16:
+		_ccs = groth16.NewCS(curveID)
+		_pk = groth16.NewProvingKey(curveID)
+	case backend.PLONK:
+		_ccs = plonk.NewCS(curveID)
+		_pk = plonk.NewProvingKey(curveID)
+	default:
+		return fmt.Errorf("unsupported backend %s", backendID)
+	}
+
+	if _, err := _ccs.ReadFrom(ccs); err != nil {
+		return fmt.Errorf("read constraint system: %w", err)
+	}
+	if _, err := _pk.ReadFrom(pk); err != nil {
+		return fmt.Errorf("read proving key: %w", err)
+	}
+	if backendID == backend.PLONK && srs != nil {
+		_srs := plonk.NewSRS(curveID)
+		if _, err := _srs.ReadFrom(srs); err != nil {
+			return fmt.Errorf("read kzg srs: %w", err)
+		}
+		if err := _pk.(plonk.ProvingKey).InitKZG(_srs); err != nil {
+			return err
+		}
+	}
+
+	return s.Register(id, _ccs, _pk)
+}
+
+// Circuits returns the registered circuits, sorted by ID
+func (s *Server) Circuits() []*Circuit {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	circuits := make([]*Circuit, 0, len(s.circuits))
+	for _, c := range s.circuits {
+		circuits = append(circuits, c)
+	}
+	sort.Slice(circuits, func(i, j int) bool { return circuits[i].ID < circuits[j].ID })
+	return circuits
+}
+
+// Circuit returns the circuit registered under id
+func (s *Server) Circuit(id string) (*Circuit, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	c, ok := s.circuits[id]
+	return c, ok
+}
+
+// JobStatus is the status of a Job
+type JobStatus string
+
+const (
+	StatusQueued  JobStatus = "queued"
+	StatusRunning JobStatus = "running"
+	StatusDone    JobStatus = "done"
+	StatusFailed  JobStatus = "failed"
+)
+
+// Job is a proof request
+type Job struct {
+	ID      string
+	Circuit *Circuit
+
+	ctx     context.Context
+	witness *witness.Witness
+	done    chan struct{}
+
+	lock          sync.Mutex
+	status        JobStatus
+	proof         io.WriterTo
+	publicWitness *witness.Witness
+	err           error
+}
+
+// Status returns the status of the job
+func (job *Job) Status() JobStatus {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.status
+}
+
+// Done returns a channel closed when the job is done or failed
+func (job *Job) Done() <-chan struct{} {
+	return job.done
+}
+
+// Result returns the proof and the public witness of a done job, or the error of a failed job.
+// It must be called after Done is closed.
+func (job *Job) Result() (proof io.WriterTo, publicWitness *witness.Witness, err error) {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.proof, job.publicWitness, job.err
+}
+
+func (job *Job) setStatus(status JobStatus) {
+	job.lock.Lock()
+	job.status = status
+	job.lock.Unlock()
+}
+
+// Submit queues a proof of the full witness w for the circuit registered under circuitID.
+//
+// The proof is cancelled when ctx is done. It returns ErrQueueFull if the queue is full.
+func (s *Server) Submit(ctx context.Context, circuitID string, w *witness.Witness) (*Job, error) {
+	c, ok := s.Circuit(circuitID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCircuit, circuitID)
+	}
+	if w.CurveID != c.CCS.CurveID() {
+		return nil, fmt.Errorf("%w: witness curve is %s, circuit curve is %s", witness.ErrInvalidWitness, w.CurveID, c.CCS.CurveID())
+	}
+	if w.Schema == nil {
+		// the public witness is extracted with the circuit schema
+		_w := *w
+		_w.Schema = c.CCS.GetSchema()
+		w = &_w
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.ctx.Err() != nil {
+		return nil, ErrClosed
+	}
+	s.nextJob++
+	job := &Job{
+		ID:      strconv.FormatUint(s.nextJob, 10),
+		Circuit: c,
+		ctx:     ctx,
+		witness: w,
+		done:    make(chan struct{}),
+		status:  StatusQueued,
+	}
+
+	select {
+	case s.queue <- job:
+		s.jobs[job.ID] = job
+		return job, nil
+	default:
+		return nil, ErrQueueFull
+	}
+}
+
+// Job returns the job with the given ID
+func (s *Server) Job(id string) (*Job, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownJob, id)
+	}
+	return job, nil
+}
+
+// forget removes a job from the jobs map, once its result is delivered or expired
+func (s *Server) forget(id string) {
+	s.lock.Lock()
+	delete(s.jobs, id)
+	s.lock.Unlock()
+}
+
+func (s *Server) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			// Close fails the queued jobs
+			return
+		case job := <-s.queue:
+			if s.ctx.Err() != nil {
+				// select doesn't favor the cancellation over a queued job
+				s.finish(job, nil, nil, ErrClosed)
+				continue
+			}
+			s.run(job)
+		}
+	}
+}
+
+func (s *Server) run(job *Job) {
+	job.setStatus(StatusRunning)
+	start := time.Now()
+
+	// the proof is cancelled when the job context or the server is done
+	ctx, cancel := context.WithCancel(job.ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	opts := []backend.ProverOption{
+		backend.WithContext(ctx),
+		backend.WithNbTasks(s.cfg.NbTasks),
+	}
+	if len(s.cfg.Hints) != 0 {
+		opts = append(opts, backend.WithHints(s.cfg.Hints...))
+	}
+
+	var (
+		proof io.WriterTo
+		err   error
+	)
+	switch job.Circuit.Backend {
+	case backend.GROTH16:
+		proof, err = groth16.Prove(job.Circuit.CCS, job.Circuit.groth16PK, job.witness, opts...)
+	case backend.PLONK:
+		proof, err = plonk.Prove(job.Circuit.CCS, job.Circuit.plonkPK, job.witness, opts...)
+	}
+
+	var publicWitness *witness.Witness
+	if err == nil {
+		publicWitness, err = job.witness.Public()
+	}
+
+	log := s.log.With().Str("circuit", job.Circuit.ID).Str("job", job.ID).Dur("took", time.Since(start)).Logger()
+	if err != nil {
+		log.Warn().Err(err).Msg("proof failed")
+	} else {
+		log.Info().Msg("proof done")
+	}
+
+	s.finish(job, proof, publicWitness, err)
+}
+
+func (s *Server) finish(job *Job, proof io.WriterTo, publicWitness *witness.Witness, err error) {
+	job.lock.Lock()
+	job.proof, job.publicWitness, job.err = proof, publicWitness, err
+	if err != nil {
+		job.status = StatusFailed
+	} else {
+		job.status = StatusDone
+	}
+	job.witness = nil
+	job.lock.Unlock()
+	close(job.done)
+
+	// results that are not fetched expire
+	time.AfterFunc(s.cfg.JobTTL, func() { s.forget(job.ID) })
+}
@@ -0,0 +1,338 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *cubicCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestProveGroth16(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	s, url := newTestServer(t, Config{})
+	upload(t, url+"/v1/circuits/cubic", backend.GROTH16, ecc.BN254, ccs, pk, nil)
+
+	for _, contentType := range []string{"application/json", "application/octet-stream"} {
+		res := proveHTTP(t, url+"/v1/circuits/cubic/prove", contentType, &cubicCircuit{X: 3, Y: 35})
+		assert.Equal(StatusDone, res.Status, res.Error)
+
+		proof := groth16.NewProof(ecc.BN254)
+		_, err = proof.ReadFrom(bytes.NewReader(res.Proof))
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, publicWitness(t, ccs, res)))
+	}
+	_, err = s.Job("1")
+	assert.Error(err, "synchronous jobs must be forgotten once delivered")
+}
+
+func TestProvePlonk(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, scs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	srs, err := kzg.NewSRS(ecc.NextPowerOfTwo(uint64(ccs.GetNbConstraints()))+3, big.NewInt(42))
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs)
+	assert.NoError(err)
+
+	_, url := newTestServer(t, Config{})
+	upload(t, url+"/v1/circuits/cubic", backend.PLONK, ecc.BN254, ccs, pk, srs)
+
+	resp, err := http.Get(url + "/v1/circuits/cubic")
+	assert.NoError(err)
+	var info CircuitInfo
+	assert.NoError(json.NewDecoder(resp.Body).Decode(&info))
+	resp.Body.Close()
+	assert.Equal(CircuitInfo{ID: "cubic", Backend: "plonk", Curve: ecc.BN254.String(), NbConstraints: ccs.GetNbConstraints(), NbPublic: 1, NbSecret: 1}, info)
+
+	res := proveHTTP(t, url+"/v1/circuits/cubic/prove", "application/json", &cubicCircuit{X: 3, Y: 35})
+	assert.Equal(StatusDone, res.Status, res.Error)
+
+	proof := plonk.NewProof(ecc.BN254)
+	_, err = proof.ReadFrom(bytes.NewReader(res.Proof))
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, publicWitness(t, ccs, res)))
+
+	// an invalid witness fails the proof
+	res = proveHTTP(t, url+"/v1/circuits/cubic/prove", "application/json", &cubicCircuit{X: 3, Y: 36})
+	assert.Equal(StatusFailed, res.Status)
+	assert.NotEmpty(res.Error)
+}
+
+func TestUnknownCircuit(t *testing.T) {
+	assert := require.New(t)
+	_, url := newTestServer(t, Config{})
+
+	resp, err := http.Post(url+"/v1/circuits/nope/prove", "application/json", bytes.NewReader([]byte("{}")))
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(url + "/v1/jobs/42")
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+// hintStarted and hintRelease are set by TestQueueFull
+var hintStarted, hintRelease chan struct{}
+
+// blockingHint returns its input once hintRelease is closed
+func blockingHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	hintStarted <- struct{}{}
+	<-hintRelease
+	results[0].Set(inputs[0])
+	return nil
+}
+
+type blockingCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *blockingCircuit) Define(api frontend.API) error {
+	r, err := api.Compiler().NewHint(blockingHint, 1, c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(api.Mul(r[0], c.X), c.Y)
+	return nil
+}
+
+func TestQueueFull(t *testing.T) {
+	assert := require.New(t)
+	hintStarted, hintRelease = make(chan struct{}, 2), make(chan struct{})
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &blockingCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	s, url := newTestServer(t, Config{MaxConcurrency: 1, QueueSize: 1, Hints: []hint.Function{blockingHint}})
+	assert.NoError(s.Register("blocking", ccs, pk))
+
+	submit := func() (int, JobResponse) {
+		w, err := frontend.NewWitness(&blockingCircuit{X: 3, Y: 9}, ecc.BN254)
+		assert.NoError(err)
+		data, err := w.MarshalBinary()
+		assert.NoError(err)
+		resp, err := http.Post(url+"/v1/circuits/blocking/prove?async", "application/octet-stream", bytes.NewReader(data))
+		assert.NoError(err)
+		defer resp.Body.Close()
+		var res JobResponse
+		assert.NoError(json.NewDecoder(resp.Body).Decode(&res))
+		return resp.StatusCode, res
+	}
+
+	// the first job runs and blocks in the hint, the second one waits in the queue
+	code, first := submit()
+	assert.Equal(http.StatusAccepted, code)
+	<-hintStarted
+	code, second := submit()
+	assert.Equal(http.StatusAccepted, code)
+	assert.Equal(StatusQueued, second.Status)
+
+	// the third one is rejected
+	code, _ = submit()
+	assert.Equal(http.StatusServiceUnavailable, code)
+
+	close(hintRelease)
+	for _, job := range []JobResponse{first, second} {
+		res := waitJob(t, url, job.ID)
+		assert.Equal(StatusDone, res.Status, res.Error)
+
+		proof := groth16.NewProof(ecc.BN254)
+		_, err = proof.ReadFrom(bytes.NewReader(res.Proof))
+		assert.NoError(err)
+		assert.NoError(groth16.Verify(proof, vk, publicWitness(t, ccs, res)))
+	}
+}
+
+func TestJobExpires(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &cubicCircuit{})
+	assert.NoError(err)
+	pk, _, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	s, _ := newTestServer(t, Config{JobTTL: 10 * time.Millisecond})
+	assert.NoError(s.Register("cubic", ccs, pk))
+
+	w, err := frontend.NewWitness(&cubicCircuit{X: 3, Y: 35}, ecc.BN254)
+	assert.NoError(err)
+	job, err := s.Submit(context.Background(), "cubic", w)
+	assert.NoError(err)
+	<-job.Done()
+
+	// the result is never fetched
+	assert.Eventually(func() bool {
+		_, err := s.Job(job.ID)
+		return errors.Is(err, ErrUnknownJob)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCloseFailsQueuedJobs(t *testing.T) {
+	assert := require.New(t)
+	hintStarted, hintRelease = make(chan struct{}, 2), make(chan struct{})
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &blockingCircuit{})
+	assert.NoError(err)
+	pk, _, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	s, _ := newTestServer(t, Config{MaxConcurrency: 1, QueueSize: 1, Hints: []hint.Function{blockingHint}})
+	assert.NoError(s.Register("blocking", ccs, pk))
+
+	w, err := frontend.NewWitness(&blockingCircuit{X: 3, Y: 9}, ecc.BN254)
+	assert.NoError(err)
+	running, err := s.Submit(context.Background(), "blocking", w)
+	assert.NoError(err)
+	<-hintStarted
+	queued, err := s.Submit(context.Background(), "blocking", w)
+	assert.NoError(err)
+
+	// the running job blocks the worker until the server is closed
+	go s.Close()
+	assert.Eventually(func() bool {
+		_, err := s.Submit(context.Background(), "blocking", w)
+		return errors.Is(err, ErrClosed)
+	}, 5*time.Second, time.Millisecond)
+	close(hintRelease)
+
+	// the running job may complete before it sees the cancellation, the queued one fails
+	for _, job := range []*Job{running, queued} {
+		select {
+		case <-job.Done():
+		case <-time.After(10 * time.Second):
+			t.Fatalf("job %s is not done", job.ID)
+		}
+	}
+	assert.Equal(StatusFailed, queued.Status())
+}
+
+func newTestServer(t *testing.T, cfg Config) (*Server, string) {
+	s := New(cfg)
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Close()
+	})
+	return s, ts.URL
+}
+
+// upload registers a circuit with a multipart PUT request
+func upload(t *testing.T, url string, backendID backend.ID, curveID ecc.ID, ccs, pk, srs io.WriterTo) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("backend", backendID.String()))
+	require.NoError(t, mw.WriteField("curve", curveID.String()))
+	files := map[string]io.WriterTo{"ccs": ccs, "pk": pk}
+	if srs != nil {
+		files["srs"] = srs
+	}
+	for name, v := range files {
+		fw, err := mw.CreateFormFile(name, name)
+		require.NoError(t, err)
+		_, err = v.WriteTo(fw)
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+
+	req, err := http.NewRequest(http.MethodPut, url, &body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(resp.Body)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(msg))
+}
+
+// proveHTTP sends a synchronous prove request with the full witness of assignment
+func proveHTTP(t *testing.T, url, contentType string, assignment frontend.Circuit) JobResponse {
+	w, err := frontend.NewWitness(assignment, ecc.BN254)
+	require.NoError(t, err)
+	var data []byte
+	if contentType == "application/json" {
+		data, err = w.MarshalJSON()
+	} else {
+		data, err = w.MarshalBinary()
+	}
+	require.NoError(t, err)
+
+	resp, err := http.Post(url, contentType, bytes.NewReader(data))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var res JobResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	return res
+}
+
+// waitJob polls an asynchronous job until it is done or failed
+func waitJob(t *testing.T, url, id string) JobResponse {
+	for i := 0; i < 600; i++ {
+		resp, err := http.Get(fmt.Sprintf("%s/v1/jobs/%s", url, id))
+		require.NoError(t, err)
+		var res JobResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		resp.Body.Close()
+		if res.Status == StatusDone || res.Status == StatusFailed {
+			return res
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("job %s timed out", id)
+	return JobResponse{}
+}
+
+// publicWitness decodes the binary public witness of res, and checks it matches its JSON encoding
+func publicWitness(t *testing.T, ccs frontend.CompiledConstraintSystem, res JobResponse) *witness.Witness {
+	fromBinary, err := witness.New(ccs.CurveID(), ccs.GetSchema())
+	require.NoError(t, err)
+	require.NoError(t, fromBinary.UnmarshalBinary(res.PublicWitness))
+
+	fromJSON, err := witness.New(ccs.CurveID(), ccs.GetSchema())
+	require.NoError(t, err)
+	require.NoError(t, fromJSON.UnmarshalJSON(res.PublicWitnessJSON))
+	require.Equal(t, fromBinary.Vector, fromJSON.Vector)
+
+	return fromBinary
+}
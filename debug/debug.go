@@ -18,14 +18,10 @@ func WriteStack(sbb *strings.Builder, forceClean ...bool) {
 	// we stop when func name == Define as it is where the gnark circuit code should start
 
 	// Ask runtime.Callers for up to 10 pcs
-	pc := make([]uintptr, 10)
-	n := runtime.Callers(3, pc)
-	if n == 0 {
-		// No pcs available. Stop now.
-		// This can happen if the first argument to runtime.Callers is large.
+	pc := Callers(2, 10)
+	if len(pc) == 0 {
 		return
 	}
-	pc = pc[:n] // pass only valid pcs to runtime.CallersFrames
 	frames := runtime.CallersFrames(pc)
 	// Loop to get frames.
 	// A fixed number of pcs can expand to an indefinite number of Frames.
@@ -66,3 +62,15 @@ func WriteStack(sbb *strings.Builder, forceClean ...bool) {
 		}
 	}
 }
+
+// Callers returns up to maxDepth program counters of the calling goroutine's stack,
+// skipping the first skip frames (0 identifies the caller of Callers).
+//
+// The result is meant to be resolved with runtime.CallersFrames; it is shared by the debug
+// stacks of the constraint systems and by the compile profiles (see frontend.WithProfile).
+func Callers(skip, maxDepth int) []uintptr {
+	pc := make([]uintptr, maxDepth)
+	n := runtime.Callers(skip+2, pc)
+	// n == 0 can happen if skip is large.
+	return pc[:n] // pass only valid pcs to runtime.CallersFrames
+}
@@ -33,6 +33,10 @@ type CompiledConstraintSystem interface {
 	// IsSolved returns nil if given witness solves the constraint system and error otherwise
 	IsSolved(witness *witness.Witness, opts ...backend.ProverOption) error
 
+	// SolveWires solves the constraint system with the given witness and returns the complete
+	// solved wire vector, labelled with the input names and the hints provenance
+	SolveWires(witness *witness.Witness, opts ...backend.ProverOption) (*compiled.Solution, error)
+
 	// GetNbVariables return number of internal, secret and public Variables
 	GetNbVariables() (internal, secret, public int)
 	GetNbConstraints() int
@@ -48,4 +52,8 @@ type CompiledConstraintSystem interface {
 
 	// GetConstraints return a human readable representation of the constraints
 	GetConstraints() [][]string
+
+	// Digest returns a deterministic digest of the constraints, coefficients, schema and hints IDs
+	// of the circuit. Two constraint systems with the same digest are interchangeable for the backends.
+	Digest() [32]byte
 }
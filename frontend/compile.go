@@ -3,6 +3,7 @@ package frontend
 import (
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -125,6 +126,7 @@ type CompileOption func(opt *CompileConfig) error
 type CompileConfig struct {
 	Capacity                  int
 	IgnoreUnconstrainedInputs bool
+	Profile                   io.Writer
 }
 
 // WithCapacity is a compile option that specifies the estimated capacity needed
@@ -151,6 +153,22 @@ func IgnoreUnconstrainedInputs() CompileOption {
 	}
 }
 
+// WithProfile is a compile option that attributes every constraint, internal variable and
+// coefficient to the call stack that created it. When the circuit is compiled, the result is
+// written to w as a pprof profile (profile.proto), to be opened with go tool pprof:
+//
+//	f, _ := os.Create("profile.proto")
+//	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &circuit, frontend.WithProfile(f))
+//	// go tool pprof -top profile.proto
+//
+// Capturing the stacks slows down the compilation; this option is meant for circuit development.
+func WithProfile(w io.Writer) CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.Profile = w
+		return nil
+	}
+}
+
 var tVariable reflect.Type
 
 func init() {
@@ -0,0 +1,205 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compiled
+
+import (
+	"encoding/binary"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// WriteDigest writes a deterministic encoding of the constraint system to w, to be hashed
+// by the curve typed constraint systems (which append their coefficients).
+//
+// It covers the curve, the schema, the number of variables, the constraints and the hints;
+// logs, debug info, counters and levels are not part of it.
+func (r1cs *R1CS) WriteDigest(w io.Writer) error {
+	dw := digestWriter{w: w}
+	r1cs.ConstraintSystem.writeDigest(&dw)
+
+	dw.writeInt(len(r1cs.Constraints))
+	for _, r1c := range r1cs.Constraints {
+		dw.writeLinearExpression(r1c.L)
+		dw.writeLinearExpression(r1c.R)
+		dw.writeLinearExpression(r1c.O)
+	}
+
+	return dw.err
+}
+
+// WriteDigest writes a deterministic encoding of the constraint system to w, to be hashed
+// by the curve typed constraint systems (which append their coefficients).
+//
+// It covers the curve, the schema, the number of variables, the constraints and the hints;
+// logs, debug info, counters and levels are not part of it.
+func (cs *SparseR1CS) WriteDigest(w io.Writer) error {
+	dw := digestWriter{w: w}
+	cs.ConstraintSystem.writeDigest(&dw)
+
+	dw.writeInt(len(cs.Constraints))
+	for _, c := range cs.Constraints {
+		dw.writeTerm(c.L)
+		dw.writeTerm(c.R)
+		dw.writeTerm(c.M[0])
+		dw.writeTerm(c.M[1])
+		dw.writeTerm(c.O)
+		dw.writeInt(c.K)
+	}
+
+	return dw.err
+}
+
+func (cs *ConstraintSystem) writeDigest(dw *digestWriter) {
+	dw.writeUint64(uint64(cs.CurveID))
+
+	dw.writeInt(cs.NbPublicVariables)
+	dw.writeInt(cs.NbSecretVariables)
+	dw.writeInt(cs.NbInternalVariables)
+
+	// schema
+	if cs.Schema == nil {
+		dw.writeInt(-1)
+	} else {
+		dw.writeInt(cs.Schema.NbPublic)
+		dw.writeInt(cs.Schema.NbSecret)
+		dw.writeFields(cs.Schema.Fields)
+	}
+	dw.writeStrings(cs.Public)
+	dw.writeStrings(cs.Secret)
+
+	// hints, sorted by wire ID; several wires may point to the same hint
+	wires := make([]int, 0, len(cs.MHints))
+	for wireID := range cs.MHints {
+		wires = append(wires, wireID)
+	}
+	sort.Ints(wires)
+
+	modulus := cs.CurveID.Info().Fr.Modulus()
+	dw.writeInt(len(wires))
+	for _, wireID := range wires {
+		h := cs.MHints[wireID]
+		dw.writeInt(wireID)
+		dw.writeUint64(uint64(h.ID))
+		dw.writeInt(len(h.Inputs))
+		for _, in := range h.Inputs {
+			switch t := in.(type) {
+			case LinearExpression:
+				dw.writeUint64(0)
+				dw.writeLinearExpression(t)
+			case Term:
+				dw.writeUint64(1)
+				dw.writeTerm(t)
+			default:
+				// constant inputs are reduced by the solver
+				v := utils.FromInterface(t)
+				v.Mod(&v, modulus)
+				dw.writeUint64(2)
+				dw.writeBigInt(&v)
+			}
+		}
+		dw.writeInts(h.Wires)
+	}
+
+	// hint dependencies
+	ids := make([]hint.ID, 0, len(cs.MHintsDependencies))
+	for id := range cs.MHintsDependencies {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	dw.writeInt(len(ids))
+	for _, id := range ids {
+		dw.writeUint64(uint64(id))
+		dw.writeString(cs.MHintsDependencies[id])
+	}
+}
+
+// digestWriter writes length prefixed values to w and keeps the first error
+type digestWriter struct {
+	w   io.Writer
+	err error
+	buf [8]byte
+}
+
+func (dw *digestWriter) write(b []byte) {
+	if dw.err != nil {
+		return
+	}
+	_, dw.err = dw.w.Write(b)
+}
+
+func (dw *digestWriter) writeUint64(v uint64) {
+	binary.BigEndian.PutUint64(dw.buf[:], v)
+	dw.write(dw.buf[:])
+}
+
+func (dw *digestWriter) writeInt(v int) {
+	dw.writeUint64(uint64(v))
+}
+
+func (dw *digestWriter) writeInts(v []int) {
+	dw.writeInt(len(v))
+	for _, i := range v {
+		dw.writeInt(i)
+	}
+}
+
+func (dw *digestWriter) writeString(s string) {
+	dw.writeInt(len(s))
+	dw.write([]byte(s))
+}
+
+func (dw *digestWriter) writeStrings(s []string) {
+	dw.writeInt(len(s))
+	for _, ss := range s {
+		dw.writeString(ss)
+	}
+}
+
+func (dw *digestWriter) writeBigInt(v *big.Int) {
+	b := v.Bytes()
+	dw.writeInt(len(b))
+	dw.write(b)
+}
+
+func (dw *digestWriter) writeTerm(t Term) {
+	dw.writeUint64(uint64(t))
+}
+
+func (dw *digestWriter) writeLinearExpression(l LinearExpression) {
+	dw.writeInt(len(l))
+	for _, t := range l {
+		dw.writeTerm(t)
+	}
+}
+
+func (dw *digestWriter) writeFields(fields []schema.Field) {
+	dw.writeInt(len(fields))
+	for _, f := range fields {
+		dw.writeString(f.Name)
+		dw.writeString(f.NameTag)
+		dw.writeUint64(uint64(f.Visibility))
+		dw.writeUint64(uint64(f.Type))
+		dw.writeInt(f.ArraySize)
+		dw.writeFields(f.SubFields)
+	}
+}
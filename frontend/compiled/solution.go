@@ -0,0 +1,175 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// Solution is the complete solved wire vector of a constraint system.
+//
+// Wires are ordered by wire ID (public | secret | internal) and labelled with the input
+// names from the schema, or with the hint that computed them. It marshals to JSON so that
+// the internal values of two circuits (or two backends) can be diffed.
+type Solution struct {
+	CurveID ecc.ID
+	Wires   []SolvedWire
+}
+
+// SolvedWire is a wire of a Solution
+type SolvedWire struct {
+	ID         int
+	Visibility schema.Visibility
+	Name       string // input name for public and secret wires
+	Hint       string // name of the hint function, for internal wires computed by a hint
+	HintOutput int    // index of the wire in the hint outputs, if Hint is set
+	Value      big.Int
+}
+
+// NewSolution labels the solved values of the wires of cs.
+// values must be ordered by wire ID and contain all the wires.
+func (cs *ConstraintSystem) NewSolution(values []big.Int) *Solution {
+	nbInputs := cs.NbPublicVariables + cs.NbSecretVariables
+	s := &Solution{
+		CurveID: cs.CurveID,
+		Wires:   make([]SolvedWire, len(values)),
+	}
+	for i := range values {
+		w := &s.Wires[i]
+		w.ID = i
+		w.Value.Set(&values[i])
+		switch {
+		case i < cs.NbPublicVariables:
+			w.Visibility = schema.Public
+			w.Name = cs.Public[i]
+		case i < nbInputs:
+			w.Visibility = schema.Secret
+			w.Name = cs.Secret[i-cs.NbPublicVariables]
+		default:
+			w.Visibility = schema.Internal
+		}
+	}
+
+	// hint provenance; all the outputs of a hint point to the same *Hint
+	for _, h := range cs.MHints {
+		name := cs.MHintsDependencies[h.ID]
+		for i, wireID := range h.Wires {
+			if wireID < len(s.Wires) {
+				s.Wires[wireID].Hint = name
+				s.Wires[wireID].HintOutput = i
+			}
+		}
+	}
+
+	return s
+}
+
+// Lookup returns the wire of the input with the given name
+func (s *Solution) Lookup(name string) (*SolvedWire, bool) {
+	for i := range s.Wires {
+		if s.Wires[i].Visibility != schema.Internal && s.Wires[i].Name == name {
+			return &s.Wires[i], true
+		}
+	}
+	return nil, false
+}
+
+type solutionJSON struct {
+	Curve string           `json:"curve"`
+	Wires []solvedWireJSON `json:"wires"`
+}
+
+// values are encoded as decimal strings, as they don't fit in a JSON number
+type solvedWireJSON struct {
+	ID         int    `json:"id"`
+	Visibility string `json:"visibility"`
+	Name       string `json:"name,omitempty"`
+	Hint       string `json:"hint,omitempty"`
+	HintOutput *int   `json:"hintOutput,omitempty"`
+	Value      string `json:"value"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (s *Solution) MarshalJSON() ([]byte, error) {
+	r := solutionJSON{
+		Curve: s.CurveID.String(),
+		Wires: make([]solvedWireJSON, len(s.Wires)),
+	}
+	for i, w := range s.Wires {
+		r.Wires[i] = solvedWireJSON{
+			ID:         w.ID,
+			Visibility: w.Visibility.String(),
+			Name:       w.Name,
+			Hint:       w.Hint,
+			Value:      w.Value.String(),
+		}
+		if w.Hint != "" {
+			hintOutput := w.HintOutput
+			r.Wires[i].HintOutput = &hintOutput
+		}
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *Solution) UnmarshalJSON(data []byte) error {
+	var r solutionJSON
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+
+	s.CurveID = ecc.UNKNOWN
+	for _, id := range ecc.Implemented() {
+		if id.String() == r.Curve {
+			s.CurveID = id
+		}
+	}
+	if s.CurveID == ecc.UNKNOWN {
+		return fmt.Errorf("unknown curve %q", r.Curve)
+	}
+
+	s.Wires = make([]SolvedWire, len(r.Wires))
+	for i, w := range r.Wires {
+		s.Wires[i] = SolvedWire{
+			ID:   w.ID,
+			Name: w.Name,
+			Hint: w.Hint,
+		}
+		if w.HintOutput != nil {
+			s.Wires[i].HintOutput = *w.HintOutput
+		}
+		switch w.Visibility {
+		case schema.Public.String():
+			s.Wires[i].Visibility = schema.Public
+		case schema.Secret.String():
+			s.Wires[i].Visibility = schema.Secret
+		case schema.Internal.String():
+			s.Wires[i].Visibility = schema.Internal
+		default:
+			return fmt.Errorf("wire %d: invalid visibility %q", w.ID, w.Visibility)
+		}
+		if _, ok := s.Wires[i].Value.SetString(w.Value, 10); !ok {
+			return fmt.Errorf("wire %d: invalid value %q", w.ID, w.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package compiled_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/compiled"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+type solutionCircuit struct {
+	X [2]frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *solutionCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X[0], c.X[1]), c.Y)
+	api.ToBinary(c.X[0], 4)
+	return nil
+}
+
+func TestSolveWires(t *testing.T) {
+	for name, newBuilder := range map[string]frontend.NewBuilder{"r1cs": r1cs.NewBuilder, "scs": scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254, newBuilder, &solutionCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := frontend.NewWitness(&solutionCircuit{X: [2]frontend.Variable{5, 7}, Y: 35}, ecc.BN254)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		solution, err := ccs.SolveWires(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		internal, secret, public := ccs.GetNbVariables()
+		if len(solution.Wires) != internal+secret+public {
+			t.Fatalf("%s: expected %d wires, got %d", name, internal+secret+public, len(solution.Wires))
+		}
+
+		// inputs are labelled with their schema names
+		for input, expected := range map[string]int64{"X_0": 5, "X_1": 7, "Y": 35} {
+			wire, ok := solution.Lookup(input)
+			if !ok {
+				t.Fatalf("%s: input %s not found", name, input)
+			}
+			if wire.Value.Int64() != expected {
+				t.Fatalf("%s: %s == %s, expected %d", name, input, wire.Value.String(), expected)
+			}
+		}
+
+		// the bits of X[0] are computed by a hint
+		var bits []int64
+		for _, wire := range solution.Wires {
+			if wire.Hint != "" {
+				if wire.Visibility != schema.Internal || wire.HintOutput != len(bits) {
+					t.Fatalf("%s: invalid hint wire %#v", name, wire)
+				}
+				bits = append(bits, wire.Value.Int64())
+			}
+		}
+		if !reflect.DeepEqual(bits, []int64{1, 0, 1, 0}) {
+			t.Fatalf("%s: expected the bits of 5, got %v", name, bits)
+		}
+
+		// JSON round trip
+		data, err := json.Marshal(solution)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded compiled.Solution
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(solution, &decoded) {
+			t.Fatalf("%s: JSON round trip failed", name)
+		}
+
+		// invalid witness
+		w, err = frontend.NewWitness(&solutionCircuit{X: [2]frontend.Variable{5, 7}, Y: 36}, ecc.BN254)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ccs.SolveWires(w); err == nil {
+			t.Fatalf("%s: invalid witness solved", name)
+		}
+	}
+}
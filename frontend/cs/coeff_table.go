@@ -12,6 +12,9 @@ type CoeffTable struct {
 	Coeffs         []big.Int      // list of unique coefficients.
 	CoeffsIDsLarge map[string]int // map to check existence of a coefficient (key = coeff.Bytes())
 	CoeffsIDsInt64 map[int64]int  // map to check existence of a coefficient (key = int64 value)
+
+	// Profile, if set, records the call stacks that create new coefficients
+	Profile *Profile
 }
 
 func NewCoeffTable() CoeffTable {
@@ -58,6 +61,7 @@ func (t *CoeffTable) CoeffID(v *big.Int) int {
 	resID := len(t.Coeffs)
 	t.Coeffs = append(t.Coeffs, bCopy)
 	t.CoeffsIDsLarge[key] = resID
+	t.Profile.RecordCoefficient()
 	return resID
 }
 
@@ -70,6 +74,7 @@ func (t *CoeffTable) coeffID64(v int64) int {
 		resID := len(t.Coeffs)
 		t.Coeffs = append(t.Coeffs, bCopy)
 		t.CoeffsIDsInt64[v] = resID
+		t.Profile.RecordCoefficient()
 		return resID
 	}
 }
@@ -0,0 +1,357 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cs
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/consensys/gnark/debug"
+)
+
+// maximum number of frames captured per event
+const profileMaxDepth = 64
+
+// sample value indexes, in the order of the profile sample types
+const (
+	profileConstraints = iota
+	profileVariables
+	profileCoefficients
+	profileNbValues
+)
+
+// Profile attributes the constraints, internal variables and coefficients created by a builder
+// to the call stacks that created them. Its WriteTo method outputs a gzipped pprof protobuf
+// (profile.proto) that can be opened with go tool pprof.
+//
+// A nil *Profile is valid and records nothing, so that the builders can call it unconditionally.
+type Profile struct {
+	samples map[string]*profileSample // keyed by the encoded program counters of the stack
+	order   []*profileSample          // samples in insertion order, for a deterministic output
+	key     []byte
+}
+
+type profileSample struct {
+	pc     []uintptr
+	values [profileNbValues]int64
+}
+
+// NewProfile returns an empty profile
+func NewProfile() *Profile {
+	return &Profile{samples: make(map[string]*profileSample)}
+}
+
+// RecordConstraint attributes a new constraint to the caller's stack
+func (p *Profile) RecordConstraint() {
+	p.record(profileConstraints)
+}
+
+// RecordVariable attributes a new internal variable to the caller's stack
+func (p *Profile) RecordVariable() {
+	p.record(profileVariables)
+}
+
+// RecordCoefficient attributes a new coefficient to the caller's stack
+func (p *Profile) RecordCoefficient() {
+	p.record(profileCoefficients)
+}
+
+// NbConstraints returns the number of constraints recorded in the profile
+func (p *Profile) NbConstraints() int {
+	return p.total(profileConstraints)
+}
+
+// NbVariables returns the number of internal variables recorded in the profile
+func (p *Profile) NbVariables() int {
+	return p.total(profileVariables)
+}
+
+// NbCoefficients returns the number of coefficients recorded in the profile
+func (p *Profile) NbCoefficients() int {
+	return p.total(profileCoefficients)
+}
+
+func (p *Profile) total(i int) int {
+	if p == nil {
+		return 0
+	}
+	n := 0
+	for _, s := range p.order {
+		n += int(s.values[i])
+	}
+	return n
+}
+
+func (p *Profile) record(i int) {
+	if p == nil {
+		return
+	}
+	// skip record and the Record* method
+	pc := debug.Callers(2, profileMaxDepth)
+
+	p.key = p.key[:0]
+	var b [8]byte
+	for _, v := range pc {
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		p.key = append(p.key, b[:]...)
+	}
+	s, ok := p.samples[string(p.key)]
+	if !ok {
+		s = &profileSample{pc: pc}
+		p.samples[string(p.key)] = s
+		p.order = append(p.order, s)
+	}
+	s.values[i]++
+}
+
+// WriteTo writes the profile to w, in gzipped pprof protobuf format
+func (p *Profile) WriteTo(w io.Writer) (int64, error) {
+	b := newProfileBuilder()
+	b.sampleType("constraints", "count")
+	b.sampleType("variables", "count")
+	b.sampleType("coefficients", "count")
+
+	if p != nil {
+		for _, s := range p.order {
+			b.sample(s)
+		}
+	}
+	b.functionsAndLocations()
+
+	cw := &countWriter{w: w}
+	zw := gzip.NewWriter(cw)
+	if _, err := zw.Write(b.buf); err != nil {
+		return cw.n, err
+	}
+	err := zw.Close()
+	return cw.n, err
+}
+
+// profileBuilder encodes a profile.proto message, resolving the program counters
+// into locations and functions
+type profileBuilder struct {
+	buf []byte
+
+	strings   map[string]int
+	locations map[uintptr]*profileLocation
+	locOrder  []*profileLocation
+	functions map[string]int // function name → function id
+	funcs     []profileFunction
+}
+
+type profileLocation struct {
+	id     int
+	lines  []profileLine // leaf first, when the pc expands into inlined frames
+	define bool          // the location is in a Define method, where circuit code starts
+}
+
+type profileLine struct {
+	functionID int
+	line       int
+}
+
+type profileFunction struct {
+	name, file int
+}
+
+// field numbers of the profile.proto messages
+const (
+	fProfileSampleType = 1
+	fProfileSample     = 2
+	fProfileLocation   = 4
+	fProfileFunction   = 5
+	fProfileString     = 6
+	fProfileDefault    = 14
+
+	fValueTypeType = 1
+	fValueTypeUnit = 2
+
+	fSampleLocation = 1
+	fSampleValue    = 2
+
+	fLocationID   = 1
+	fLocationLine = 4
+
+	fLineFunction = 1
+	fLineLine     = 2
+
+	fFunctionID         = 1
+	fFunctionName       = 2
+	fFunctionSystemName = 3
+	fFunctionFilename   = 4
+)
+
+func newProfileBuilder() *profileBuilder {
+	b := &profileBuilder{
+		strings:   make(map[string]int),
+		locations: make(map[uintptr]*profileLocation),
+		functions: make(map[string]int),
+	}
+	b.string("") // string_table[0] must be ""
+	return b
+}
+
+func (b *profileBuilder) string(s string) int {
+	if id, ok := b.strings[s]; ok {
+		return id
+	}
+	id := len(b.strings)
+	b.strings[s] = id
+	return id
+}
+
+func (b *profileBuilder) sampleType(typ, unit string) {
+	var m []byte
+	m = appendVarintField(m, fValueTypeType, uint64(b.string(typ)))
+	m = appendVarintField(m, fValueTypeUnit, uint64(b.string(unit)))
+	b.buf = appendBytesField(b.buf, fProfileSampleType, m)
+}
+
+func (b *profileBuilder) sample(s *profileSample) {
+	ids := make([]uint64, 0, len(s.pc))
+	for _, pc := range s.pc {
+		l := b.location(pc)
+		ids = append(ids, uint64(l.id))
+		if l.define {
+			// frames above Define belong to the compiler
+			break
+		}
+	}
+	values := make([]uint64, len(s.values))
+	for i, v := range s.values {
+		values[i] = uint64(v)
+	}
+
+	var m []byte
+	m = appendPackedField(m, fSampleLocation, ids)
+	m = appendPackedField(m, fSampleValue, values)
+	b.buf = appendBytesField(b.buf, fProfileSample, m)
+}
+
+func (b *profileBuilder) location(pc uintptr) *profileLocation {
+	if l, ok := b.locations[pc]; ok {
+		return l
+	}
+	l := &profileLocation{id: len(b.locOrder) + 1}
+	frames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			l.lines = append(l.lines, profileLine{functionID: b.function(frame.Function, frame.File), line: frame.Line})
+			if strings.HasSuffix(frame.Function, "Define") {
+				l.define = true
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	b.locations[pc] = l
+	b.locOrder = append(b.locOrder, l)
+	return l
+}
+
+func (b *profileBuilder) function(name, file string) int {
+	if id, ok := b.functions[name]; ok {
+		return id
+	}
+	b.funcs = append(b.funcs, profileFunction{name: b.string(name), file: b.string(file)})
+	id := len(b.funcs)
+	b.functions[name] = id
+	return id
+}
+
+// functionsAndLocations appends the locations, functions and string table to the message;
+// it must be called once all the samples are written
+func (b *profileBuilder) functionsAndLocations() {
+	for _, l := range b.locOrder {
+		var m []byte
+		m = appendVarintField(m, fLocationID, uint64(l.id))
+		for _, line := range l.lines {
+			var ml []byte
+			ml = appendVarintField(ml, fLineFunction, uint64(line.functionID))
+			ml = appendVarintField(ml, fLineLine, uint64(line.line))
+			m = appendBytesField(m, fLocationLine, ml)
+		}
+		b.buf = appendBytesField(b.buf, fProfileLocation, m)
+	}
+
+	for i, f := range b.funcs {
+		var m []byte
+		m = appendVarintField(m, fFunctionID, uint64(i+1))
+		m = appendVarintField(m, fFunctionName, uint64(f.name))
+		m = appendVarintField(m, fFunctionSystemName, uint64(f.name))
+		m = appendVarintField(m, fFunctionFilename, uint64(f.file))
+		b.buf = appendBytesField(b.buf, fProfileFunction, m)
+	}
+
+	// constraints are the default sample type
+	b.buf = appendVarintField(b.buf, fProfileDefault, uint64(b.string("constraints")))
+
+	table := make([]string, len(b.strings))
+	for s, id := range b.strings {
+		table[id] = s
+	}
+	for _, s := range table {
+		b.buf = appendBytesField(b.buf, fProfileString, []byte(s))
+	}
+}
+
+// protobuf wire types
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func appendVarintField(buf []byte, field int, v uint64) []byte {
+	buf = appendUvarint(buf, uint64(field)<<3|wireVarint)
+	return appendUvarint(buf, v)
+}
+
+func appendBytesField(buf []byte, field int, v []byte) []byte {
+	buf = appendUvarint(buf, uint64(field)<<3|wireBytes)
+	buf = appendUvarint(buf, uint64(len(v)))
+	return append(buf, v...)
+}
+
+func appendPackedField(buf []byte, field int, v []uint64) []byte {
+	var m []byte
+	for _, x := range v {
+		m = appendUvarint(m, x)
+	}
+	return appendBytesField(buf, field, m)
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
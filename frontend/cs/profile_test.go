@@ -0,0 +1,146 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cs_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+type profiledCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *profiledCircuit) Define(api frontend.API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(api.Add(x3, c.X, 5), c.Y)
+	api.ToBinary(c.X, 16)
+	api.AssertIsEqual(api.Mul(c.Y, 0xffffffffffff), api.Mul(x3, 42))
+	return nil
+}
+
+func TestProfile(t *testing.T) {
+	for name, newBuilder := range map[string]frontend.NewBuilder{"r1cs": r1cs.NewBuilder, "scs": scs.NewBuilder} {
+		var buf bytes.Buffer
+		ccs, err := frontend.Compile(ecc.BN254, newBuilder, &profiledCircuit{}, frontend.WithProfile(&buf))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		samples, stringTable := decodeProfile(t, &buf)
+
+		var totals [3]uint64
+		for _, values := range samples {
+			if len(values) != len(totals) {
+				t.Fatalf("%s: expected %d values per sample, got %d", name, len(totals), len(values))
+			}
+			for i, v := range values {
+				totals[i] += v
+			}
+		}
+
+		// the 4 default coefficients (0, 1, 2, -1) are not created by the circuit
+		internal, _, _ := ccs.GetNbVariables()
+		expected := [3]uint64{uint64(ccs.GetNbConstraints()), uint64(internal), uint64(ccs.GetNbCoefficients() - 4)}
+		if totals != expected {
+			t.Fatalf("%s: profile totals (constraints, variables, coefficients) are %v, expected %v", name, totals, expected)
+		}
+
+		// the circuit code appears in the stacks
+		found := false
+		for _, s := range stringTable {
+			if s == "github.com/consensys/gnark/frontend/cs_test.(*profiledCircuit).Define" {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("%s: Define is not in the profile", name)
+		}
+	}
+}
+
+// decodeProfile returns the sample values and the string table of a gzipped profile.proto
+func decodeProfile(t *testing.T, r io.Reader) (samples [][]uint64, stringTable []string) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range decodeFields(t, data) {
+		switch f.number {
+		case 2: // sample
+			for _, sf := range decodeFields(t, f.bytes) {
+				if sf.number == 2 { // packed values
+					var values []uint64
+					for b := sf.bytes; len(b) > 0; {
+						v, n := binary.Uvarint(b)
+						values = append(values, v)
+						b = b[n:]
+					}
+					samples = append(samples, values)
+				}
+			}
+		case 6: // string table
+			stringTable = append(stringTable, string(f.bytes))
+		}
+	}
+	return
+}
+
+type protoField struct {
+	number int
+	varint uint64
+	bytes  []byte
+}
+
+func decodeFields(t *testing.T, b []byte) []protoField {
+	var fields []protoField
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatal("invalid varint")
+		}
+		b = b[n:]
+		f := protoField{number: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			f.varint, n = binary.Uvarint(b)
+			b = b[n:]
+		case 2:
+			l, n := binary.Uvarint(b)
+			f.bytes = b[n : n+int(l)]
+			b = b[n+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
@@ -56,6 +56,9 @@ type r1cs struct {
 	st     cs.CoeffTable
 	config frontend.CompileConfig
 
+	// profile records the stacks creating constraints, variables and coefficients (nil if not profiling)
+	profile *cs.Profile
+
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[uint64][]compiled.LinearExpression
 }
@@ -83,6 +86,11 @@ func newBuilder(curveID ecc.ID, config frontend.CompileConfig) *r1cs {
 
 	system.CurveID = curveID
 
+	if config.Profile != nil {
+		system.profile = cs.NewProfile()
+		system.st.Profile = system.profile
+	}
+
 	return &system
 }
 
@@ -91,6 +99,7 @@ func newBuilder(curveID ecc.ID, config frontend.CompileConfig) *r1cs {
 func (system *r1cs) newInternalVariable() compiled.LinearExpression {
 	idx := system.NbInternalVariables + system.NbPublicVariables + system.NbSecretVariables
 	system.NbInternalVariables++
+	system.profile.RecordVariable()
 	return compiled.LinearExpression{
 		compiled.Pack(idx, compiled.CoeffIdOne, schema.Internal),
 	}
@@ -169,6 +178,7 @@ func newR1C(_l, _r, _o frontend.Variable) compiled.R1C {
 
 func (system *r1cs) addConstraint(r1c compiled.R1C, debugID ...int) {
 	system.Constraints = append(system.Constraints, r1c)
+	system.profile.RecordConstraint()
 	if len(debugID) > 0 {
 		system.MDebug[len(system.Constraints)-1] = debugID[0]
 	}
@@ -377,6 +387,10 @@ func (cs *r1cs) Compile() (frontend.CompiledConstraintSystem, error) {
 	// build levels
 	res.Levels = buildLevels(res)
 
+	if err := cs.writeProfile(); err != nil {
+		return nil, err
+	}
+
 	switch cs.CurveID {
 	case ecc.BLS12_377:
 		return bls12377r1cs.NewR1CS(res, cs.st.Coeffs), nil
@@ -395,6 +409,17 @@ func (cs *r1cs) Compile() (frontend.CompiledConstraintSystem, error) {
 	}
 }
 
+// writeProfile writes the compile profile to the writer provided with frontend.WithProfile, if any
+func (cs *r1cs) writeProfile() error {
+	if cs.profile == nil {
+		return nil
+	}
+	if _, err := cs.profile.WriteTo(cs.config.Profile); err != nil {
+		return fmt.Errorf("write profile: %w", err)
+	}
+	return nil
+}
+
 func (cs *r1cs) SetSchema(s *schema.Schema) {
 	if cs.Schema != nil {
 		panic("SetSchema called multiple times")
@@ -209,10 +209,12 @@ func (system *scs) Xor(a, b frontend.Variable) frontend.Variable {
 	if bConstant {
 		l := a.(compiled.Term)
 		r := l
+		// (2b-1)*a + res - b == 0
+		k := new(big.Int).Neg(_b)
 		one := big.NewInt(1)
 		_b.Lsh(_b, 1).Sub(_b, one)
 		idl := system.st.CoeffID(_b)
-		system.addPlonkConstraint(l, r, res, idl, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdOne, compiled.CoeffIdZero)
+		system.addPlonkConstraint(l, r, res, idl, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdOne, system.st.CoeffID(k))
 		return res
 	}
 	l := a.(compiled.Term)
@@ -246,10 +248,12 @@ func (system *scs) Or(a, b frontend.Variable) frontend.Variable {
 		}
 		system.AssertIsBoolean(a)
 
+		// (b-1)*a + res - b == 0
+		k := new(big.Int).Neg(_b)
 		one := big.NewInt(1)
 		_b.Sub(_b, one)
 		idl := system.st.CoeffID(_b)
-		system.addPlonkConstraint(l, r, res, idl, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdOne, compiled.CoeffIdZero)
+		system.addPlonkConstraint(l, r, res, idl, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdOne, system.st.CoeffID(k))
 		return res
 	}
 	l := a.(compiled.Term)
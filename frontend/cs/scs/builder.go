@@ -55,6 +55,9 @@ type scs struct {
 	st     cs.CoeffTable
 	config frontend.CompileConfig
 
+	// profile records the stacks creating constraints, variables and coefficients (nil if not profiling)
+	profile *cs.Profile
+
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[int]struct{}
 }
@@ -79,6 +82,11 @@ func newBuilder(curveID ecc.ID, config frontend.CompileConfig) *scs {
 
 	system.CurveID = curveID
 
+	if config.Profile != nil {
+		system.profile = cs.NewProfile()
+		system.st.Profile = system.profile
+	}
+
 	return &system
 }
 
@@ -101,6 +109,7 @@ func (system *scs) addPlonkConstraint(l, r, o compiled.Term, cidl, cidr, cidm1,
 
 	//system.Constraints = append(system.Constraints, compiled.SparseR1C{L: _l, R: _r, O: _o, M: [2]compiled.Term{u, v}, K: k})
 	system.Constraints = append(system.Constraints, compiled.SparseR1C{L: l, R: r, O: o, M: [2]compiled.Term{u, v}, K: k})
+	system.profile.RecordConstraint()
 }
 
 // newInternalVariable creates a new wire, appends it on the list of wires of the circuit, sets
@@ -108,6 +117,7 @@ func (system *scs) addPlonkConstraint(l, r, o compiled.Term, cidl, cidr, cidm1,
 func (system *scs) newInternalVariable() compiled.Term {
 	idx := system.NbInternalVariables + system.NbPublicVariables + system.NbSecretVariables
 	system.NbInternalVariables++
+	system.profile.RecordVariable()
 	return compiled.Pack(idx, compiled.CoeffIdOne, schema.Internal)
 }
 
@@ -325,6 +335,10 @@ func (cs *scs) Compile() (frontend.CompiledConstraintSystem, error) {
 	// build levels
 	res.Levels = buildLevels(res)
 
+	if err := cs.writeProfile(); err != nil {
+		return nil, err
+	}
+
 	switch cs.CurveID {
 	case ecc.BLS12_377:
 		return bls12377r1cs.NewSparseR1CS(res, cs.st.Coeffs), nil
@@ -344,6 +358,17 @@ func (cs *scs) Compile() (frontend.CompiledConstraintSystem, error) {
 
 }
 
+// writeProfile writes the compile profile to the writer provided with frontend.WithProfile, if any
+func (cs *scs) writeProfile() error {
+	if cs.profile == nil {
+		return nil
+	}
+	if _, err := cs.profile.WriteTo(cs.config.Profile); err != nil {
+		return fmt.Errorf("write profile: %w", err)
+	}
+	return nil
+}
+
 func (cs *scs) SetSchema(s *schema.Schema) {
 	if cs.Schema != nil {
 		panic("SetSchema called multiple times")
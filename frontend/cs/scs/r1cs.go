@@ -0,0 +1,591 @@
+/*
+Copyright © 2021 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scs
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/compiled"
+	"github.com/consensys/gnark/frontend/schema"
+	bls12377r1cs "github.com/consensys/gnark/internal/backend/bls12-377/cs"
+	bls12381r1cs "github.com/consensys/gnark/internal/backend/bls12-381/cs"
+	bls24315r1cs "github.com/consensys/gnark/internal/backend/bls24-315/cs"
+	bn254r1cs "github.com/consensys/gnark/internal/backend/bn254/cs"
+	bw6633r1cs "github.com/consensys/gnark/internal/backend/bw6-633/cs"
+	bw6761r1cs "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+)
+
+// FromR1CS converts a R1CS compiled with frontend/cs/r1cs into an equivalent SparseR1CS
+// which can be used with the PlonK backend.
+//
+// Each rank-1 constraint L⋅R == O is split into plonk gates: linear expressions are folded
+// with addition gates, and the wire the R1CS solver would deduce from the constraint is placed
+// in a position the SparseR1CS solver can solve. The ONE_WIRE of the R1CS disappears (its terms
+// become constants of the gates), hints, logs and debug info are carried over on the new wires.
+//
+// For a given witness, the converted system solves to the same input, hint and R1CS internal
+// wire values as the original one.
+func FromR1CS(ccs frontend.CompiledConstraintSystem) (frontend.CompiledConstraintSystem, error) {
+	c, err := newR1CSConverter(ccs)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.convert(); err != nil {
+		return nil, err
+	}
+	return c.system.Compile()
+}
+
+// r1csConverter holds the state of a R1CS -> SparseR1CS conversion
+type r1csConverter struct {
+	system *scs
+
+	r1cs   compiled.R1CS
+	coeffs []big.Int // R1CS coefficients, in regular form
+	mod    *big.Int
+
+	wires    []compiled.Term                    // R1CS wireID -> SparseR1CS wire
+	mapped   []bool                             // true if wires[wireID] is set
+	solved   []bool                             // true if the R1CS wire is solved by the previous constraints
+	mCoeffs  map[int]int                        // R1CS coeffID -> SparseR1CS coeffID
+	mSums    map[[2]compiled.Term]compiled.Term // (a, b) -> wire constrained to a + b
+	hasOne   bool
+	oneWire  compiled.Term // wire constrained to 1, created if a hint needs a constant in a linear expression
+	nbInputs int           // number of R1CS inputs, ONE_WIRE included
+}
+
+func newR1CSConverter(ccs frontend.CompiledConstraintSystem) (*r1csConverter, error) {
+	r1cs, coeffs, err := compiledR1CS(ccs)
+	if err != nil {
+		return nil, err
+	}
+
+	// the R1CS was already checked by the compiler, we don't want to fail on
+	// inputs that were explicitly ignored.
+	system := newBuilder(ccs.CurveID(), frontend.CompileConfig{
+		Capacity:                  len(r1cs.Constraints),
+		IgnoreUnconstrainedInputs: true,
+	})
+
+	// the R1CS public inputs start with the ONE_WIRE
+	system.Schema = r1cs.Schema
+	system.NbPublicVariables = r1cs.NbPublicVariables - 1
+	system.NbSecretVariables = r1cs.NbSecretVariables
+	system.Public = append(system.Public, r1cs.Public[1:]...)
+	system.Secret = append(system.Secret, r1cs.Secret...)
+	for id, name := range r1cs.MHintsDependencies {
+		system.MHintsDependencies[id] = name
+	}
+
+	nbWires := r1cs.NbPublicVariables + r1cs.NbSecretVariables + r1cs.NbInternalVariables
+	c := &r1csConverter{
+		system:   system,
+		r1cs:     r1cs,
+		coeffs:   coeffs,
+		mod:      ccs.CurveID().Info().Fr.Modulus(),
+		wires:    make([]compiled.Term, nbWires),
+		mapped:   make([]bool, nbWires),
+		solved:   make([]bool, nbWires),
+		mCoeffs:  make(map[int]int),
+		mSums:    make(map[[2]compiled.Term]compiled.Term),
+		nbInputs: r1cs.NbPublicVariables + r1cs.NbSecretVariables,
+	}
+
+	for i := 1; i < r1cs.NbPublicVariables; i++ {
+		c.setWire(i, compiled.Pack(i-1, compiled.CoeffIdOne, schema.Public))
+	}
+	for i := r1cs.NbPublicVariables; i < c.nbInputs; i++ {
+		c.setWire(i, compiled.Pack(i-1, compiled.CoeffIdOne, schema.Secret))
+	}
+	c.solved[0] = true
+
+	return c, nil
+}
+
+// compiledR1CS returns the curve agnostic part of ccs, and its coefficients
+func compiledR1CS(ccs frontend.CompiledConstraintSystem) (compiled.R1CS, []big.Int, error) {
+	var coeffs []big.Int
+	switch t := ccs.(type) {
+	case *bls12377r1cs.R1CS:
+		coeffs = make([]big.Int, len(t.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			t.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+		return t.R1CS, coeffs, nil
+	case *bls12381r1cs.R1CS:
+		coeffs = make([]big.Int, len(t.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			t.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+		return t.R1CS, coeffs, nil
+	case *bn254r1cs.R1CS:
+		coeffs = make([]big.Int, len(t.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			t.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+		return t.R1CS, coeffs, nil
+	case *bw6761r1cs.R1CS:
+		coeffs = make([]big.Int, len(t.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			t.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+		return t.R1CS, coeffs, nil
+	case *bls24315r1cs.R1CS:
+		coeffs = make([]big.Int, len(t.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			t.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+		return t.R1CS, coeffs, nil
+	case *bw6633r1cs.R1CS:
+		coeffs = make([]big.Int, len(t.Coefficients))
+		for i := 0; i < len(coeffs); i++ {
+			t.Coefficients[i].ToBigIntRegular(&coeffs[i])
+		}
+		return t.R1CS, coeffs, nil
+	default:
+		return compiled.R1CS{}, nil, fmt.Errorf("can't convert %T, expected a R1CS", ccs)
+	}
+}
+
+func (c *r1csConverter) setWire(wireID int, t compiled.Term) {
+	c.wires[wireID] = t
+	c.mapped[wireID] = true
+	c.solved[wireID] = true
+}
+
+func (c *r1csConverter) convert() error {
+	for i, r := range c.r1cs.Constraints {
+		if err := c.convertConstraint(i, r); err != nil {
+			return err
+		}
+	}
+
+	// carry the logs and debug info over
+	for _, l := range c.r1cs.Logs {
+		entry, err := c.convertLogEntry(l)
+		if err != nil {
+			return err
+		}
+		c.system.Logs = append(c.system.Logs, entry)
+	}
+	for _, l := range c.r1cs.DebugInfo {
+		entry, err := c.convertLogEntry(l)
+		if err != nil {
+			return err
+		}
+		c.system.DebugInfo = append(c.system.DebugInfo, entry)
+	}
+
+	return nil
+}
+
+// convertConstraint adds the gates equivalent to the i-th R1C
+//
+// the R1CS solver deduces at most one wire per constraint, this wire (if any) ends
+// up in the last gate, which also carries the debug info of the R1C.
+func (c *r1csConverter) convertConstraint(cID int, r compiled.R1C) error {
+	lro := [3]compiled.LinearExpression{r.L, r.R, r.O}
+
+	// hint wires are solved lazily, when a constraint first references them
+	for _, l := range lro {
+		for _, t := range l {
+			if err := c.solveHint(t.WireID()); err != nil {
+				return err
+			}
+		}
+	}
+
+	// find the wire to solve
+	loc, idx := -1, -1
+	for i, l := range lro {
+		for j, t := range l {
+			if t.CoeffID() == compiled.CoeffIdZero || c.solved[t.WireID()] {
+				continue
+			}
+			if loc != -1 {
+				return fmt.Errorf("constraint %d has more than one unsolved wire", cID)
+			}
+			loc, idx = i, j
+		}
+	}
+
+	var u compiled.Term
+	var cu big.Int
+	if loc != -1 {
+		t := lro[loc][idx]
+		u = c.system.newInternalVariable()
+		c.setWire(t.WireID(), u)
+		cu.Set(&c.coeffs[t.CoeffID()])
+
+		l := make(compiled.LinearExpression, 0, len(lro[loc])-1)
+		l = append(l, lro[loc][:idx]...)
+		lro[loc] = append(l, lro[loc][idx+1:]...)
+	}
+
+	if loc == 1 {
+		// (a)⋅(b + cu⋅u) == o is solved as (b + cu⋅u)⋅(a) == o
+		lro[0], lro[1] = lro[1], lro[0]
+		loc = 0
+	}
+
+	var debugID []int
+	if id, ok := c.r1cs.MDebug[cID]; ok {
+		debugID = append(debugID, id)
+	}
+
+	a, ka, err := c.split(lro[0])
+	if err != nil {
+		return err
+	}
+	b, kb, err := c.split(lro[1])
+	if err != nil {
+		return err
+	}
+	o, ko, err := c.split(lro[2])
+	if err != nil {
+		return err
+	}
+
+	switch loc {
+	case -1:
+		// (a + ka)⋅(b + kb) - (o + ko) == 0
+		e := c.product(a, &ka, b, &kb, o, &ko)
+		c.emit(e, c.system.zero(), debugID...)
+	case 2:
+		// (a + ka)⋅(b + kb) - (o + ko) - cu⋅u == 0
+		e := c.product(a, &ka, b, &kb, o, &ko)
+		cu.Neg(&cu).Mod(&cu, c.mod)
+		u.SetCoeffID(c.system.st.CoeffID(&cu))
+		c.emit(e, u, debugID...)
+	case 0:
+		if len(b) == 0 {
+			// (cu⋅u + a + ka)⋅kb - (o + ko) == 0, this is linear
+			if kb.Sign() == 0 {
+				return fmt.Errorf("constraint %d can't be solved, its right hand side is zero", cID)
+			}
+			lin := make(compiled.LinearExpression, 0, len(a)+len(o))
+			for _, t := range a {
+				lin = append(lin, c.system.mulConstant(t, &kb))
+			}
+			lin = append(lin, c.neg(o)...)
+			var k big.Int
+			k.Mul(&ka, &kb).Sub(&k, &ko).Mod(&k, c.mod)
+			cu.Mul(&cu, &kb).Mod(&cu, c.mod)
+			u.SetCoeffID(c.system.st.CoeffID(&cu))
+			c.emit(c.linear(lin, &k), u, debugID...)
+			return nil
+		}
+
+		// cu⋅u⋅(b + kb) + (a + ka)⋅(b + kb) - (o + ko) == 0
+		// the known part s + ks is computed first, then u is solved with
+		// 	cu⋅kb⋅u + cu⋅u⋅b + s + ks == 0
+		s, ks := c.materialize(c.product(a, &ka, b, &kb, o, &ko))
+		bt := c.fold(b)
+
+		var qL big.Int
+		qL.Mul(&cu, &kb).Mod(&qL, c.mod)
+		cidl := c.system.st.CoeffID(&qL)
+		cidm1 := c.system.st.CoeffID(&cu)
+		c.system.addPlonkConstraint(u, bt, s, cidl, compiled.CoeffIdZero, cidm1, bt.CoeffID(), s.CoeffID(), c.system.st.CoeffID(&ks), debugID...)
+	}
+
+	return nil
+}
+
+// expression represents qL⋅l + qR⋅r + qM⋅l⋅r + k, where l, r are solved wires,
+// qL and qR are the coefficients of l and r and qM = m[0]⋅m[1]
+type expression struct {
+	l, r compiled.Term
+	m    [2]int
+	k    big.Int
+}
+
+// emit adds the gate expression + o == 0
+func (c *r1csConverter) emit(e expression, o compiled.Term, debugID ...int) {
+	c.system.addPlonkConstraint(e.l, e.r, o, e.l.CoeffID(), e.r.CoeffID(), e.m[0], e.m[1], o.CoeffID(), c.system.st.CoeffID(&e.k), debugID...)
+}
+
+// product returns (a + ka)⋅(b + kb) - (o + ko) as an expression, adding intermediate gates if needed
+func (c *r1csConverter) product(a compiled.LinearExpression, ka *big.Int, b compiled.LinearExpression, kb *big.Int, o compiled.LinearExpression, ko *big.Int) expression {
+	var k big.Int
+	k.Mul(ka, kb).Mod(&k, c.mod)
+
+	if len(a) != 0 && len(b) != 0 {
+		l, r := c.fold(a), c.fold(b)
+
+		var e expression
+		e.m = [2]int{l.CoeffID(), r.CoeffID()}
+		e.l = c.system.mulConstant(l, kb)
+		e.r = c.system.mulConstant(r, ka)
+		e.k.Set(&k)
+
+		if len(o) == 0 {
+			e.k.Sub(&e.k, ko).Mod(&e.k, c.mod)
+			return e
+		}
+
+		// the product doesn't fit in the same gate as o
+		p := c.system.newInternalVariable()
+		po := p
+		po.SetCoeffID(compiled.CoeffIdMinusOne)
+		c.emit(e, po)
+
+		k.Neg(ko).Mod(&k, c.mod)
+		return c.linear(append(c.neg(o), p), &k)
+	}
+
+	// at least one side is constant, the product is linear
+	lin := make(compiled.LinearExpression, 0, len(a)+len(b)+len(o))
+	for _, t := range a {
+		lin = append(lin, c.system.mulConstant(t, kb))
+	}
+	for _, t := range b {
+		lin = append(lin, c.system.mulConstant(t, ka))
+	}
+	lin = append(lin, c.neg(o)...)
+	k.Sub(&k, ko).Mod(&k, c.mod)
+
+	return c.linear(lin, &k)
+}
+
+// linear returns l + k as an expression, adding addition gates if needed
+func (c *r1csConverter) linear(l compiled.LinearExpression, k *big.Int) expression {
+	var e expression
+	e.k.Set(k)
+	l = c.system.reduce(l)
+
+	// filter out the terms that cancelled out
+	n := 0
+	for _, t := range l {
+		if t.CoeffID() != compiled.CoeffIdZero {
+			l[n] = t
+			n++
+		}
+	}
+	l = l[:n]
+
+	switch len(l) {
+	case 0:
+		e.l, e.r = c.system.zero(), c.system.zero()
+	case 1:
+		e.l, e.r = l[0], c.system.zero()
+	default:
+		e.l, e.r = l[0], c.fold(l[1:])
+	}
+	return e
+}
+
+// materialize returns a term and a constant (t, k) such that t + k == e.
+// if e is not of that form already, a new wire is created.
+func (c *r1csConverter) materialize(e expression) (compiled.Term, big.Int) {
+	if e.m[0] == compiled.CoeffIdZero || e.m[1] == compiled.CoeffIdZero {
+		if e.r.CoeffID() == compiled.CoeffIdZero {
+			return e.l, e.k
+		}
+	}
+	s := c.system.newInternalVariable()
+	so := s
+	so.SetCoeffID(compiled.CoeffIdMinusOne)
+	c.emit(e, so)
+	return s, big.Int{}
+}
+
+// fold returns a single term equal to the sum of the terms in l, adding addition gates if needed
+//
+// R1CS linear expressions are often reused, or extended, from one constraint to the next
+// (binary decompositions for example), so the partial sums are memoized.
+func (c *r1csConverter) fold(l compiled.LinearExpression) compiled.Term {
+	acc := l[0]
+	for _, t := range l[1:] {
+		k := [2]compiled.Term{acc, t}
+		if o, ok := c.mSums[k]; ok {
+			acc = o
+			continue
+		}
+		o := c.system.newInternalVariable()
+		c.system.addPlonkConstraint(acc, t, o, acc.CoeffID(), t.CoeffID(), compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdMinusOne, compiled.CoeffIdZero)
+		c.mSums[k] = o
+		acc = o
+	}
+	return acc
+}
+
+func (c *r1csConverter) neg(l compiled.LinearExpression) compiled.LinearExpression {
+	var minusOne big.Int
+	minusOne.SetInt64(-1)
+	res := make(compiled.LinearExpression, len(l))
+	for i, t := range l {
+		if t.CoeffID() == compiled.CoeffIdOne {
+			t.SetCoeffID(compiled.CoeffIdMinusOne)
+			res[i] = t
+		} else {
+			res[i] = c.system.mulConstant(t, &minusOne)
+		}
+	}
+	return res
+}
+
+// split returns l as a linear expression on the SparseR1CS wires and its constant part (ONE_WIRE terms)
+// all the wires in l must be solved
+func (c *r1csConverter) split(l compiled.LinearExpression) (compiled.LinearExpression, big.Int, error) {
+	var k big.Int
+	res := make(compiled.LinearExpression, 0, len(l))
+	for _, t := range l {
+		cID, wID, _ := t.Unpack()
+		if cID == compiled.CoeffIdZero {
+			continue
+		}
+		if wID == 0 {
+			k.Add(&k, &c.coeffs[cID])
+			continue
+		}
+		if !c.mapped[wID] {
+			return nil, k, fmt.Errorf("wire %d is referenced before being solved", wID)
+		}
+		nt := c.wires[wID]
+		nt.SetCoeffID(c.coeffID(cID))
+		res = append(res, nt)
+	}
+	k.Mod(&k, c.mod)
+	return res, k, nil
+}
+
+// coeffID returns the SparseR1CS coefficient ID of the R1CS coefficient cID
+func (c *r1csConverter) coeffID(cID int) int {
+	if cID <= compiled.CoeffIdMinusOne {
+		return cID
+	}
+	if id, ok := c.mCoeffs[cID]; ok {
+		return id
+	}
+	id := c.system.st.CoeffID(&c.coeffs[cID])
+	c.mCoeffs[cID] = id
+	return id
+}
+
+// one returns a wire constrained to be equal to 1
+func (c *r1csConverter) one() compiled.Term {
+	if !c.hasOne {
+		c.oneWire = c.system.newInternalVariable()
+		c.system.addPlonkConstraint(c.system.zero(), c.system.zero(), c.oneWire, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdZero, compiled.CoeffIdMinusOne, compiled.CoeffIdOne)
+		c.hasOne = true
+	}
+	return c.oneWire
+}
+
+// solveHint maps the wires of the hint wireID belongs to, if any and if it wasn't done before
+func (c *r1csConverter) solveHint(wireID int) error {
+	if c.solved[wireID] {
+		return nil
+	}
+	h, ok := c.r1cs.MHints[wireID]
+	if !ok {
+		return nil
+	}
+
+	inputs := make([]interface{}, len(h.Inputs))
+	for i, in := range h.Inputs {
+		switch t := in.(type) {
+		case compiled.LinearExpression:
+			v, err := c.hintInput(t)
+			if err != nil {
+				return err
+			}
+			inputs[i] = v
+		case compiled.Term:
+			v, err := c.hintInput(compiled.LinearExpression{t})
+			if err != nil {
+				return err
+			}
+			inputs[i] = v
+		default:
+			inputs[i] = t
+		}
+	}
+
+	wires := make([]int, len(h.Wires))
+	for i, wID := range h.Wires {
+		t := c.system.newInternalVariable()
+		c.setWire(wID, t)
+		wires[i] = t.WireID()
+	}
+
+	nh := &compiled.Hint{ID: h.ID, Inputs: inputs, Wires: wires}
+	for _, wID := range wires {
+		c.system.MHints[wID] = nh
+	}
+
+	return nil
+}
+
+// hintInput converts a linear expression input of a hint
+func (c *r1csConverter) hintInput(l compiled.LinearExpression) (interface{}, error) {
+	for _, t := range l {
+		if t.CoeffID() == compiled.CoeffIdZero {
+			continue
+		}
+		if err := c.solveHint(t.WireID()); err != nil {
+			return nil, err
+		}
+		if !c.solved[t.WireID()] {
+			return nil, fmt.Errorf("hint input wire %d is not solved", t.WireID())
+		}
+	}
+	res, k, err := c.split(l)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return k, nil
+	}
+	if k.Sign() != 0 {
+		t := c.one()
+		t.SetCoeffID(c.system.st.CoeffID(&k))
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+// convertLogEntry remaps the wires and coefficients of a log entry
+func (c *r1csConverter) convertLogEntry(l compiled.LogEntry) (compiled.LogEntry, error) {
+	res := compiled.LogEntry{
+		Caller:    l.Caller,
+		Format:    l.Format,
+		ToResolve: make([]compiled.Term, len(l.ToResolve)),
+	}
+	for i, t := range l.ToResolve {
+		if t == compiled.TermDelimitor {
+			res.ToResolve[i] = t
+			continue
+		}
+		cID, wID, visibility := t.Unpack()
+		if visibility == schema.Virtual || (visibility == schema.Public && wID == 0) {
+			// constant
+			res.ToResolve[i] = compiled.Pack(0, c.coeffID(cID), schema.Virtual)
+			continue
+		}
+		if !c.mapped[wID] {
+			return res, fmt.Errorf("log references wire %d which is not in any constraint", wID)
+		}
+		nt := c.wires[wID]
+		nt.SetCoeffID(c.coeffID(cID))
+		res.ToResolve[i] = nt
+	}
+	return res, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2021 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scs
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/kzg"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	bn254cs "github.com/consensys/gnark/internal/backend/bn254/cs"
+	bn254witness "github.com/consensys/gnark/internal/backend/bn254/witness"
+	"github.com/consensys/gnark/internal/backend/circuits"
+)
+
+func TestFromR1CS(t *testing.T) {
+	for name, tData := range circuits.Circuits {
+		tData := tData
+		t.Run(name, func(t *testing.T) {
+			hints := backend.WithHints(tData.HintFunctions...)
+
+			for _, curve := range tData.Curves {
+				ccs, err := frontend.Compile(curve, r1cs.NewBuilder, tData.Circuit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				spr, err := FromR1CS(ccs)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, assignment := range tData.ValidAssignments {
+					w, err := frontend.NewWitness(assignment, curve)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := spr.IsSolved(w, hints); err != nil {
+						t.Fatalf("%s: valid witness not solved: %v", curve, err)
+					}
+				}
+				for _, assignment := range tData.InvalidAssignments {
+					w, err := frontend.NewWitness(assignment, curve)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := spr.IsSolved(w, hints); err == nil {
+						t.Fatalf("%s: invalid witness solved", curve)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFromR1CSSolution(t *testing.T) {
+	for name, tData := range circuits.Circuits {
+		tData := tData
+		t.Run(name, func(t *testing.T) {
+			opt, err := backend.NewProverConfig(backend.WithHints(tData.HintFunctions...))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, tData.Circuit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := newR1CSConverter(ccs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.convert(); err != nil {
+				t.Fatal(err)
+			}
+			spr, err := c.system.Compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, assignment := range tData.ValidAssignments {
+				w, err := frontend.NewWitness(assignment, ecc.BN254)
+				if err != nil {
+					t.Fatal(err)
+				}
+				v := *w.Vector.(*bn254witness.Witness)
+
+				nbConstraints := ccs.GetNbConstraints()
+				a := make([]fr.Element, nbConstraints)
+				b := make([]fr.Element, nbConstraints)
+				o := make([]fr.Element, nbConstraints)
+				expected, err := ccs.(*bn254cs.R1CS).Solve(v, a, b, o, opt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				solution, err := spr.(*bn254cs.SparseR1CS).Solve(v, opt)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// every R1CS wire but the ONE_WIRE has a counterpart in the SparseR1CS
+				for wID := 1; wID < len(expected); wID++ {
+					if !c.mapped[wID] {
+						t.Fatalf("wire %d is not mapped", wID)
+					}
+					if !solution[c.wires[wID].WireID()].Equal(&expected[wID]) {
+						t.Fatalf("wire %d: expected %s, got %s", wID, expected[wID].String(), solution[c.wires[wID].WireID()].String())
+					}
+				}
+
+				// the converted system goes through plonk
+				_, _, nbPublic := spr.GetNbVariables()
+				size := ecc.NextPowerOfTwo(uint64(spr.GetNbConstraints()+nbPublic)) + 3
+				srs, err := kzg.NewSRS(size, big.NewInt(42))
+				if err != nil {
+					t.Fatal(err)
+				}
+				pk, vk, err := plonk.Setup(spr, srs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				proof, err := plonk.Prove(spr, pk, w, backend.WithHints(tData.HintFunctions...))
+				if err != nil {
+					t.Fatal(err)
+				}
+				publicWitness, err := w.Public()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := plonk.Verify(proof, vk, publicWitness); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
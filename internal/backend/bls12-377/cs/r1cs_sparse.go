@@ -17,6 +17,7 @@
 package cs
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
@@ -46,6 +47,9 @@ type SparseR1CS struct {
 	compiled.SparseR1CS
 
 	Coefficients []fr.Element // coefficients in the constraints
+
+	digest     [32]byte  // computed once by Digest
+	digestOnce sync.Once // the constraint system must not be modified after Digest is called
 }
 
 // NewSparseR1CS returns a new SparseR1CS and sets r1cs.Coefficient (fr.Element) from provided big.Int values
@@ -109,7 +113,7 @@ func (cs *SparseR1CS) Solve(witness []fr.Element, opt backend.ProverConfig) ([]f
 		coefficientsNegInv[i].Neg(&coefficientsNegInv[i])
 	}
 
-	if err := cs.parallelSolve(&solution, coefficientsNegInv); err != nil {
+	if err := cs.parallelSolve(&solution, coefficientsNegInv, opt.NbTasks); err != nil {
 		if unsatisfiedErr, ok := err.(*UnsatisfiedConstraintError); ok {
 			log.Err(errors.New("unsatisfied constraint")).Int("id", unsatisfiedErr.CID).Send()
 		} else {
@@ -130,7 +134,7 @@ func (cs *SparseR1CS) Solve(witness []fr.Element, opt backend.ProverConfig) ([]f
 
 }
 
-func (cs *SparseR1CS) parallelSolve(solution *solution, coefficientsNegInv []fr.Element) error {
+func (cs *SparseR1CS) parallelSolve(solution *solution, coefficientsNegInv []fr.Element, nbWorkers int) error {
 	// minWorkPerCPU is the minimum target number of constraint a task should hold
 	// in other words, if a level has less than minWorkPerCPU, it will not be parallelized and executed
 	// sequentially without sync.
@@ -139,14 +143,20 @@ func (cs *SparseR1CS) parallelSolve(solution *solution, coefficientsNegInv []fr.
 	// cs.Levels has a list of levels, where all constraints in a level l(n) are independent
 	// and may only have dependencies on previous levels
 
+	if nbWorkers <= 0 {
+		nbWorkers = runtime.NumCPU()
+	}
+	// with a single worker, the levels are solved sequentially in the calling go routine
+	sequential := nbWorkers == 1
+
 	var wg sync.WaitGroup
-	chTasks := make(chan []int, runtime.NumCPU())
-	chError := make(chan *UnsatisfiedConstraintError, runtime.NumCPU())
+	chTasks := make(chan []int, nbWorkers)
+	chError := make(chan *UnsatisfiedConstraintError, nbWorkers)
 
 	// start a worker pool
 	// each worker wait on chTasks
 	// a task is a slice of constraint indexes to be solved
-	for i := 0; i < runtime.NumCPU(); i++ {
+	for i := 0; i < nbWorkers && !sequential; i++ {
 		go func() {
 			for t := range chTasks {
 				for _, i := range t {
@@ -184,7 +194,7 @@ func (cs *SparseR1CS) parallelSolve(solution *solution, coefficientsNegInv []fr.
 		// max CPU to use
 		maxCPU := float64(len(level)) / minWorkPerCPU
 
-		if maxCPU <= 1.0 {
+		if maxCPU <= 1.0 || sequential {
 			// we do it sequentially
 			for _, i := range level {
 				if err := cs.solveConstraint(cs.Constraints[i], solution, coefficientsNegInv); err != nil {
@@ -203,7 +213,7 @@ func (cs *SparseR1CS) parallelSolve(solution *solution, coefficientsNegInv []fr.
 
 		// number of tasks for this level is set to num cpus
 		// but if we don't have enough work for all our CPUS, it can be lower.
-		nbTasks := runtime.NumCPU()
+		nbTasks := nbWorkers
 		maxTasks := int(math.Ceil(maxCPU))
 		if nbTasks > maxTasks {
 			nbTasks = maxTasks
@@ -373,15 +383,37 @@ func (cs *SparseR1CS) IsSolved(witness *witness.Witness, opts ...backend.ProverO
 	return err
 }
 
+// SolveWires solves the SparseR1CS with the given witness and returns all its wires,
+// labelled with the input names and the hints that computed them
+func (cs *SparseR1CS) SolveWires(witness *witness.Witness, opts ...backend.ProverOption) (*compiled.Solution, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	v := witness.Vector.(*bls12_377witness.Witness)
+	wires, err := cs.Solve(*v, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]big.Int, len(wires))
+	for i := 0; i < len(wires); i++ {
+		wires[i].ToBigIntRegular(&values[i])
+	}
+	return cs.NewSolution(values), nil
+}
+
 // GetConstraints return a list of constraint formatted as in the paper
 // https://eprint.iacr.org/2019/953.pdf section 6 such that
 // qL⋅xa + qR⋅xb + qO⋅xc + qM⋅(xaxb) + qC == 0
 // each constraint is thus decomposed in [5]string with
-// 		[0] = qL⋅xa
-//		[1] = qR⋅xb
-//		[2] = qO⋅xc
-//		[3] = qM⋅(xaxb)
-//		[4] = qC
+//
+//	[0] = qL⋅xa
+//	[1] = qR⋅xb
+//	[2] = qO⋅xc
+//	[3] = qM⋅(xaxb)
+//	[4] = qC
 func (cs *SparseR1CS) GetConstraints() [][]string {
 	r := make([][]string, 0, len(cs.Constraints))
 	for _, c := range cs.Constraints {
@@ -502,6 +534,32 @@ func (cs *SparseR1CS) GetNbCoefficients() int {
 	return len(cs.Coefficients)
 }
 
+// Digest returns a deterministic sha256 digest of the constraint system (curve, schema, constraints,
+// coefficients and hints). It is recorded in the proving and verifying keys at setup, and checked by Prove.
+//
+// The digest is computed on the first call, and cached for the following ones.
+func (cs *SparseR1CS) Digest() [32]byte {
+	cs.digestOnce.Do(func() {
+		cs.digest = cs.computeDigest()
+	})
+	return cs.digest
+}
+
+func (cs *SparseR1CS) computeDigest() [32]byte {
+	h := sha256.New()
+
+	// writing to a hash.Hash never returns an error
+	_ = cs.SparseR1CS.WriteDigest(h)
+	for i := 0; i < len(cs.Coefficients); i++ {
+		b := cs.Coefficients[i].Bytes()
+		h.Write(b[:])
+	}
+
+	var res [32]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
 // CurveID returns curve ID as defined in gnark-crypto (ecc.BLS12-377)
 func (cs *SparseR1CS) CurveID() ecc.ID {
 	return ecc.BLS12_377
@@ -530,6 +588,8 @@ func (cs *SparseR1CS) ReadFrom(r io.Reader) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	// the digest of the decoded constraint system is computed anew
+	cs.digestOnce = sync.Once{}
 	decoder := dm.NewDecoder(r)
 	err = decoder.Decode(cs)
 	return int64(decoder.NumBytesRead()), err
@@ -74,6 +74,49 @@ func (proof *Proof) ReadFrom(r io.Reader) (n int64, err error) {
 	return dec.BytesRead(), nil
 }
 
+// WriteTo writes binary encoding of the Solution to writer
+// CircuitDigest | Wires | A | B | C
+func (solution *Solution) WriteTo(w io.Writer) (n int64, err error) {
+	enc := curve.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&solution.CircuitDigest,
+		solution.Wires,
+		solution.A,
+		solution.B,
+		solution.C,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom attempts to decode a Solution from reader
+func (solution *Solution) ReadFrom(r io.Reader) (n int64, err error) {
+	dec := curve.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&solution.CircuitDigest,
+		&solution.Wires,
+		&solution.A,
+		&solution.B,
+		&solution.C,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
 // WriteTo writes binary encoding of the key elements to writer
 // points are compressed
 // use WriteRawTo(...) to encode the key without point compression
@@ -92,6 +135,7 @@ func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
 // follows bellman format:
 // https://github.com/zkcrypto/bellman/blob/fa9be45588227a8c6ec34957de3f68705f07bd92/src/groth16/mod.rs#L143
 // [α]1,[β]1,[β]2,[γ]2,[δ]1,[δ]2,uint32(len(Kvk)),[Kvk]1
+// followed by the circuit digest (32 bytes)
 func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	var enc *curve.Encoder
 	if raw {
@@ -124,6 +168,10 @@ func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	if err := enc.Encode(vk.G1.K); err != nil {
 		return enc.BytesWritten(), err
 	}
+
+	if err := enc.Encode(&vk.CircuitDigest); err != nil {
+		return enc.BytesWritten(), err
+	}
 	return enc.BytesWritten(), nil
 }
 
@@ -132,6 +180,7 @@ func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 // serialization format:
 // https://github.com/zkcrypto/bellman/blob/fa9be45588227a8c6ec34957de3f68705f07bd92/src/groth16/mod.rs#L143
 // [α]1,[β]1,[β]2,[γ]2,[δ]1,[δ]2,uint32(len(Kvk)),[Kvk]1
+// followed by the circuit digest, which is optional (bellman keys don't have it)
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	return vk.readFrom(r)
 }
@@ -144,42 +193,128 @@ func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
 
 func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
 	dec := curve.NewDecoder(r, decOptions...)
+	if err := vk.decode(dec); err != nil {
+		return dec.BytesRead(), err
+	}
 
+	// recompute vk.e (e(α, β)) and  -[δ]2, -[γ]2
+	var err error
+	vk.e, err = curve.Pair([]curve.G1Affine{vk.G1.Alpha}, []curve.G2Affine{vk.G2.Beta})
+	if err != nil {
+		return dec.BytesRead(), err
+	}
+	vk.G2.deltaNeg.Neg(&vk.G2.Delta)
+	vk.G2.gammaNeg.Neg(&vk.G2.Gamma)
+
+	return dec.BytesRead(), nil
+}
+
+// decode decodes the elements of the key written by writeTo, without its precomputations
+func (vk *VerifyingKey) decode(dec *curve.Decoder) error {
 	// [α]1,[β]1,[β]2,[γ]2,[δ]1,[δ]2
 	if err := dec.Decode(&vk.G1.Alpha); err != nil {
-		return dec.BytesRead(), err
+		return err
 	}
 	if err := dec.Decode(&vk.G1.Beta); err != nil {
-		return dec.BytesRead(), err
+		return err
 	}
 	if err := dec.Decode(&vk.G2.Beta); err != nil {
-		return dec.BytesRead(), err
+		return err
 	}
 	if err := dec.Decode(&vk.G2.Gamma); err != nil {
-		return dec.BytesRead(), err
+		return err
 	}
 	if err := dec.Decode(&vk.G1.Delta); err != nil {
-		return dec.BytesRead(), err
+		return err
 	}
 	if err := dec.Decode(&vk.G2.Delta); err != nil {
-		return dec.BytesRead(), err
+		return err
 	}
 
 	// uint32(len(Kvk)),[Kvk]1
 	if err := dec.Decode(&vk.G1.K); err != nil {
-		return dec.BytesRead(), err
+		return err
 	}
 
-	// recompute vk.e (e(α, β)) and  -[δ]2, -[γ]2
-	var err error
-	vk.e, err = curve.Pair([]curve.G1Affine{vk.G1.Alpha}, []curve.G2Affine{vk.G2.Beta})
+	// circuit digest
+	if err := dec.Decode(&vk.CircuitDigest); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// WriteTo writes binary encoding of the key to writer:
+// the VerifyingKey (uncompressed, see VerifyingKey.WriteRawTo) followed by e(α, β)
+// and uint32(len(lines)),lines for the lines of -[γ]2 and -[δ]2
+func (pvk *PreparedVerifyingKey) WriteTo(w io.Writer) (int64, error) {
+	n, err := pvk.VerifyingKey.writeTo(w, true)
 	if err != nil {
+		return n, err
+	}
+
+	enc := curve.NewEncoder(w, curve.RawEncoding())
+	e := pvk.e.Bytes()
+	toEncode := []interface{}{
+		&e,
+		[]curve.G2Affine(pvk.gammaNegLines),
+		[]curve.G2Affine(pvk.deltaNegLines),
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return n + enc.BytesWritten(), err
+		}
+	}
+
+	return n + enc.BytesWritten(), nil
+}
+
+// WriteRawTo has the same behavior as WriteTo, as the points of a PreparedVerifyingKey
+// are not compressed
+func (pvk *PreparedVerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return pvk.WriteTo(w)
+}
+
+// ReadFrom attempts to decode a PreparedVerifyingKey written by WriteTo.
+//
+// The precomputations are not checked against the points of the VerifyingKey, so they must
+// come from a trusted source, like the VerifyingKey itself.
+func (pvk *PreparedVerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	return pvk.readFrom(r)
+}
+
+// UnsafeReadFrom has the same behavior as ReadFrom, except that it will not check that decode points
+// are on the curve and in the correct subgroup.
+func (pvk *PreparedVerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return pvk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (pvk *PreparedVerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	dec := curve.NewDecoder(r, decOptions...)
+	if err := pvk.VerifyingKey.decode(dec); err != nil {
 		return dec.BytesRead(), err
 	}
-	vk.G2.deltaNeg.Neg(&vk.G2.Delta)
-	vk.G2.gammaNeg.Neg(&vk.G2.Gamma)
+	pvk.G2.deltaNeg.Neg(&pvk.G2.Delta)
+	pvk.G2.gammaNeg.Neg(&pvk.G2.Gamma)
 
-	return dec.BytesRead(), nil
+	// the coefficients of the lines are not points of the curve
+	precomputations := curve.NewDecoder(r, curve.NoSubgroupChecks())
+	var e [curve.SizeOfGT]byte
+	toDecode := []interface{}{
+		&e,
+		(*[]curve.G2Affine)(&pvk.gammaNegLines),
+		(*[]curve.G2Affine)(&pvk.deltaNegLines),
+	}
+	for _, v := range toDecode {
+		if err := precomputations.Decode(v); err != nil {
+			return dec.BytesRead() + precomputations.BytesRead(), err
+		}
+	}
+	if err := pvk.e.SetBytes(e[:]); err != nil {
+		return dec.BytesRead() + precomputations.BytesRead(), err
+	}
+
+	return dec.BytesRead() + precomputations.BytesRead(), nil
 }
 
 // WriteTo writes binary encoding of the key elements to writer
@@ -226,6 +361,7 @@ func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 		pk.NbInfinityB,
 		pk.InfinityA,
 		pk.InfinityB,
+		&pk.CircuitDigest,
 	}
 
 	for _, v := range toEncode {
@@ -291,6 +427,9 @@ func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder))
 	if err := dec.Decode(&pk.InfinityB); err != nil {
 		return n + dec.BytesRead(), err
 	}
+	if err := dec.Decode(&pk.CircuitDigest); err != nil {
+		return n + dec.BytesRead(), err
+	}
 
 	return n + dec.BytesRead(), nil
 }
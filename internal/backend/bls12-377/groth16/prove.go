@@ -32,7 +32,6 @@ import (
 	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
 	"math/big"
-	"runtime"
 	"time"
 )
 
@@ -54,33 +53,194 @@ func (proof *Proof) CurveID() ecc.ID {
 	return curve.ID
 }
 
+// Solution is a solved witness of a R1CS, produced by Solve and consumed by ProveFromSolution.
+//
+// It holds the wire values and the a, b, c vectors (ab-c = hz), so that the MSM and FFT
+// heavy part of the prover can run on another machine than the solver.
+type Solution struct {
+	// CircuitDigest is the digest of the solved R1CS (see R1CS.Digest)
+	CircuitDigest [32]byte
+
+	// Wires = [ONE_WIRE | public | secret | internal]
+	Wires []fr.Element
+
+	// A, B, C are the evaluations of the constraints linear expressions on the wires
+	A, B, C []fr.Element
+}
+
+// CurveID returns the curveID
+func (solution *Solution) CurveID() ecc.ID {
+	return curve.ID
+}
+
 // Prove generates the proof of knoweldge of a r1cs with full witness (secret + public part).
 func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, opt backend.ProverConfig) (*Proof, error) {
+	if err := checkWitnessSize(r1cs, witness); err != nil {
+		return nil, err
+	}
+	digest := r1cs.Digest()
+	if digest != pk.CircuitDigest {
+		return nil, fmt.Errorf("%w: circuit digest is %x, proving key was generated for %x", backend.ErrCircuitMismatch, digest, pk.CircuitDigest)
+	}
+
+	solution := newSolution(r1cs)
+	if err := solve(r1cs, witness, solution, opt); err != nil {
+		return nil, err
+	}
+	solution.CircuitDigest = digest
+
+	return proveFromSolution(r1cs, pk, solution, &proverBuffers{}, opt)
+}
+
+// Solve solves the R1CS with full witness (secret + public part) and returns the Solution
+// to be proven with ProveFromSolution.
+//
+// if opt.Force is set, the internal wires of an invalid witness are filled with random values.
+func Solve(r1cs *cs.R1CS, witness bls12_377witness.Witness, opt backend.ProverConfig) (*Solution, error) {
+	if err := checkWitnessSize(r1cs, witness); err != nil {
+		return nil, err
+	}
+
+	solution := newSolution(r1cs)
+	if err := solve(r1cs, witness, solution, opt); err != nil {
+		return nil, err
+	}
+	solution.CircuitDigest = r1cs.Digest()
+
+	if opt.Progress != nil {
+		opt.Progress(backend.PhaseDone, 100)
+	}
+	return solution, nil
+}
+
+// ProveFromSolution generates the proof of knowledge of a r1cs from a Solution returned by Solve,
+// skipping the solver.
+//
+// The solution vectors are reused by the prover and must not be used after this call.
+func ProveFromSolution(r1cs *cs.R1CS, pk *ProvingKey, solution *Solution, opt backend.ProverConfig) (*Proof, error) {
+	digest := r1cs.Digest()
+	if digest != pk.CircuitDigest {
+		return nil, fmt.Errorf("%w: circuit digest is %x, proving key was generated for %x", backend.ErrCircuitMismatch, digest, pk.CircuitDigest)
+	}
+	if digest != solution.CircuitDigest {
+		return nil, fmt.Errorf("%w: circuit digest is %x, solution was computed for %x", backend.ErrCircuitMismatch, digest, solution.CircuitDigest)
+	}
+
+	nbWires := r1cs.NbPublicVariables + r1cs.NbSecretVariables + r1cs.NbInternalVariables
+	if len(solution.Wires) != nbWires {
+		return nil, fmt.Errorf("invalid solution size, got %d wires, expected %d", len(solution.Wires), nbWires)
+	}
+	nbConstraints := len(r1cs.Constraints)
+	if len(solution.A) != nbConstraints || len(solution.B) != nbConstraints || len(solution.C) != nbConstraints {
+		return nil, fmt.Errorf("invalid solution size, expected a, b, c of size %d", nbConstraints)
+	}
+
+	return proveFromSolution(r1cs, pk, solution, &proverBuffers{}, opt)
+}
+
+// Rerandomize returns a fresh proof of the same statement as proof, that verifies with vk.
+//
+// With r ≠ 0 and s sampled from opt.RandomSource, the new proof is
+//
+//	Ar' = r⁻¹.Ar, Bs' = r.Bs + rs.[δ]2, Krs' = Krs + s.Ar
+//
+// so that e(Ar', Bs') = e(Ar, Bs).e(s.Ar, [δ]2) and the verifier equation still holds.
+// The proof is not verified, but its points must be in the correct subgroups.
+func Rerandomize(proof *Proof, vk *VerifyingKey, opt backend.ProverConfig) (*Proof, error) {
+	if !proof.isValid() {
+		return nil, errCorrectSubgroupCheckFailed
+	}
+
+	// sample random r and s
+	var _r, _s, _rInv, _rs fr.Element
+	for _r.IsZero() {
+		if err := setRandom(&_r, opt.RandomSource); err != nil {
+			return nil, err
+		}
+	}
+	if err := setRandom(&_s, opt.RandomSource); err != nil {
+		return nil, err
+	}
+	_rInv.Inverse(&_r)
+	_rs.Mul(&_r, &_s)
+
+	var r, s, rInv, rs big.Int
+	_r.ToBigIntRegular(&r)
+	_s.ToBigIntRegular(&s)
+	_rInv.ToBigIntRegular(&rInv)
+	_rs.ToBigIntRegular(&rs)
+
+	res := &Proof{}
+	res.Ar.ScalarMultiplication(&proof.Ar, &rInv)
+
+	var deltaRs curve.G2Affine
+	deltaRs.ScalarMultiplication(&vk.G2.Delta, &rs)
+	res.Bs.ScalarMultiplication(&proof.Bs, &r)
+	res.Bs.Add(&res.Bs, &deltaRs)
+
+	res.Krs.ScalarMultiplication(&proof.Ar, &s)
+	res.Krs.Add(&res.Krs, &proof.Krs)
+
+	return res, nil
+}
+
+func checkWitnessSize(r1cs *cs.R1CS, witness bls12_377witness.Witness) error {
 	if len(witness) != int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables) {
-		return nil, fmt.Errorf("invalid witness size, got %d, expected %d = %d (public) + %d (secret)", len(witness), int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables), r1cs.NbPublicVariables, r1cs.NbSecretVariables)
+		return fmt.Errorf("invalid witness size, got %d, expected %d = %d (public) + %d (secret)", len(witness), int(r1cs.NbPublicVariables-1+r1cs.NbSecretVariables), r1cs.NbPublicVariables, r1cs.NbSecretVariables)
 	}
+	return nil
+}
 
-	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Int("nbConstraints", len(r1cs.Constraints)).Str("backend", "groth16").Logger()
+// newSolution allocates the a, b, c vectors of a Solution of the R1CS
+func newSolution(r1cs *cs.R1CS) *Solution {
+	// a, b, c are padded up to the FFT domain cardinality in computeH
+	capacity := ecc.NextPowerOfTwo(uint64(len(r1cs.Constraints)))
+	return &Solution{
+		A: make([]fr.Element, len(r1cs.Constraints), capacity),
+		B: make([]fr.Element, len(r1cs.Constraints), capacity),
+		C: make([]fr.Element, len(r1cs.Constraints), capacity),
+	}
+}
+
+// solve solves the R1CS and computes the a, b, c vectors in solution (see newSolution)
+func solve(r1cs *cs.R1CS, witness bls12_377witness.Witness, solution *Solution, opt backend.ProverConfig) error {
+	if err := opt.Checkpoint("solve", 0); err != nil {
+		return err
+	}
 
-	// solve the R1CS and compute the a, b, c vectors
-	a := make([]fr.Element, len(r1cs.Constraints), pk.Domain.Cardinality)
-	b := make([]fr.Element, len(r1cs.Constraints), pk.Domain.Cardinality)
-	c := make([]fr.Element, len(r1cs.Constraints), pk.Domain.Cardinality)
-	var wireValues []fr.Element
 	var err error
-	if wireValues, err = r1cs.Solve(witness, a, b, c, opt); err != nil {
+	if solution.Wires, err = r1cs.Solve(witness, solution.A, solution.B, solution.C, opt); err != nil {
 		if !opt.Force {
-			return nil, err
+			return err
 		} else {
 			// we need to fill wireValues with random values else multi exps don't do much
 			var r fr.Element
-			_, _ = r.SetRandom()
-			for i := r1cs.NbPublicVariables + r1cs.NbSecretVariables; i < len(wireValues); i++ {
-				wireValues[i] = r
+			if err := setRandom(&r, opt.RandomSource); err != nil {
+				return err
+			}
+			for i := r1cs.NbPublicVariables + r1cs.NbSecretVariables; i < len(solution.Wires); i++ {
+				solution.Wires[i] = r
 				r.Double(&r)
 			}
 		}
 	}
+	return nil
+}
+
+// proverBuffers holds the vectors of a proof that are not part of its Solution, so that a Prover
+// can reuse them from one proof to the next
+type proverBuffers struct {
+	wireValuesA, wireValuesB []fr.Element
+}
+
+func proveFromSolution(r1cs *cs.R1CS, pk *ProvingKey, solution *Solution, buf *proverBuffers, opt backend.ProverConfig) (*Proof, error) {
+	if err := opt.Checkpoint("fft", 20); err != nil {
+		return nil, err
+	}
+
+	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Int("nbConstraints", len(r1cs.Constraints)).Str("backend", "groth16").Logger()
+
+	wireValues, a, b, c := solution.Wires, solution.A, solution.B, solution.C
 	start := time.Now()
 
 	// set the wire values in regular form
@@ -88,54 +248,15 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, opt
 		for i := start; i < end; i++ {
 			wireValues[i].FromMont()
 		}
-	})
-
-	// H (witness reduction / FFT part)
-	var h []fr.Element
-	chHDone := make(chan struct{}, 1)
-	go func() {
-		h = computeH(a, b, c, &pk.Domain)
-		a = nil
-		b = nil
-		c = nil
-		chHDone <- struct{}{}
-	}()
-
-	// we need to copy and filter the wireValues for each multi exp
-	// as pk.G1.A, pk.G1.B and pk.G2.B may have (a significant) number of point at infinity
-	var wireValuesA, wireValuesB []fr.Element
-	chWireValuesA, chWireValuesB := make(chan struct{}, 1), make(chan struct{}, 1)
-
-	go func() {
-		wireValuesA = make([]fr.Element, len(wireValues)-int(pk.NbInfinityA))
-		for i, j := 0, 0; j < len(wireValuesA); i++ {
-			if pk.InfinityA[i] {
-				continue
-			}
-			wireValuesA[j] = wireValues[i]
-			j++
-		}
-		close(chWireValuesA)
-	}()
-	go func() {
-		wireValuesB = make([]fr.Element, len(wireValues)-int(pk.NbInfinityB))
-		for i, j := 0, 0; j < len(wireValuesB); i++ {
-			if pk.InfinityB[i] {
-				continue
-			}
-			wireValuesB[j] = wireValues[i]
-			j++
-		}
-		close(chWireValuesB)
-	}()
+	}, opt.NbTasks)
 
 	// sample random r and s
 	var r, s big.Int
 	var _r, _s, _kr fr.Element
-	if _, err := _r.SetRandom(); err != nil {
+	if err := setRandom(&_r, opt.RandomSource); err != nil {
 		return nil, err
 	}
-	if _, err := _s.SetRandom(); err != nil {
+	if err := setRandom(&_s, opt.RandomSource); err != nil {
 		return nil, err
 	}
 	_kr.Mul(&_r, &_s).Neg(&_kr)
@@ -146,18 +267,43 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, opt
 	_r.ToBigInt(&r)
 	_s.ToBigInt(&s)
 
+	// H (witness reduction / FFT part)
+	var h []fr.Element
+	chHDone := make(chan struct{}, 1)
+	utils.Go(opt.NbTasks, func() {
+		h = computeH(a, b, c, &pk.Domain, opt.NbTasks)
+		a = nil
+		b = nil
+		c = nil
+		chHDone <- struct{}{}
+	})
+
+	// we need to copy and filter the wireValues for each multi exp
+	// as pk.G1.A, pk.G1.B and pk.G2.B may have (a significant) number of point at infinity
+	wireValuesA, wireValuesB := buf.wireValuesA, buf.wireValuesB
+	chWireValuesA, chWireValuesB := make(chan struct{}, 1), make(chan struct{}, 1)
+
+	utils.Go(opt.NbTasks, func() {
+		wireValuesA = filterInfinity(wireValuesA, wireValues, pk.InfinityA, pk.NbInfinityA)
+		close(chWireValuesA)
+	})
+	utils.Go(opt.NbTasks, func() {
+		wireValuesB = filterInfinity(wireValuesB, wireValues, pk.InfinityB, pk.NbInfinityB)
+		close(chWireValuesB)
+	})
+
 	// computes r[δ], s[δ], kr[δ]
-	deltas := curve.BatchScalarMultiplicationG1(&pk.G1.Delta, []fr.Element{_r, _s, _kr})
+	deltas := boundedBatchScalarMultiplicationG1(&pk.G1.Delta, []fr.Element{_r, _s, _kr}, opt.NbTasks)
 
 	proof := &Proof{}
 	var bs1, ar curve.G1Jac
 
-	n := runtime.NumCPU()
+	n := utils.NbTasks(opt.NbTasks)
 
 	chBs1Done := make(chan error, 1)
 	computeBS1 := func() {
 		<-chWireValuesB
-		if _, err := bs1.MultiExp(pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if _, err := boundedMultiExpG1(&bs1, pk.G1.B, wireValuesB, ecc.MultiExpConfig{NbTasks: n / 2}, opt.NbTasks); err != nil {
 			chBs1Done <- err
 			close(chBs1Done)
 			return
@@ -170,7 +316,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, opt
 	chArDone := make(chan error, 1)
 	computeAR1 := func() {
 		<-chWireValuesA
-		if _, err := ar.MultiExp(pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if _, err := boundedMultiExpG1(&ar, pk.G1.A, wireValuesA, ecc.MultiExpConfig{NbTasks: n / 2}, opt.NbTasks); err != nil {
 			chArDone <- err
 			close(chArDone)
 			return
@@ -188,40 +334,37 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, opt
 
 		var krs, krs2, p1 curve.G1Jac
 		chKrs2Done := make(chan error, 1)
-		go func() {
-			_, err := krs2.MultiExp(pk.G1.Z, h, ecc.MultiExpConfig{NbTasks: n / 2})
+		utils.Go(opt.NbTasks, func() {
+			_, err := boundedMultiExpG1(&krs2, pk.G1.Z, h, ecc.MultiExpConfig{NbTasks: n / 2}, opt.NbTasks)
 			chKrs2Done <- err
-		}()
-		if _, err := krs.MultiExp(pk.G1.K, wireValues[r1cs.NbPublicVariables:], ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
-			chKrsDone <- err
-			return
-		}
+		})
+		_, err := boundedMultiExpG1(&krs, pk.G1.K, wireValues[r1cs.NbPublicVariables:], ecc.MultiExpConfig{NbTasks: n / 2}, opt.NbTasks)
 		krs.AddMixed(&deltas[2])
-		n := 3
-		for n != 0 {
+
+		// on error, we still wait for krs2, ar and bs1, such that no MSM outlives the prover
+		chKrs2, chAr, chBs1 := chKrs2Done, chArDone, chBs1Done
+		for n := 3; n != 0; n-- {
+			var errMSM error
 			select {
-			case err := <-chKrs2Done:
-				if err != nil {
-					chKrsDone <- err
-					return
-				}
+			case errMSM = <-chKrs2:
+				chKrs2 = nil
 				krs.AddAssign(&krs2)
-			case err := <-chArDone:
-				if err != nil {
-					chKrsDone <- err
-					return
-				}
+			case errMSM = <-chAr:
+				chAr = nil
 				p1.ScalarMultiplication(&ar, &s)
 				krs.AddAssign(&p1)
-			case err := <-chBs1Done:
-				if err != nil {
-					chKrsDone <- err
-					return
-				}
+			case errMSM = <-chBs1:
+				chBs1 = nil
 				p1.ScalarMultiplication(&bs1, &r)
 				krs.AddAssign(&p1)
 			}
-			n--
+			if err == nil {
+				err = errMSM
+			}
+		}
+		if err != nil {
+			chKrsDone <- err
+			return
 		}
 
 		proof.Krs.FromJacobian(&krs)
@@ -238,7 +381,7 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, opt
 			nbTasks *= 2
 		}
 		<-chWireValuesB
-		if _, err := Bs.MultiExp(pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+		if _, err := boundedMultiExpG2(&Bs, pk.G2.B, wireValuesB, ecc.MultiExpConfig{NbTasks: nbTasks}, opt.NbTasks); err != nil {
 			return err
 		}
 
@@ -254,11 +397,19 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, opt
 	// wait for FFT to end, as it uses all our CPUs
 	<-chHDone
 
-	// schedule our proof part computations
-	go computeKRS()
-	go computeAR1()
-	go computeBS1()
+	if err := opt.Checkpoint("msm", 50); err != nil {
+		// the wire values are being filtered in buffers that outlive this call
+		<-chWireValuesA
+		<-chWireValuesB
+		return nil, err
+	}
+
+	// schedule our proof part computations; computeKRS waits for ar and bs1
+	utils.Go(opt.NbTasks, computeAR1)
+	utils.Go(opt.NbTasks, computeBS1)
+	utils.Go(opt.NbTasks, computeKRS)
 	if err := computeBS2(); err != nil {
+		<-chKrsDone // computeKRS returns once ar and bs1 are done
 		return nil, err
 	}
 
@@ -267,12 +418,36 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bls12_377witness.Witness, opt
 		return nil, err
 	}
 
+	buf.wireValuesA, buf.wireValuesB = wireValuesA, wireValuesB
+
 	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
 
+	if opt.Progress != nil {
+		opt.Progress(backend.PhaseDone, 100)
+	}
 	return proof, nil
 }
 
-func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
+// filterInfinity returns the wire values whose basis point is not the point at infinity,
+// reusing the memory of dst if it is large enough
+func filterInfinity(dst, wireValues []fr.Element, infinity []bool, nbInfinity uint64) []fr.Element {
+	filtered := dst[:0]
+	if size := len(wireValues) - int(nbInfinity); cap(filtered) >= size {
+		filtered = filtered[:size]
+	} else {
+		filtered = make([]fr.Element, size)
+	}
+	for i, j := 0, 0; j < len(filtered); i++ {
+		if infinity[i] {
+			continue
+		}
+		filtered[j] = wireValues[i]
+		j++
+	}
+	return filtered
+}
+
+func computeH(a, b, c []fr.Element, domain *fft.Domain, nbTasks int) []fr.Element {
 	// H part of Krs
 	// Compute H (hz=ab-c, where z=-2 on ker X^n+1 (z(x)=x^n-1))
 	// 	1 - _a = ifft(a), _b = ifft(b), _c = ifft(c)
@@ -288,13 +463,13 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 	c = append(c, padding...)
 	n = len(a)
 
-	domain.FFTInverse(a, fft.DIF)
-	domain.FFTInverse(b, fft.DIF)
-	domain.FFTInverse(c, fft.DIF)
+	boundedFFTInverse(domain, a, fft.DIF, false, nbTasks)
+	boundedFFTInverse(domain, b, fft.DIF, false, nbTasks)
+	boundedFFTInverse(domain, c, fft.DIF, false, nbTasks)
 
-	domain.FFT(a, fft.DIT, true)
-	domain.FFT(b, fft.DIT, true)
-	domain.FFT(c, fft.DIT, true)
+	boundedFFT(domain, a, fft.DIT, true, nbTasks)
+	boundedFFT(domain, b, fft.DIT, true, nbTasks)
+	boundedFFT(domain, c, fft.DIT, true, nbTasks)
 
 	var den, one fr.Element
 	one.SetOne()
@@ -309,16 +484,16 @@ func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 				Sub(&a[i], &c[i]).
 				Mul(&a[i], &den)
 		}
-	})
+	}, nbTasks)
 
 	// ifft_coset
-	domain.FFTInverse(a, fft.DIF, true)
+	boundedFFTInverse(domain, a, fft.DIF, true, nbTasks)
 
 	utils.Parallelize(len(a), func(start, end int) {
 		for i := start; i < end; i++ {
 			a[i].FromMont()
 		}
-	})
+	}, nbTasks)
 
 	return a
 }
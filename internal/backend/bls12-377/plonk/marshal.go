@@ -19,9 +19,14 @@ package plonk
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
 
-	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/kzg"
 	"io"
+	"runtime"
 )
 
 // WriteTo writes binary encoding of Proof to w
@@ -80,10 +85,67 @@ func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
 	return n + n2 + dec.BytesRead(), err
 }
 
+// WriteTo writes binary encoding of Solution to w
+// CircuitDigest | L | R | O
+func (solution *Solution) WriteTo(w io.Writer) (int64, error) {
+	enc := curve.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&solution.CircuitDigest,
+		solution.L,
+		solution.R,
+		solution.O,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads binary representation of Solution from r
+func (solution *Solution) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&solution.CircuitDigest,
+		&solution.L,
+		&solution.R,
+		&solution.O,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
 // WriteTo writes binary encoding of ProvingKey to w
+// points are compressed
+// use WriteRawTo(...) to encode the key without point compression
 func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.writeTo(w, false)
+}
+
+// WriteRawTo writes binary encoding of ProvingKey to w
+// points are not compressed
+// use WriteTo(...) to encode the key with point compression
+func (pk *ProvingKey) WriteRawTo(w io.Writer) (n int64, err error) {
+	return pk.writeTo(w, true)
+}
+
+// writeTo serialization format:
+// VerifyingKey | Domain[0] | Domain[1] | Ql | Qr | Qm | Qo | CQk | LQk | S1Canonical | S2Canonical | S3Canonical | Permutation
+// followed by uint32(len(G1)),G1 for the powers of α in G1 of the SRS used by the prover (empty if it is not set)
+func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (n int64, err error) {
 	// encode the verifying key
-	n, err = pk.Vk.WriteTo(w)
+	n, err = pk.Vk.writeTo(w, raw)
 	if err != nil {
 		return
 	}
@@ -106,7 +168,22 @@ func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
 		return n, errors.New("invalid permutation size, expected 3*domain cardinality")
 	}
 
-	enc := curve.NewEncoder(w)
+	// the prover commits to polynomials of size at most Domain[0].Cardinality+3, the SRS
+	// may be larger
+	var g1 []curve.G1Affine
+	if pk.KZGSRS != nil {
+		g1 = pk.KZGSRS.G1
+		if maxSize := int(pk.Domain[0].Cardinality) + 3; len(g1) > maxSize {
+			g1 = g1[:maxSize]
+		}
+	}
+
+	var enc *curve.Encoder
+	if raw {
+		enc = curve.NewEncoder(w, curve.RawEncoding())
+	} else {
+		enc = curve.NewEncoder(w)
+	}
 	// note: type Polynomial, which is handled by default binary.Write(...) op and doesn't
 	// encode the size (nor does it convert from Montgomery to Regular form)
 	// so we explicitly transmit []fr.Element
@@ -121,6 +198,7 @@ func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
 		([]fr.Element)(pk.S2Canonical),
 		([]fr.Element)(pk.S3Canonical),
 		pk.Permutation,
+		g1,
 	}
 
 	for _, v := range toEncode {
@@ -133,9 +211,20 @@ func (pk *ProvingKey) WriteTo(w io.Writer) (n int64, err error) {
 }
 
 // ReadFrom reads from binary representation in r into ProvingKey
+// ProvingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
 func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	return pk.readFrom(r)
+}
+
+// UnsafeReadFrom behaves like ReadFrom excepts it doesn't check if the decoded points are on the curve
+// or in the correct subgroup
+func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return pk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
 	pk.Vk = &VerifyingKey{}
-	n, err := pk.Vk.ReadFrom(r)
+	n, err := pk.Vk.readFrom(r, decOptions...)
 	if err != nil {
 		return n, err
 	}
@@ -154,7 +243,8 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 
 	pk.Permutation = make([]int64, 3*pk.Domain[0].Cardinality)
 
-	dec := curve.NewDecoder(r)
+	var g1 []curve.G1Affine
+	dec := curve.NewDecoder(r, decOptions...)
 	toDecode := []interface{}{
 		(*[]fr.Element)(&pk.Ql),
 		(*[]fr.Element)(&pk.Qr),
@@ -166,6 +256,7 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 		(*[]fr.Element)(&pk.S2Canonical),
 		(*[]fr.Element)(&pk.S3Canonical),
 		&pk.Permutation,
+		&g1,
 	}
 
 	for _, v := range toDecode {
@@ -174,13 +265,53 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 		}
 	}
 
+	// the G2 points of the SRS are the ones of the verifying key
+	pk.KZGSRS = nil
+	if len(g1) != 0 {
+		if pk.Vk.KZGSRS == nil {
+			return n + dec.BytesRead(), errors.New("proving key has a kzg srs but not its verifying key")
+		}
+		pk.KZGSRS = &kzg.SRS{G1: g1, G2: pk.Vk.KZGSRS.G2}
+	}
+
+	// the coset shift and the evaluations of the permutation on the big domain are not serialized
+	pk.Vk.CosetShift.Set(&pk.Domain[0].FrMultiplicativeGen)
+	evaluatePermutationBigDomain(pk, runtime.NumCPU())
+
 	return n + dec.BytesRead(), nil
 
 }
 
 // WriteTo writes binary encoding of VerifyingKey to w
+// points are compressed
+// use WriteRawTo(...) to encode the key without point compression
 func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
-	enc := curve.NewEncoder(w)
+	return vk.writeTo(w, false)
+}
+
+// WriteRawTo writes binary encoding of VerifyingKey to w
+// points are not compressed
+// use WriteTo(...) to encode the key with point compression
+func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
+	return vk.writeTo(w, true)
+}
+
+// writeTo serialization format:
+// Size | SizeInv | Generator | NbPublicVariables | S[0] | S[1] | S[2] | Ql | Qr | Qm | Qo | Qk | CircuitDigest
+// followed by [1]1, [1]2, [α]2 for the points of the SRS used by the verifier (at infinity if it is not set)
+func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
+	var enc *curve.Encoder
+	if raw {
+		enc = curve.NewEncoder(w, curve.RawEncoding())
+	} else {
+		enc = curve.NewEncoder(w)
+	}
+
+	var srsG1 curve.G1Affine
+	var srsG2 [2]curve.G2Affine
+	if vk.KZGSRS != nil {
+		srsG1, srsG2 = vk.KZGSRS.G1[0], vk.KZGSRS.G2
+	}
 
 	toEncode := []interface{}{
 		vk.Size,
@@ -195,6 +326,10 @@ func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 		&vk.Qm,
 		&vk.Qo,
 		&vk.Qk,
+		&vk.CircuitDigest,
+		&srsG1,
+		&srsG2[0],
+		&srsG2[1],
 	}
 
 	for _, v := range toEncode {
@@ -207,8 +342,20 @@ func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 }
 
 // ReadFrom reads from binary representation in r into VerifyingKey
+// VerifyingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
-	dec := curve.NewDecoder(r)
+	return vk.readFrom(r)
+}
+
+// UnsafeReadFrom has the same behavior as ReadFrom, except that it will not check that decode points
+// are on the curve and in the correct subgroup.
+func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return vk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	dec := curve.NewDecoder(r, decOptions...)
+	srs := kzg.SRS{G1: make([]curve.G1Affine, 1)}
 	toDecode := []interface{}{
 		&vk.Size,
 		&vk.SizeInv,
@@ -222,6 +369,10 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		&vk.Qm,
 		&vk.Qo,
 		&vk.Qk,
+		&vk.CircuitDigest,
+		&srs.G1[0],
+		&srs.G2[0],
+		&srs.G2[1],
 	}
 
 	for _, v := range toDecode {
@@ -230,5 +381,78 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		}
 	}
 
+	// [1]1 is at infinity if the key was serialized without SRS
+	vk.KZGSRS = nil
+	if !srs.G1[0].IsInfinity() {
+		vk.KZGSRS = &srs
+	}
+
+	// the coset shift is not serialized, it is the multiplicative generator of every fft domain
+	vk.CosetShift.Set(&fft.NewDomain(1).FrMultiplicativeGen)
+
 	return dec.BytesRead(), nil
 }
+
+// WriteTo writes binary encoding of PreparedVerifyingKey to w:
+// the VerifyingKey followed by uint32(len(lines)),lines for the lines of [1]2 and [α]2, uncompressed
+// use WriteRawTo(...) to encode the VerifyingKey without point compression
+func (pvk *PreparedVerifyingKey) WriteTo(w io.Writer) (int64, error) {
+	return pvk.writeTo(w, false)
+}
+
+// WriteRawTo has the same behavior as WriteTo, except that the points of the VerifyingKey
+// are not compressed
+func (pvk *PreparedVerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
+	return pvk.writeTo(w, true)
+}
+
+func (pvk *PreparedVerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
+	n, err := pvk.VerifyingKey.writeTo(w, raw)
+	if err != nil {
+		return n, err
+	}
+
+	// the coefficients of the lines are not points of the curve, they can't be compressed
+	enc := curve.NewEncoder(w, curve.RawEncoding())
+	for i := range pvk.g2Lines {
+		if err := enc.Encode([]curve.G2Affine(pvk.g2Lines[i])); err != nil {
+			return n + enc.BytesWritten(), err
+		}
+	}
+
+	return n + enc.BytesWritten(), nil
+}
+
+// ReadFrom reads from binary representation in r into PreparedVerifyingKey.
+//
+// The precomputations are not checked against the points of the SRS, so the key
+// must come from a trusted source, like the VerifyingKey itself.
+func (pvk *PreparedVerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	return pvk.readFrom(r)
+}
+
+// UnsafeReadFrom has the same behavior as ReadFrom, except that it will not check that decode points
+// are on the curve and in the correct subgroup.
+func (pvk *PreparedVerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return pvk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (pvk *PreparedVerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	n, err := pvk.VerifyingKey.readFrom(r, decOptions...)
+	if err != nil {
+		return n, err
+	}
+	if pvk.KZGSRS == nil {
+		return n, errors.New("prepared verifying key has no kzg srs")
+	}
+
+	// the coefficients of the lines are not points of the curve
+	dec := curve.NewDecoder(r, curve.NoSubgroupChecks())
+	for i := range pvk.g2Lines {
+		if err := dec.Decode((*[]curve.G2Affine)(&pvk.g2Lines[i])); err != nil {
+			return n + dec.BytesRead(), err
+		}
+	}
+
+	return n + dec.BytesRead(), nil
+}
@@ -18,9 +18,11 @@ package plonk
 
 import (
 	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
 	"math/big"
 	"math/bits"
-	"runtime"
 	"sync"
 	"time"
 
@@ -36,6 +38,7 @@ import (
 
 	"github.com/consensys/gnark/internal/backend/bls12-377/cs"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/utils"
@@ -60,39 +63,183 @@ type Proof struct {
 	ZShiftedOpening kzg.OpeningProof
 }
 
+// Solution is a solved witness of a SparseR1CS, produced by Solve and consumed by ProveFromSolution.
+//
+// It holds the l, r, o vectors in Lagrange form on the small domain (public inputs placeholders,
+// constraints and padding), so that the FFT and MSM heavy part of the prover can run on another
+// machine than the solver.
+type Solution struct {
+	// CircuitDigest is the digest of the solved SparseR1CS (see SparseR1CS.Digest)
+	CircuitDigest [32]byte
+
+	L, R, O []fr.Element
+}
+
+// CurveID returns the curveID
+func (solution *Solution) CurveID() ecc.ID {
+	return curve.ID
+}
+
 // Prove from the public data
 func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witness, opt backend.ProverConfig) (*Proof, error) {
+	if err := checkWitnessSize(spr, fullWitness); err != nil {
+		return nil, err
+	}
+	digest := spr.Digest()
+	if digest != pk.Vk.CircuitDigest {
+		return nil, fmt.Errorf("%w: circuit digest is %x, proving key was generated for %x", backend.ErrCircuitMismatch, digest, pk.Vk.CircuitDigest)
+	}
 
-	log := logger.Logger().With().Str("curve", spr.CurveID().String()).Int("nbConstraints", len(spr.Constraints)).Str("backend", "plonk").Logger()
-	start := time.Now()
-	// pick a hash function that will be used to derive the challenges
-	hFunc := sha256.New()
+	solution := newSolution(spr)
+	if err := solve(spr, fullWitness, solution, opt); err != nil {
+		return nil, err
+	}
+	solution.CircuitDigest = digest
 
-	// create a transcript manager to apply Fiat Shamir
-	fs := fiatshamir.NewTranscript(hFunc, "gamma", "beta", "alpha", "zeta")
+	return proveFromSolution(spr, pk, solution, nil, opt)
+}
 
-	// result
-	proof := &Proof{}
+// Solve solves the SparseR1CS with full witness (secret + public part) and returns the Solution
+// to be proven with ProveFromSolution.
+//
+// if opt.Force is set, the internal wires of an invalid witness are filled with random values.
+func Solve(spr *cs.SparseR1CS, fullWitness bls12_377witness.Witness, opt backend.ProverConfig) (*Solution, error) {
+	if err := checkWitnessSize(spr, fullWitness); err != nil {
+		return nil, err
+	}
+
+	solution := newSolution(spr)
+	if err := solve(spr, fullWitness, solution, opt); err != nil {
+		return nil, err
+	}
+	solution.CircuitDigest = spr.Digest()
+
+	if opt.Progress != nil {
+		opt.Progress(backend.PhaseDone, 100)
+	}
+	return solution, nil
+}
+
+// ProveFromSolution generates a proof from a Solution returned by Solve, skipping the solver.
+func ProveFromSolution(spr *cs.SparseR1CS, pk *ProvingKey, solution *Solution, opt backend.ProverConfig) (*Proof, error) {
+	digest := spr.Digest()
+	if digest != pk.Vk.CircuitDigest {
+		return nil, fmt.Errorf("%w: circuit digest is %x, proving key was generated for %x", backend.ErrCircuitMismatch, digest, pk.Vk.CircuitDigest)
+	}
+	if digest != solution.CircuitDigest {
+		return nil, fmt.Errorf("%w: circuit digest is %x, solution was computed for %x", backend.ErrCircuitMismatch, digest, solution.CircuitDigest)
+	}
+
+	size := int(pk.Domain[0].Cardinality)
+	if len(solution.L) != size || len(solution.R) != size || len(solution.O) != size {
+		return nil, fmt.Errorf("invalid solution size, expected l, r, o of size %d", size)
+	}
+
+	return proveFromSolution(spr, pk, solution, nil, opt)
+}
+
+func checkWitnessSize(spr *cs.SparseR1CS, fullWitness bls12_377witness.Witness) error {
+	if len(fullWitness) != int(spr.NbPublicVariables+spr.NbSecretVariables) {
+		return fmt.Errorf("invalid witness size, got %d, expected %d = %d (public) + %d (secret)", len(fullWitness), int(spr.NbPublicVariables+spr.NbSecretVariables), spr.NbPublicVariables, spr.NbSecretVariables)
+	}
+	return nil
+}
+
+// newSolution allocates the l, r, o vectors of a Solution of the SparseR1CS
+func newSolution(spr *cs.SparseR1CS) *Solution {
+	// the size of the small domain is set in Setup
+	size := ecc.NextPowerOfTwo(uint64(spr.NbPublicVariables + len(spr.Constraints)))
+	return &Solution{
+		L: make([]fr.Element, size),
+		R: make([]fr.Element, size),
+		O: make([]fr.Element, size),
+	}
+}
+
+// solve solves the SparseR1CS and extracts the l, r, o vectors in solution (see newSolution)
+func solve(spr *cs.SparseR1CS, fullWitness bls12_377witness.Witness, solution *Solution, opt backend.ProverConfig) error {
+	if err := opt.Checkpoint("solve", 0); err != nil {
+		return err
+	}
 
 	// compute the constraint system solution
-	var solution []fr.Element
+	var wires []fr.Element
 	var err error
-	if solution, err = spr.Solve(fullWitness, opt); err != nil {
+	if wires, err = spr.Solve(fullWitness, opt); err != nil {
 		if !opt.Force {
-			return nil, err
+			return err
 		} else {
 			// we need to fill solution with random values
 			var r fr.Element
-			_, _ = r.SetRandom()
-			for i := spr.NbPublicVariables + spr.NbSecretVariables; i < len(solution); i++ {
-				solution[i] = r
+			if err := setRandom(&r, opt.RandomSource); err != nil {
+				return err
+			}
+			for i := spr.NbPublicVariables + spr.NbSecretVariables; i < len(wires); i++ {
+				wires[i] = r
 				r.Double(&r)
 			}
 		}
 	}
 
 	// query l, r, o in Lagrange basis, not blinded
-	evaluationLDomainSmall, evaluationRDomainSmall, evaluationODomainSmall := evaluateLROSmallDomain(spr, pk, solution)
+	evaluateLROSmallDomain(spr, wires, solution.L, solution.R, solution.O)
+
+	return nil
+}
+
+// keyEvaluations are the evaluations of polynomials derived from the proving key only. They are
+// computed by each proof, or once by a Prover (see newKeyEvaluations).
+type keyEvaluations struct {
+	// ql, qr, qm, qo evaluated on the coset of the big domain, in bit reversed order
+	qlBigBitReversed, qrBigBitReversed, qmBigBitReversed, qoBigBitReversed []fr.Element
+
+	// L₁ evaluated on the coset of the big domain, in bit reversed order
+	startsAtOneBigBitReversed []fr.Element
+
+	// (Xᵐ-1)⁻¹ evaluated on the coset of the big domain, whose values repeat every ratio = m'/m
+	xnMinusOneInverseBigCoset []fr.Element
+
+	// the identity permutation evaluated on the small domain
+	idSmallDomain []fr.Element
+}
+
+// newKeyEvaluations computes the evaluations derived from pk
+func newKeyEvaluations(pk *ProvingKey, nbTasks int) *keyEvaluations {
+	evals := &keyEvaluations{
+		startsAtOneBigBitReversed: evaluateStartsAtOneDomainBigBitReversed(pk, nbTasks),
+		xnMinusOneInverseBigCoset: fr.BatchInvert(evaluateXnMinusOneDomainBigCoset(&pk.Domain[1], &pk.Domain[0])),
+		idSmallDomain:             getIDSmallDomain(&pk.Domain[0]),
+	}
+	evals.qlBigBitReversed, evals.qrBigBitReversed, evals.qmBigBitReversed, evals.qoBigBitReversed = evaluateSelectorsDomainBigBitReversed(pk, nbTasks)
+	return evals
+}
+
+// proveFromSolution generates the proof of the solution. evals may be nil, in which case the
+// evaluations derived from pk are computed along the proof.
+func proveFromSolution(spr *cs.SparseR1CS, pk *ProvingKey, solution *Solution, evals *keyEvaluations, opt backend.ProverConfig) (*Proof, error) {
+	if pk.KZGSRS == nil {
+		return nil, errors.New("the KZG SRS of the proving key is not set")
+	}
+	if err := opt.Checkpoint("fft", 10); err != nil {
+		return nil, err
+	}
+
+	log := logger.Logger().With().Str("curve", spr.CurveID().String()).Int("nbConstraints", len(spr.Constraints)).Str("backend", "plonk").Logger()
+	start := time.Now()
+	nbTasks := opt.NbTasks
+	// pick a hash function that will be used to derive the challenges
+	hFunc := sha256.New()
+
+	// create a transcript manager to apply Fiat Shamir
+	fs := fiatshamir.NewTranscript(hFunc, "gamma", "beta", "alpha", "zeta")
+
+	// result
+	proof := &Proof{}
+
+	evaluationLDomainSmall, evaluationRDomainSmall, evaluationODomainSmall := solution.L, solution.R, solution.O
+
+	// the public inputs are the first entries of l
+	publicWitness := evaluationLDomainSmall[:spr.NbPublicVariables]
 
 	// save ll, lr, lo, and make a copy of them in canonical basis.
 	// note that we allocate more capacity to reuse for blinded polynomials
@@ -100,20 +247,25 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 		evaluationLDomainSmall,
 		evaluationRDomainSmall,
 		evaluationODomainSmall,
-		&pk.Domain[0])
+		&pk.Domain[0],
+		opt.RandomSource,
+		nbTasks)
 	if err != nil {
 		return nil, err
 	}
 
 	// compute kzg commitments of bcl, bcr and bco
-	if err := commitToLRO(blindedLCanonical, blindedRCanonical, blindedOCanonical, proof, pk.Vk.KZGSRS); err != nil {
+	if err := opt.Checkpoint("msm", 20); err != nil {
+		return nil, err
+	}
+	if err := commitToLRO(blindedLCanonical, blindedRCanonical, blindedOCanonical, proof, pk.KZGSRS, nbTasks); err != nil {
 		return nil, err
 	}
 
 	// The first challenge is derived using the public data: the commitments to the permutation,
 	// the coefficients of the circuit, and the public inputs.
 	// derive gamma from the Comm(blinded cl), Comm(blinded cr), Comm(blinded co)
-	if err := bindPublicData(&fs, "gamma", *pk.Vk, fullWitness[:spr.NbPublicVariables]); err != nil {
+	if err := bindPublicData(&fs, "gamma", *pk.Vk, publicWitness); err != nil {
 		return nil, err
 	}
 	bgamma, err := fs.ComputeChallenge("gamma")
@@ -129,18 +281,22 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 		return nil, err
 	}
 
+	if err := opt.Checkpoint("quotient", 30); err != nil {
+		return nil, err
+	}
+
 	// compute Z, the permutation accumulator polynomial, in canonical basis
 	// ll, lr, lo are NOT blinded
 	var blindedZCanonical []fr.Element
 	chZ := make(chan error, 1)
 	var alpha fr.Element
-	go func() {
+	utils.Go(nbTasks, func() {
 		var err error
 		blindedZCanonical, err = computeBlindedZCanonical(
 			evaluationLDomainSmall,
 			evaluationRDomainSmall,
 			evaluationODomainSmall,
-			pk, beta, gamma)
+			pk, evals, beta, gamma, opt.RandomSource, nbTasks)
 		if err != nil {
 			chZ <- err
 			close(chZ)
@@ -152,7 +308,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 		// this may add additional arithmetic operations, but with smaller tasks
 		// we ensure that this commitment is well parallelized, without having a "unbalanced task" making
 		// the rest of the code wait too long.
-		if proof.Z, err = kzg.Commit(blindedZCanonical, pk.Vk.KZGSRS, runtime.NumCPU()*2); err != nil {
+		if proof.Z, err = boundedKZGCommit(blindedZCanonical, pk.KZGSRS, nbTasks, utils.NbTasks(nbTasks)*2); err != nil {
 			chZ <- err
 			close(chZ)
 			return
@@ -162,7 +318,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 		alpha, err = deriveRandomness(&fs, "alpha", &proof.Z)
 		chZ <- err
 		close(chZ)
-	}()
+	})
 
 	// evaluation of the blinded versions of l, r, o and bz
 	// on the coset of the big domain
@@ -175,27 +331,27 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 	chEvalBL := make(chan struct{}, 1)
 	chEvalBR := make(chan struct{}, 1)
 	chEvalBO := make(chan struct{}, 1)
-	go func() {
-		evaluationBlindedLDomainBigBitReversed = evaluateDomainBigBitReversed(blindedLCanonical, &pk.Domain[1])
+	utils.Go(nbTasks, func() {
+		evaluationBlindedLDomainBigBitReversed = evaluateDomainBigBitReversed(blindedLCanonical, &pk.Domain[1], nbTasks)
 		close(chEvalBL)
-	}()
-	go func() {
-		evaluationBlindedRDomainBigBitReversed = evaluateDomainBigBitReversed(blindedRCanonical, &pk.Domain[1])
+	})
+	utils.Go(nbTasks, func() {
+		evaluationBlindedRDomainBigBitReversed = evaluateDomainBigBitReversed(blindedRCanonical, &pk.Domain[1], nbTasks)
 		close(chEvalBR)
-	}()
-	go func() {
-		evaluationBlindedODomainBigBitReversed = evaluateDomainBigBitReversed(blindedOCanonical, &pk.Domain[1])
+	})
+	utils.Go(nbTasks, func() {
+		evaluationBlindedODomainBigBitReversed = evaluateDomainBigBitReversed(blindedOCanonical, &pk.Domain[1], nbTasks)
 		close(chEvalBO)
-	}()
+	})
 
 	var constraintsInd, constraintsOrdering []fr.Element
 	chConstraintInd := make(chan struct{}, 1)
-	go func() {
+	utils.Go(nbTasks, func() {
 		// compute qk in canonical basis, completed with the public inputs
 		qkCompletedCanonical := make([]fr.Element, pk.Domain[0].Cardinality)
-		copy(qkCompletedCanonical, fullWitness[:spr.NbPublicVariables])
+		copy(qkCompletedCanonical, publicWitness)
 		copy(qkCompletedCanonical[spr.NbPublicVariables:], pk.LQk[spr.NbPublicVariables:])
-		pk.Domain[0].FFTInverse(qkCompletedCanonical, fft.DIF)
+		boundedFFTInverse(&pk.Domain[0], qkCompletedCanonical, fft.DIF, false, nbTasks)
 		fft.BitReverse(qkCompletedCanonical)
 
 		// compute the evaluation of qlL+qrR+qmL.R+qoO+k on the coset of the big domain
@@ -205,21 +361,23 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 		<-chEvalBO
 		constraintsInd = evaluateConstraintsDomainBigBitReversed(
 			pk,
+			evals,
 			evaluationBlindedLDomainBigBitReversed,
 			evaluationBlindedRDomainBigBitReversed,
 			evaluationBlindedODomainBigBitReversed,
-			qkCompletedCanonical)
+			qkCompletedCanonical,
+			nbTasks)
 		close(chConstraintInd)
-	}()
+	})
 
 	chConstraintOrdering := make(chan error, 1)
-	go func() {
+	utils.Go(nbTasks, func() {
 		if err := <-chZ; err != nil {
 			chConstraintOrdering <- err
 			return
 		}
 
-		evaluationBlindedZDomainBigBitReversed = evaluateDomainBigBitReversed(blindedZCanonical, &pk.Domain[1])
+		evaluationBlindedZDomainBigBitReversed = evaluateDomainBigBitReversed(blindedZCanonical, &pk.Domain[1], nbTasks)
 		// compute zu*g1*g2*g3-z*f1*f2*f3 on the coset of the big domain
 		// evalL, evalO, evalR are the evaluations of the blinded versions of l, r, o.
 		<-chEvalBL
@@ -232,22 +390,27 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 			evaluationBlindedRDomainBigBitReversed,
 			evaluationBlindedODomainBigBitReversed,
 			beta,
-			gamma)
+			gamma,
+			nbTasks)
 		chConstraintOrdering <- nil
 		close(chConstraintOrdering)
-	}()
+	})
 
 	if err := <-chConstraintOrdering; err != nil {
+		<-chConstraintInd
 		return nil, err
 	}
 
 	<-chConstraintInd
 
 	// compute h in canonical form
-	h1, h2, h3 := computeQuotientCanonical(pk, constraintsInd, constraintsOrdering, evaluationBlindedZDomainBigBitReversed, alpha)
+	h1, h2, h3 := computeQuotientCanonical(pk, evals, constraintsInd, constraintsOrdering, evaluationBlindedZDomainBigBitReversed, alpha, nbTasks)
 
 	// compute kzg commitments of h1, h2 and h3
-	if err := commitToQuotient(h1, h2, h3, proof, pk.Vk.KZGSRS); err != nil {
+	if err := opt.Checkpoint("msm", 70); err != nil {
+		return nil, err
+	}
+	if err := commitToQuotient(h1, h2, h3, proof, pk.KZGSRS, nbTasks); err != nil {
 		return nil, err
 	}
 
@@ -257,32 +420,38 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 		return nil, err
 	}
 
+	if err := opt.Checkpoint("opening", 80); err != nil {
+		return nil, err
+	}
+
 	// compute evaluations of (blinded version of) l, r, o, z at zeta
 	var blzeta, brzeta, bozeta fr.Element
 	var wgZetaEvals sync.WaitGroup
 	wgZetaEvals.Add(3)
-	go func() {
+	utils.Go(nbTasks, func() {
 		blzeta = eval(blindedLCanonical, zeta)
 		wgZetaEvals.Done()
-	}()
-	go func() {
+	})
+	utils.Go(nbTasks, func() {
 		brzeta = eval(blindedRCanonical, zeta)
 		wgZetaEvals.Done()
-	}()
-	go func() {
+	})
+	utils.Go(nbTasks, func() {
 		bozeta = eval(blindedOCanonical, zeta)
 		wgZetaEvals.Done()
-	}()
+	})
 
 	// open blinded Z at zeta*z
+	// note: the KZG openings of gnark-crypto are not bounded by nbTasks (see backend.WithNbTasks)
 	var zetaShifted fr.Element
 	zetaShifted.Mul(&zeta, &pk.Vk.Generator)
 	proof.ZShiftedOpening, err = kzg.Open(
 		blindedZCanonical,
 		zetaShifted,
-		pk.Vk.KZGSRS,
+		pk.KZGSRS,
 	)
 	if err != nil {
+		wgZetaEvals.Wait()
 		return nil, err
 	}
 
@@ -296,7 +465,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 	)
 	chLpoly := make(chan struct{}, 1)
 
-	go func() {
+	utils.Go(nbTasks, func() {
 		// compute the linearization polynomial r at zeta (goal: save committing separately to z, ql, qr, qm, qo, k)
 		wgZetaEvals.Wait()
 		linearizedPolynomialCanonical = computeLinearizedPolynomial(
@@ -310,13 +479,14 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 			bzuzeta,
 			blindedZCanonical,
 			pk,
+			nbTasks,
 		)
 
 		// TODO this commitment is only necessary to derive the challenge, we should
 		// be able to avoid doing it and get the challenge in another way
-		linearizedPolynomialDigest, errLPoly = kzg.Commit(linearizedPolynomialCanonical, pk.Vk.KZGSRS)
+		linearizedPolynomialDigest, errLPoly = boundedKZGCommit(linearizedPolynomialCanonical, pk.KZGSRS, nbTasks)
 		close(chLpoly)
-	}()
+	})
 
 	// foldedHDigest = Comm(h1) + ζᵐ⁺²*Comm(h2) + ζ²⁽ᵐ⁺²⁾*Comm(h3)
 	var bZetaPowerm, bSize big.Int
@@ -339,7 +509,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 			foldedH[i].Mul(&foldedH[i], &zetaPowerm) // ζ²⁽ᵐ⁺²⁾*h3+h2*ζᵐ⁺²
 			foldedH[i].Add(&foldedH[i], &h1[i])      // ζ^{2(m+2)*h3+ζᵐ⁺²*h2 + h1
 		}
-	})
+	}, nbTasks)
 
 	<-chLpoly
 	if errLPoly != nil {
@@ -368,7 +538,7 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 		},
 		zeta,
 		hFunc,
-		pk.Vk.KZGSRS,
+		pk.KZGSRS,
 	)
 
 	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
@@ -377,6 +547,9 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness bls12_377witness.Witn
 		return nil, err
 	}
 
+	if opt.Progress != nil {
+		opt.Progress(backend.PhaseDone, 100)
+	}
 	return proof, nil
 
 }
@@ -391,20 +564,20 @@ func eval(c []fr.Element, p fr.Element) fr.Element {
 }
 
 // fills proof.LRO with kzg commits of bcl, bcr and bco
-func commitToLRO(bcl, bcr, bco []fr.Element, proof *Proof, srs *kzg.SRS) error {
-	n := runtime.NumCPU() / 2
+func commitToLRO(bcl, bcr, bco []fr.Element, proof *Proof, srs *kzg.SRS, nbTasks int) error {
+	n := utils.NbTasks(nbTasks) / 2
 	var err0, err1, err2 error
 	chCommit0 := make(chan struct{}, 1)
 	chCommit1 := make(chan struct{}, 1)
-	go func() {
-		proof.LRO[0], err0 = kzg.Commit(bcl, srs, n)
+	utils.Go(nbTasks, func() {
+		proof.LRO[0], err0 = boundedKZGCommit(bcl, srs, nbTasks, n)
 		close(chCommit0)
-	}()
-	go func() {
-		proof.LRO[1], err1 = kzg.Commit(bcr, srs, n)
+	})
+	utils.Go(nbTasks, func() {
+		proof.LRO[1], err1 = boundedKZGCommit(bcr, srs, nbTasks, n)
 		close(chCommit1)
-	}()
-	if proof.LRO[2], err2 = kzg.Commit(bco, srs, n); err2 != nil {
+	})
+	if proof.LRO[2], err2 = boundedKZGCommit(bco, srs, nbTasks, n); err2 != nil {
 		return err2
 	}
 	<-chCommit0
@@ -417,20 +590,20 @@ func commitToLRO(bcl, bcr, bco []fr.Element, proof *Proof, srs *kzg.SRS) error {
 	return err1
 }
 
-func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, srs *kzg.SRS) error {
-	n := runtime.NumCPU() / 2
+func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, srs *kzg.SRS, nbTasks int) error {
+	n := utils.NbTasks(nbTasks) / 2
 	var err0, err1, err2 error
 	chCommit0 := make(chan struct{}, 1)
 	chCommit1 := make(chan struct{}, 1)
-	go func() {
-		proof.H[0], err0 = kzg.Commit(h1, srs, n)
+	utils.Go(nbTasks, func() {
+		proof.H[0], err0 = boundedKZGCommit(h1, srs, nbTasks, n)
 		close(chCommit0)
-	}()
-	go func() {
-		proof.H[1], err1 = kzg.Commit(h2, srs, n)
+	})
+	utils.Go(nbTasks, func() {
+		proof.H[1], err1 = boundedKZGCommit(h2, srs, nbTasks, n)
 		close(chCommit1)
-	}()
-	if proof.H[2], err2 = kzg.Commit(h3, srs, n); err2 != nil {
+	})
+	if proof.H[2], err2 = boundedKZGCommit(h3, srs, nbTasks, n); err2 != nil {
 		return err2
 	}
 	<-chCommit0
@@ -444,91 +617,118 @@ func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, srs *kzg.SRS) error
 }
 
 // computeBlindedLROCanonical l, r, o in canonical basis with blinding
-func computeBlindedLROCanonical(ll, lr, lo []fr.Element, domain *fft.Domain) (bcl, bcr, bco []fr.Element, err error) {
+func computeBlindedLROCanonical(ll, lr, lo []fr.Element, domain *fft.Domain, rnd io.Reader, nbTasks int) (bcl, bcr, bco []fr.Element, err error) {
+
+	// the blinding polynomials are sampled before the FFTs run concurrently,
+	// so that they are drawn from rnd in a deterministic order
+	var blindings [3][]fr.Element
+	for i := range blindings {
+		if blindings[i], err = randomPoly(1, rnd); err != nil {
+			return
+		}
+	}
 
 	// note that bcl, bcr and bco reuses cl, cr and co memory
 	cl := make([]fr.Element, domain.Cardinality, domain.Cardinality+2)
 	cr := make([]fr.Element, domain.Cardinality, domain.Cardinality+2)
 	co := make([]fr.Element, domain.Cardinality, domain.Cardinality+2)
 
-	chDone := make(chan error, 2)
+	chDone := make(chan struct{}, 2)
 
-	go func() {
-		var err error
+	utils.Go(nbTasks, func() {
 		copy(cl, ll)
-		domain.FFTInverse(cl, fft.DIF)
+		boundedFFTInverse(domain, cl, fft.DIF, false, nbTasks)
 		fft.BitReverse(cl)
-		bcl, err = blindPoly(cl, domain.Cardinality, 1)
-		chDone <- err
-	}()
-	go func() {
-		var err error
+		bcl = blindPoly(cl, domain.Cardinality, blindings[0])
+		chDone <- struct{}{}
+	})
+	utils.Go(nbTasks, func() {
 		copy(cr, lr)
-		domain.FFTInverse(cr, fft.DIF)
+		boundedFFTInverse(domain, cr, fft.DIF, false, nbTasks)
 		fft.BitReverse(cr)
-		bcr, err = blindPoly(cr, domain.Cardinality, 1)
-		chDone <- err
-	}()
+		bcr = blindPoly(cr, domain.Cardinality, blindings[1])
+		chDone <- struct{}{}
+	})
 	copy(co, lo)
-	domain.FFTInverse(co, fft.DIF)
+	boundedFFTInverse(domain, co, fft.DIF, false, nbTasks)
 	fft.BitReverse(co)
-	if bco, err = blindPoly(co, domain.Cardinality, 1); err != nil {
-		return
-	}
-	err = <-chDone
-	if err != nil {
-		return
-	}
-	err = <-chDone
+	bco = blindPoly(co, domain.Cardinality, blindings[2])
+	<-chDone
+	<-chDone
 	return
 
 }
 
-// blindPoly blinds a polynomial by adding a Q(X)*(X**degree-1), where deg Q = order.
+// blindPoly blinds a polynomial by adding a Q(X)*(X**degree-1), where Q is the blinding polynomial.
 //
 // * cp polynomial in canonical form
 // * rou root of unity, meaning the blinding factor is multiple of X**rou-1
-// * bo blinding order,  it's the degree of Q, where the blinding is Q(X)*(X**degree-1)
+// * blindingPoly the coefficients of Q, see randomPoly
 //
 // WARNING:
-// pre condition degree(cp) ⩽ rou + bo
+// pre condition degree(cp) ⩽ rou + degree(Q)
 // pre condition cap(cp) ⩾ int(totalDegree + 1)
-func blindPoly(cp []fr.Element, rou, bo uint64) ([]fr.Element, error) {
+func blindPoly(cp []fr.Element, rou uint64, blindingPoly []fr.Element) []fr.Element {
 
 	// degree of the blinded polynomial is max(rou+order, cp.Degree)
+	bo := uint64(len(blindingPoly) - 1)
 	totalDegree := rou + bo
 
 	// re-use cp
 	res := cp[:totalDegree+1]
 
-	// random polynomial
-	blindingPoly := make([]fr.Element, bo+1)
-	for i := uint64(0); i < bo+1; i++ {
-		if _, err := blindingPoly[i].SetRandom(); err != nil {
-			return nil, err
-		}
-	}
-
 	// blinding
 	for i := uint64(0); i < bo+1; i++ {
 		res[i].Sub(&res[i], &blindingPoly[i])
 		res[rou+i].Add(&res[rou+i], &blindingPoly[i])
 	}
 
-	return res, nil
+	return res
 
 }
 
-// evaluateLROSmallDomain extracts the solution l, r, o, and returns it in lagrange form.
-// solution = [ public | secret | internal ]
-func evaluateLROSmallDomain(spr *cs.SparseR1CS, pk *ProvingKey, solution []fr.Element) ([]fr.Element, []fr.Element, []fr.Element) {
+// randomPoly returns a random polynomial of degree bo, with coefficients read from rnd
+// (crypto/rand if rnd is nil)
+func randomPoly(bo uint64, rnd io.Reader) ([]fr.Element, error) {
+	p := make([]fr.Element, bo+1)
+	for i := range p {
+		if err := setRandom(&p[i], rnd); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
 
-	s := int(pk.Domain[0].Cardinality)
+// setRandom sets e to a uniformly random element read from rnd, or from crypto/rand if rnd is nil
+func setRandom(e *fr.Element, rnd io.Reader) error {
+	if rnd == nil {
+		_, err := e.SetRandom()
+		return err
+	}
 
-	var l, r, o []fr.Element
-	l = make([]fr.Element, s)
-	r = make([]fr.Element, s)
-	o = make([]fr.Element, s)
+	// rejection sampling, so that the same source yields the same elements on all platforms
+	q := fr.Modulus()
+	excess := uint(fr.Bytes*8 - q.BitLen())
+	var buf [fr.Bytes]byte
+	var v big.Int
+	for {
+		if _, err := io.ReadFull(rnd, buf[:]); err != nil {
+			return err
+		}
+		buf[0] &= 0xff >> excess
+		v.SetBytes(buf[:])
+		if v.Cmp(q) < 0 {
+			e.SetBigInt(&v)
+			return nil
+		}
+	}
+}
+
+// evaluateLROSmallDomain extracts the solution l, r, o in lagrange form, of the size of the small domain.
+// solution = [ public | secret | internal ]
+func evaluateLROSmallDomain(spr *cs.SparseR1CS, solution, l, r, o []fr.Element) {
+
+	s := len(l)
 	s0 := solution[0]
 
 	for i := 0; i < spr.NbPublicVariables; i++ { // placeholders
@@ -550,20 +750,25 @@ func evaluateLROSmallDomain(spr *cs.SparseR1CS, pk *ProvingKey, solution []fr.El
 		o[offset+i] = s0
 	}
 
-	return l, r, o
-
 }
 
 // computeZ computes Z, in canonical basis, where:
 //
-// * Z of degree n (domainNum.Cardinality)
-// * Z(1)=1
-// 								   (l(g^k)+β*g^k+γ)*(r(g^k)+uβ*g^k+γ)*(o(g^k)+u²β*g^k+γ)
-// * for i>0: Z(gⁱ) = Π_{k<i} -------------------------------------------------------
-//								     (l(g^k)+β*s1(g^k)+γ)*(r(g^k)+β*s2(g^k)+γ)*(o(g^k)+β*s3(\g^k)+γ)
+//   - Z of degree n (domainNum.Cardinality)
+//
+//   - Z(1)=1
+//     (l(g^k)+β*g^k+γ)*(r(g^k)+uβ*g^k+γ)*(o(g^k)+u²β*g^k+γ)
 //
-//	* l, r, o are the solution in Lagrange basis, evaluated on the small domain
-func computeBlindedZCanonical(l, r, o []fr.Element, pk *ProvingKey, beta, gamma fr.Element) ([]fr.Element, error) {
+//   - for i>0: Z(gⁱ) = Π_{k<i} -------------------------------------------------------
+//     (l(g^k)+β*s1(g^k)+γ)*(r(g^k)+β*s2(g^k)+γ)*(o(g^k)+β*s3(\g^k)+γ)
+//
+//   - l, r, o are the solution in Lagrange basis, evaluated on the small domain
+func computeBlindedZCanonical(l, r, o []fr.Element, pk *ProvingKey, evals *keyEvaluations, beta, gamma fr.Element, rnd io.Reader, nbTasks int) ([]fr.Element, error) {
+
+	blinding, err := randomPoly(2, rnd)
+	if err != nil {
+		return nil, err
+	}
 
 	// note that z has more capacity has its memory is reused for blinded z later on
 	z := make([]fr.Element, pk.Domain[0].Cardinality, pk.Domain[0].Cardinality+3)
@@ -573,7 +778,12 @@ func computeBlindedZCanonical(l, r, o []fr.Element, pk *ProvingKey, beta, gamma
 	z[0].SetOne()
 	gInv[0].SetOne()
 
-	evaluationIDSmallDomain := getIDSmallDomain(&pk.Domain[0])
+	var evaluationIDSmallDomain []fr.Element
+	if evals != nil {
+		evaluationIDSmallDomain = evals.idSmallDomain
+	} else {
+		evaluationIDSmallDomain = getIDSmallDomain(&pk.Domain[0])
+	}
 
 	utils.Parallelize(nbElmts-1, func(start, end int) {
 
@@ -596,7 +806,7 @@ func computeBlindedZCanonical(l, r, o []fr.Element, pk *ProvingKey, beta, gamma
 			gInv[i+1] = g[0]
 			z[i+1] = f[0]
 		}
-	})
+	}, nbTasks)
 
 	gInv = fr.BatchInvert(gInv)
 	for i := 1; i < nbElmts; i++ {
@@ -604,10 +814,10 @@ func computeBlindedZCanonical(l, r, o []fr.Element, pk *ProvingKey, beta, gamma
 			Mul(&z[i], &gInv[i])
 	}
 
-	pk.Domain[0].FFTInverse(z, fft.DIF)
+	boundedFFTInverse(&pk.Domain[0], z, fft.DIF, false, nbTasks)
 	fft.BitReverse(z)
 
-	return blindPoly(z, pk.Domain[0].Cardinality, 2)
+	return blindPoly(z, pk.Domain[0].Cardinality, blinding), nil
 
 }
 
@@ -616,29 +826,20 @@ func computeBlindedZCanonical(l, r, o []fr.Element, pk *ProvingKey, beta, gamma
 //
 // * evalL, evalR, evalO are the evaluation of the blinded solution vectors on odd cosets
 // * qk is the completed version of qk, in canonical version
-func evaluateConstraintsDomainBigBitReversed(pk *ProvingKey, evalL, evalR, evalO, qk []fr.Element) []fr.Element {
+func evaluateConstraintsDomainBigBitReversed(pk *ProvingKey, evals *keyEvaluations, evalL, evalR, evalO, qk []fr.Element, nbTasks int) []fr.Element {
 	var evalQl, evalQr, evalQm, evalQo, evalQk []fr.Element
-	var wg sync.WaitGroup
-	wg.Add(4)
-
-	go func() {
-		evalQl = evaluateDomainBigBitReversed(pk.Ql, &pk.Domain[1])
-		wg.Done()
-	}()
-	go func() {
-		evalQr = evaluateDomainBigBitReversed(pk.Qr, &pk.Domain[1])
-		wg.Done()
-	}()
-	go func() {
-		evalQm = evaluateDomainBigBitReversed(pk.Qm, &pk.Domain[1])
-		wg.Done()
-	}()
-	go func() {
-		evalQo = evaluateDomainBigBitReversed(pk.Qo, &pk.Domain[1])
-		wg.Done()
-	}()
-	evalQk = evaluateDomainBigBitReversed(qk, &pk.Domain[1])
-	wg.Wait()
+	if evals != nil {
+		evalQl, evalQr, evalQm, evalQo = evals.qlBigBitReversed, evals.qrBigBitReversed, evals.qmBigBitReversed, evals.qoBigBitReversed
+		evalQk = evaluateDomainBigBitReversed(qk, &pk.Domain[1], nbTasks)
+	} else {
+		chQk := make(chan struct{}, 1)
+		utils.Go(nbTasks, func() {
+			evalQk = evaluateDomainBigBitReversed(qk, &pk.Domain[1], nbTasks)
+			close(chQk)
+		})
+		evalQl, evalQr, evalQm, evalQo = evaluateSelectorsDomainBigBitReversed(pk, nbTasks)
+		<-chQk
+	}
 
 	// computes the evaluation of qrR+qlL+qmL.R+qoO+k on the coset of the big domain
 	utils.Parallelize(len(evalQk), func(start, end int) {
@@ -655,18 +856,40 @@ func evaluateConstraintsDomainBigBitReversed(pk *ProvingKey, evalL, evalR, evalO
 			t0.Add(&t0, &t1)               // ql.l + qr.r + qm.l.r + qo.o
 			evalQk[i].Add(&t0, &evalQk[i]) // ql.l + qr.r + qm.l.r + qo.o + k
 		}
-	})
+	}, nbTasks)
 
 	return evalQk
 }
 
+// evaluateSelectorsDomainBigBitReversed evaluates ql, qr, qm, qo on the big domain (coset).
+func evaluateSelectorsDomainBigBitReversed(pk *ProvingKey, nbTasks int) (evalQl, evalQr, evalQm, evalQo []fr.Element) {
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	utils.Go(nbTasks, func() {
+		evalQl = evaluateDomainBigBitReversed(pk.Ql, &pk.Domain[1], nbTasks)
+		wg.Done()
+	})
+	utils.Go(nbTasks, func() {
+		evalQr = evaluateDomainBigBitReversed(pk.Qr, &pk.Domain[1], nbTasks)
+		wg.Done()
+	})
+	utils.Go(nbTasks, func() {
+		evalQo = evaluateDomainBigBitReversed(pk.Qo, &pk.Domain[1], nbTasks)
+		wg.Done()
+	})
+	evalQm = evaluateDomainBigBitReversed(pk.Qm, &pk.Domain[1], nbTasks)
+	wg.Wait()
+	return
+}
+
 // evaluateOrderingDomainBigBitReversed computes the evaluation of Z(uX)g1g2g3-Z(X)f1f2f3 on the odd
 // cosets of the big domain.
 //
 // * z evaluation of the blinded permutation accumulator polynomial on odd cosets
 // * l, r, o evaluation of the blinded solution vectors on odd cosets
 // * gamma randomization
-func evaluateOrderingDomainBigBitReversed(pk *ProvingKey, z, l, r, o []fr.Element, beta, gamma fr.Element) []fr.Element {
+func evaluateOrderingDomainBigBitReversed(pk *ProvingKey, z, l, r, o []fr.Element, beta, gamma fr.Element, nbTasks int) []fr.Element {
 
 	nbElmts := int(pk.Domain[1].Cardinality)
 
@@ -713,7 +936,7 @@ func evaluateOrderingDomainBigBitReversed(pk *ProvingKey, z, l, r, o []fr.Elemen
 
 			evaluationIDBigDomain.Mul(&evaluationIDBigDomain, &pk.Domain[1].Generator) // gⁱ*g
 		}
-	})
+	}, nbTasks)
 
 	return res
 }
@@ -723,10 +946,10 @@ func evaluateOrderingDomainBigBitReversed(pk *ProvingKey, z, l, r, o []fr.Elemen
 //
 // Puts the result in res of size n.
 // Warning: result is in bit reversed order, we do a bit reverse operation only once in computeQuotientCanonical
-func evaluateDomainBigBitReversed(poly []fr.Element, domainH *fft.Domain) []fr.Element {
+func evaluateDomainBigBitReversed(poly []fr.Element, domainH *fft.Domain, nbTasks int) []fr.Element {
 	res := make([]fr.Element, domainH.Cardinality)
 	copy(res, poly)
-	domainH.FFT(res, fft.DIF, true)
+	boundedFFT(domainH, res, fft.DIF, true, nbTasks)
 	return res
 }
 
@@ -756,25 +979,36 @@ func evaluateXnMinusOneDomainBigCoset(domainBig, domainSmall *fft.Domain) []fr.E
 	return res
 }
 
+// evaluateStartsAtOneDomainBigBitReversed evaluates L₁ on the big domain (coset).
+func evaluateStartsAtOneDomainBigBitReversed(pk *ProvingKey, nbTasks int) []fr.Element {
+	// computes L₁ (canonical form)
+	startsAtOne := make([]fr.Element, pk.Domain[1].Cardinality)
+	for i := 0; i < int(pk.Domain[0].Cardinality); i++ {
+		startsAtOne[i].Set(&pk.Domain[0].CardinalityInv)
+	}
+	boundedFFT(&pk.Domain[1], startsAtOne, fft.DIF, true, nbTasks)
+	return startsAtOne
+}
+
 // computeQuotientCanonical computes h in canonical form, split as h1+X^mh2+X²mh3 such that
 //
 // ql(X)L(X)+qr(X)R(X)+qm(X)L(X)R(X)+qo(X)O(X)+k(X) + α.(z(μX)*g₁(X)*g₂(X)*g₃(X)-z(X)*f₁(X)*f₂(X)*f₃(X)) + α²*L₁(X)*(Z(X)-1)= h(X)Z(X)
 //
 // constraintInd, constraintOrdering are evaluated on the big domain (coset).
-func computeQuotientCanonical(pk *ProvingKey, evaluationConstraintsIndBitReversed, evaluationConstraintOrderingBitReversed, evaluationBlindedZDomainBigBitReversed []fr.Element, alpha fr.Element) ([]fr.Element, []fr.Element, []fr.Element) {
+func computeQuotientCanonical(pk *ProvingKey, evals *keyEvaluations, evaluationConstraintsIndBitReversed, evaluationConstraintOrderingBitReversed, evaluationBlindedZDomainBigBitReversed []fr.Element, alpha fr.Element, nbTasks int) ([]fr.Element, []fr.Element, []fr.Element) {
 
 	h := make([]fr.Element, pk.Domain[1].Cardinality)
 
-	// evaluate Z = Xᵐ-1 on a coset of the big domain
-	evaluationXnMinusOneInverse := evaluateXnMinusOneDomainBigCoset(&pk.Domain[1], &pk.Domain[0])
-	evaluationXnMinusOneInverse = fr.BatchInvert(evaluationXnMinusOneInverse)
+	var evaluationXnMinusOneInverse, startsAtOne []fr.Element
+	if evals != nil {
+		evaluationXnMinusOneInverse, startsAtOne = evals.xnMinusOneInverseBigCoset, evals.startsAtOneBigBitReversed
+	} else {
+		// evaluate Z = Xᵐ-1 on a coset of the big domain
+		evaluationXnMinusOneInverse = evaluateXnMinusOneDomainBigCoset(&pk.Domain[1], &pk.Domain[0])
+		evaluationXnMinusOneInverse = fr.BatchInvert(evaluationXnMinusOneInverse)
 
-	// computes L₁ (canonical form)
-	startsAtOne := make([]fr.Element, pk.Domain[1].Cardinality)
-	for i := 0; i < int(pk.Domain[0].Cardinality); i++ {
-		startsAtOne[i].Set(&pk.Domain[0].CardinalityInv)
+		startsAtOne = evaluateStartsAtOneDomainBigBitReversed(pk, nbTasks)
 	}
-	pk.Domain[1].FFT(startsAtOne, fft.DIF, true)
 
 	// ql(X)L(X)+qr(X)R(X)+qm(X)L(X)R(X)+qo(X)O(X)+k(X) + α.(z(μX)*g₁(X)*g₂(X)*g₃(X)-z(X)*f₁(X)*f₂(X)*f₃(X)) + α**2*L₁(X)(Z(X)-1)
 	// on a coset of the big domain
@@ -798,11 +1032,11 @@ func computeQuotientCanonical(pk *ProvingKey, evaluationConstraintsIndBitReverse
 				Add(&h[_i], &evaluationConstraintsIndBitReversed[_i]).
 				Mul(&h[_i], &evaluationXnMinusOneInverse[i%ratio])
 		}
-	})
+	}, nbTasks)
 
 	// put h in canonical form. h is of degree 3*(n+1)+2.
 	// using fft.DIT put h revert bit reverse
-	pk.Domain[1].FFTInverse(h, fft.DIT, true)
+	boundedFFTInverse(&pk.Domain[1], h, fft.DIT, true, nbTasks)
 
 	// degree of hi is n+2 because of the blinding
 	h1 := h[:pk.Domain[0].Cardinality+2]
@@ -824,7 +1058,7 @@ func computeQuotientCanonical(pk *ProvingKey, evaluationConstraintsIndBitReverse
 // α²*L₁(ζ)*Z(X)
 // + α*( (l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*Z(μζ)*s3(X) - Z(X)*(l(ζ)+β*id1(ζ)+γ)*(r(ζ)+β*id2(ζ)+γ)*(o(ζ)+β*id3(ζ)+γ))
 // + l(ζ)*Ql(X) + l(ζ)r(ζ)*Qm(X) + r(ζ)*Qr(X) + o(ζ)*Qo(X) + Qk(X)
-func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta, zu fr.Element, blindedZCanonical []fr.Element, pk *ProvingKey) []fr.Element {
+func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta, zu fr.Element, blindedZCanonical []fr.Element, pk *ProvingKey, nbTasks int) []fr.Element {
 
 	// first part: individual constraints
 	var rl fr.Element
@@ -834,11 +1068,11 @@ func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta,
 	// Z(μζ)(l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*β*s3(X)-Z(X)(l(ζ)+β*id1(ζ)+γ)*(r(ζ)+β*id2(ζ)+γ)*(o(ζ)+β*id3(ζ)+γ)
 	var s1, s2 fr.Element
 	chS1 := make(chan struct{}, 1)
-	go func() {
+	utils.Go(nbTasks, func() {
 		s1 = eval(pk.S1Canonical, zeta)                      // s1(ζ)
 		s1.Mul(&s1, &beta).Add(&s1, &lZeta).Add(&s1, &gamma) // (l(ζ)+β*s1(ζ)+γ)
 		close(chS1)
-	}()
+	})
 	tmp := eval(pk.S2Canonical, zeta)                        // s2(ζ)
 	tmp.Mul(&tmp, &beta).Add(&tmp, &rZeta).Add(&tmp, &gamma) // (r(ζ)+β*s2(ζ)+γ)
 	<-chS1
@@ -907,7 +1141,7 @@ func computeLinearizedPolynomial(lZeta, rZeta, oZeta, alpha, beta, gamma, zeta,
 			t0.Mul(&blindedZCanonical[i], &lagrangeZeta)
 			linPol[i].Add(&linPol[i], &t0) // finish the computation
 		}
-	})
+	}, nbTasks)
 
 	return linPol
 }
@@ -18,16 +18,18 @@ package plonk
 
 import (
 	"errors"
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/kzg"
 	"github.com/consensys/gnark/internal/backend/bls12-377/cs"
 
 	kzgg "github.com/consensys/gnark-crypto/kzg"
+	"github.com/consensys/gnark/backend"
 )
 
 // ProvingKey stores the data needed to generate a proof:
-// * the commitment scheme
+// * the commitment scheme, whose SRS is referenced by the proving key only
 // * ql, prepended with as many ones as they are public inputs
 // * qr, qm, qo prepended with as many zeroes as there are public inputs.
 // * qk, prepended with as many zeroes as public inputs, to be completed by the prover
@@ -38,6 +40,9 @@ type ProvingKey struct {
 	// Verifying Key is embedded into the proving key (needed by Prove)
 	Vk *VerifyingKey
 
+	// KZGSRS is the SRS of the commitment scheme, with the powers of α in G1 used by the prover
+	KZGSRS *kzg.SRS
+
 	// qr,ql,qm,qo (in canonical basis).
 	Ql, Qr, Qm, Qo []fr.Element
 
@@ -60,7 +65,7 @@ type ProvingKey struct {
 }
 
 // VerifyingKey stores the data needed to verify a proof:
-// * The commitment scheme
+// * The commitment scheme, reduced to the points of the SRS used by the verifier
 // * Commitments of ql prepended with as many ones as there are public inputs
 // * Commitments of qr, qm, qo, qk prepended with as many zeroes as there are public inputs
 // * Commitments to S1, S2, S3
@@ -71,7 +76,7 @@ type VerifyingKey struct {
 	Generator         fr.Element
 	NbPublicVariables uint64
 
-	// Commitment scheme that is used for an instantiation of PLONK
+	// Commitment scheme that is used for an instantiation of PLONK, reduced to [1]1, [1]2 and [α]2
 	KZGSRS *kzg.SRS
 
 	// cosetShift generator of the coset on the small domain
@@ -83,12 +88,17 @@ type VerifyingKey struct {
 	// Commitments to ql, qr, qm, qo prepended with as many zeroes (ones for l) as there are public inputs.
 	// In particular Qk is not complete.
 	Ql, Qr, Qm, Qo, Qk kzg.Digest
+
+	// CircuitDigest is the digest of the SparseR1CS used at setup (see SparseR1CS.Digest)
+	// the proving key refers to it through its embedded verifying key
+	CircuitDigest [32]byte
 }
 
 // Setup sets proving and verifying keys
-func Setup(spr *cs.SparseR1CS, srs *kzg.SRS) (*ProvingKey, *VerifyingKey, error) {
+func Setup(spr *cs.SparseR1CS, srs *kzg.SRS, opt backend.SetupConfig) (*ProvingKey, *VerifyingKey, error) {
 	var pk ProvingKey
 	var vk VerifyingKey
+	nbTasks := opt.NbTasks
 
 	// The verifying key shares data with the proving key
 	pk.Vk = &vk
@@ -113,11 +123,16 @@ func Setup(spr *cs.SparseR1CS, srs *kzg.SRS) (*ProvingKey, *VerifyingKey, error)
 	vk.SizeInv.SetUint64(vk.Size).Inverse(&vk.SizeInv)
 	vk.Generator.Set(&pk.Domain[0].Generator)
 	vk.NbPublicVariables = uint64(spr.NbPublicVariables)
+	vk.CircuitDigest = spr.Digest()
 
 	if err := pk.InitKZG(srs); err != nil {
 		return nil, nil, err
 	}
 
+	if err := opt.Checkpoint("fft", 0); err != nil {
+		return nil, nil, err
+	}
+
 	// public polynomials corresponding to constraints: [ placholders | constraints | assertions ]
 	pk.Ql = make([]fr.Element, pk.Domain[0].Cardinality)
 	pk.Qr = make([]fr.Element, pk.Domain[0].Cardinality)
@@ -146,50 +161,61 @@ func Setup(spr *cs.SparseR1CS, srs *kzg.SRS) (*ProvingKey, *VerifyingKey, error)
 		pk.LQk[offset+i].Set(&spr.Coefficients[spr.Constraints[i].K])
 	}
 
-	pk.Domain[0].FFTInverse(pk.Ql, fft.DIF)
-	pk.Domain[0].FFTInverse(pk.Qr, fft.DIF)
-	pk.Domain[0].FFTInverse(pk.Qm, fft.DIF)
-	pk.Domain[0].FFTInverse(pk.Qo, fft.DIF)
-	pk.Domain[0].FFTInverse(pk.CQk, fft.DIF)
+	boundedFFTInverse(&pk.Domain[0], pk.Ql, fft.DIF, false, nbTasks)
+	boundedFFTInverse(&pk.Domain[0], pk.Qr, fft.DIF, false, nbTasks)
+	boundedFFTInverse(&pk.Domain[0], pk.Qm, fft.DIF, false, nbTasks)
+	boundedFFTInverse(&pk.Domain[0], pk.Qo, fft.DIF, false, nbTasks)
+	boundedFFTInverse(&pk.Domain[0], pk.CQk, fft.DIF, false, nbTasks)
 	fft.BitReverse(pk.Ql)
 	fft.BitReverse(pk.Qr)
 	fft.BitReverse(pk.Qm)
 	fft.BitReverse(pk.Qo)
 	fft.BitReverse(pk.CQk)
 
+	if err := opt.Checkpoint("permutation", 30); err != nil {
+		return nil, nil, err
+	}
+
 	// build permutation. Note: at this stage, the permutation takes in account the placeholders
 	buildPermutation(spr, &pk)
 
 	// set s1, s2, s3
-	ccomputePermutationPolynomials(&pk)
+	ccomputePermutationPolynomials(&pk, nbTasks)
+
+	if err := opt.Checkpoint("msm", 50); err != nil {
+		return nil, nil, err
+	}
 
 	// Commit to the polynomials to set up the verifying key
 	var err error
-	if vk.Ql, err = kzg.Commit(pk.Ql, vk.KZGSRS); err != nil {
+	if vk.Ql, err = boundedKZGCommit(pk.Ql, pk.KZGSRS, nbTasks); err != nil {
 		return nil, nil, err
 	}
-	if vk.Qr, err = kzg.Commit(pk.Qr, vk.KZGSRS); err != nil {
+	if vk.Qr, err = boundedKZGCommit(pk.Qr, pk.KZGSRS, nbTasks); err != nil {
 		return nil, nil, err
 	}
-	if vk.Qm, err = kzg.Commit(pk.Qm, vk.KZGSRS); err != nil {
+	if vk.Qm, err = boundedKZGCommit(pk.Qm, pk.KZGSRS, nbTasks); err != nil {
 		return nil, nil, err
 	}
-	if vk.Qo, err = kzg.Commit(pk.Qo, vk.KZGSRS); err != nil {
+	if vk.Qo, err = boundedKZGCommit(pk.Qo, pk.KZGSRS, nbTasks); err != nil {
 		return nil, nil, err
 	}
-	if vk.Qk, err = kzg.Commit(pk.CQk, vk.KZGSRS); err != nil {
+	if vk.Qk, err = boundedKZGCommit(pk.CQk, pk.KZGSRS, nbTasks); err != nil {
 		return nil, nil, err
 	}
-	if vk.S[0], err = kzg.Commit(pk.S1Canonical, vk.KZGSRS); err != nil {
+	if vk.S[0], err = boundedKZGCommit(pk.S1Canonical, pk.KZGSRS, nbTasks); err != nil {
 		return nil, nil, err
 	}
-	if vk.S[1], err = kzg.Commit(pk.S2Canonical, vk.KZGSRS); err != nil {
+	if vk.S[1], err = boundedKZGCommit(pk.S2Canonical, pk.KZGSRS, nbTasks); err != nil {
 		return nil, nil, err
 	}
-	if vk.S[2], err = kzg.Commit(pk.S3Canonical, vk.KZGSRS); err != nil {
+	if vk.S[2], err = boundedKZGCommit(pk.S3Canonical, pk.KZGSRS, nbTasks); err != nil {
 		return nil, nil, err
 	}
 
+	if opt.Progress != nil {
+		opt.Progress(backend.PhaseDone, 100)
+	}
 	return &pk, &vk, nil
 
 }
@@ -263,7 +289,7 @@ func buildPermutation(spr *cs.SparseR1CS, pk *ProvingKey) {
 // s11  s12 ..   s1n	   s21 s22 	 ..		s2n		     s31 	s32 	..		s3n		 v
 // \---------------/       \--------------------/        \------------------------/
 // 		s1 (LDE)                s2 (LDE)                          s3 (LDE)
-func ccomputePermutationPolynomials(pk *ProvingKey) {
+func ccomputePermutationPolynomials(pk *ProvingKey, nbTasks int) {
 
 	nbElmts := int(pk.Domain[0].Cardinality)
 
@@ -281,22 +307,26 @@ func ccomputePermutationPolynomials(pk *ProvingKey) {
 	}
 
 	// Canonical form of S1, S2, S3
-	pk.Domain[0].FFTInverse(pk.S1Canonical, fft.DIF)
-	pk.Domain[0].FFTInverse(pk.S2Canonical, fft.DIF)
-	pk.Domain[0].FFTInverse(pk.S3Canonical, fft.DIF)
+	boundedFFTInverse(&pk.Domain[0], pk.S1Canonical, fft.DIF, false, nbTasks)
+	boundedFFTInverse(&pk.Domain[0], pk.S2Canonical, fft.DIF, false, nbTasks)
+	boundedFFTInverse(&pk.Domain[0], pk.S3Canonical, fft.DIF, false, nbTasks)
 	fft.BitReverse(pk.S1Canonical)
 	fft.BitReverse(pk.S2Canonical)
 	fft.BitReverse(pk.S3Canonical)
 
-	// evaluation of permutation on the big domain
+	evaluatePermutationBigDomain(pk, nbTasks)
+}
+
+// evaluatePermutationBigDomain computes the evaluations of s1, s2, s3 on the big domain (coset),
+// in bit reversed order, from their canonical form.
+func evaluatePermutationBigDomain(pk *ProvingKey, nbTasks int) {
 	pk.EvaluationPermutationBigDomainBitReversed = make([]fr.Element, 3*pk.Domain[1].Cardinality)
 	copy(pk.EvaluationPermutationBigDomainBitReversed, pk.S1Canonical)
 	copy(pk.EvaluationPermutationBigDomainBitReversed[pk.Domain[1].Cardinality:], pk.S2Canonical)
 	copy(pk.EvaluationPermutationBigDomainBitReversed[2*pk.Domain[1].Cardinality:], pk.S3Canonical)
-	pk.Domain[1].FFT(pk.EvaluationPermutationBigDomainBitReversed[:pk.Domain[1].Cardinality], fft.DIF, true)
-	pk.Domain[1].FFT(pk.EvaluationPermutationBigDomainBitReversed[pk.Domain[1].Cardinality:2*pk.Domain[1].Cardinality], fft.DIF, true)
-	pk.Domain[1].FFT(pk.EvaluationPermutationBigDomainBitReversed[2*pk.Domain[1].Cardinality:], fft.DIF, true)
-
+	boundedFFT(&pk.Domain[1], pk.EvaluationPermutationBigDomainBitReversed[:pk.Domain[1].Cardinality], fft.DIF, true, nbTasks)
+	boundedFFT(&pk.Domain[1], pk.EvaluationPermutationBigDomainBitReversed[pk.Domain[1].Cardinality:2*pk.Domain[1].Cardinality], fft.DIF, true, nbTasks)
+	boundedFFT(&pk.Domain[1], pk.EvaluationPermutationBigDomainBitReversed[2*pk.Domain[1].Cardinality:], fft.DIF, true, nbTasks)
 }
 
 // getIDSmallDomain returns the Lagrange form of ID on the small domain
@@ -317,27 +347,32 @@ func getIDSmallDomain(domain *fft.Domain) []fr.Element {
 	return res
 }
 
-// InitKZG inits pk.Vk.KZG using pk.Domain[0] cardinality and provided SRS
+// InitKZG inits pk.KZGSRS and pk.Vk.KZGSRS using pk.Domain[0] cardinality and provided SRS
 //
-// This should be used after deserializing a ProvingKey
-// as pk.Vk.KZG is NOT serialized
+// The proving key references srs, and its verifying key keeps only the points used by the verifier.
+// Both are serialized with the keys, so this is only needed to replace the SRS of a key.
 func (pk *ProvingKey) InitKZG(srs kzgg.SRS) error {
+	_srs := srs.(*kzg.SRS)
+
+	if len(_srs.G1) < int(pk.Vk.Size) {
+		return errors.New("kzg srs is too small")
+	}
+	pk.KZGSRS = _srs
+
 	return pk.Vk.InitKZG(srs)
 }
 
-// InitKZG inits vk.KZG using provided SRS
-//
-// This should be used after deserializing a VerifyingKey
-// as vk.KZG is NOT serialized
+// InitKZG inits vk.KZGSRS with the points of the provided SRS used by the verifier: [1]1, [1]2 and [α]2
 //
-// Note that this instantiate a new FFT domain using vk.Size
+// They are serialized with the VerifyingKey, so this is only needed to replace the SRS of a key.
 func (vk *VerifyingKey) InitKZG(srs kzgg.SRS) error {
 	_srs := srs.(*kzg.SRS)
 
-	if len(_srs.G1) < int(vk.Size) {
+	if len(_srs.G1) == 0 {
 		return errors.New("kzg srs is too small")
 	}
-	vk.KZGSRS = _srs
+	// copy [1]1, so that vk doesn't retain the powers of α in G1
+	vk.KZGSRS = &kzg.SRS{G1: []curve.G1Affine{_srs.G1[0]}, G2: _srs.G2}
 
 	return nil
 }
@@ -25,7 +25,9 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend/compiled"
+	"io"
 	"math/big"
 	"math/bits"
 )
@@ -53,6 +55,9 @@ type ProvingKey struct {
 	// if InfinityA[i] == true, the point G1.A[i] == infinity
 	InfinityA, InfinityB     []bool
 	NbInfinityA, NbInfinityB uint64
+
+	// CircuitDigest is the digest of the R1CS used at setup (see R1CS.Digest)
+	CircuitDigest [32]byte
 }
 
 // VerifyingKey is used by a Groth16 verifier to verify the validity of a proof and a statement
@@ -74,10 +79,14 @@ type VerifyingKey struct {
 
 	// e(α, β)
 	e curve.GT // not serialized
+
+	// CircuitDigest is the digest of the R1CS used at setup (see R1CS.Digest)
+	// it is zero for keys imported from other implementations
+	CircuitDigest [32]byte
 }
 
 // Setup constructs the SRS
-func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
+func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey, opt backend.SetupConfig) error {
 	/*
 		Setup
 		-----
@@ -93,11 +102,15 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	nbPublicWires := int(r1cs.NbPublicVariables)
 	nbPrivateWires := r1cs.NbSecretVariables + r1cs.NbInternalVariables
 
+	if err := opt.Checkpoint("fft", 0); err != nil {
+		return err
+	}
+
 	// Setting group for fft
 	domain := fft.NewDomain(uint64(len(r1cs.Constraints)))
 
 	// samples toxic waste
-	toxicWaste, err := sampleToxicWaste()
+	toxicWaste, err := sampleToxicWaste(opt.RandomSource)
 	if err != nil {
 		return err
 	}
@@ -198,6 +211,10 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	B = B[:n]
 	pk.NbInfinityB = uint64(nbWires - n)
 
+	if err := opt.Checkpoint("msm", 20); err != nil {
+		return err
+	}
+
 	// compute our batch scalar multiplication with g1 elements
 	g1Scalars := make([]fr.Element, 0, (nbWires*3)+int(domain.Cardinality)+3)
 	g1Scalars = append(g1Scalars, toxicWaste.alphaReg, toxicWaste.betaReg, toxicWaste.deltaReg)
@@ -207,7 +224,7 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	g1Scalars = append(g1Scalars, Z...)
 	g1Scalars = append(g1Scalars, vkK...)
 
-	g1PointsAff := curve.BatchScalarMultiplicationG1(&g1, g1Scalars)
+	g1PointsAff := boundedBatchScalarMultiplicationG1(&g1, g1Scalars, opt.NbTasks)
 
 	// sets pk: [α]1, [β]1, [δ]1
 	pk.G1.Alpha = g1PointsAff[0]
@@ -239,10 +256,14 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	// [[B(i)], [β], [δ], [γ]]
 	// len(B) == nbWires
 
+	if err := opt.Checkpoint("msm", 60); err != nil {
+		return err
+	}
+
 	// compute our batch scalar multiplication with g2 elements
 	g2Scalars := append(B, toxicWaste.betaReg, toxicWaste.deltaReg, toxicWaste.gammaReg)
 
-	g2PointsAff := curve.BatchScalarMultiplicationG2(&g2, g2Scalars)
+	g2PointsAff := boundedBatchScalarMultiplicationG2(&g2, g2Scalars, opt.NbTasks)
 
 	pk.G2.B = g2PointsAff[:len(B)]
 
@@ -258,6 +279,9 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 
 	// ---------------------------------------------------------------------------------------------
 	// Pairing: vk.e
+	if err := opt.Checkpoint("pairing", 90); err != nil {
+		return err
+	}
 	vk.G1.Alpha = pk.G1.Alpha
 	vk.G2.Beta = pk.G2.Beta
 
@@ -272,6 +296,13 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	// set domain
 	pk.Domain = *domain
 
+	// bind the keys to the circuit
+	pk.CircuitDigest = r1cs.Digest()
+	vk.CircuitDigest = pk.CircuitDigest
+
+	if opt.Progress != nil {
+		opt.Progress(backend.PhaseDone, 100)
+	}
 	return nil
 }
 
@@ -365,32 +396,33 @@ type toxicWaste struct {
 	alphaReg, betaReg, gammaReg, deltaReg fr.Element
 }
 
-func sampleToxicWaste() (toxicWaste, error) {
+// sampleToxicWaste samples the secret values of the setup from rnd, or from crypto/rand if rnd is nil
+func sampleToxicWaste(rnd io.Reader) (toxicWaste, error) {
 
 	res := toxicWaste{}
 
 	for res.t.IsZero() {
-		if _, err := res.t.SetRandom(); err != nil {
+		if err := setRandom(&res.t, rnd); err != nil {
 			return res, err
 		}
 	}
 	for res.alpha.IsZero() {
-		if _, err := res.alpha.SetRandom(); err != nil {
+		if err := setRandom(&res.alpha, rnd); err != nil {
 			return res, err
 		}
 	}
 	for res.beta.IsZero() {
-		if _, err := res.beta.SetRandom(); err != nil {
+		if err := setRandom(&res.beta, rnd); err != nil {
 			return res, err
 		}
 	}
 	for res.gamma.IsZero() {
-		if _, err := res.gamma.SetRandom(); err != nil {
+		if err := setRandom(&res.gamma, rnd); err != nil {
 			return res, err
 		}
 	}
 	for res.delta.IsZero() {
-		if _, err := res.delta.SetRandom(); err != nil {
+		if err := setRandom(&res.delta, rnd); err != nil {
 			return res, err
 		}
 	}
@@ -406,6 +438,31 @@ func sampleToxicWaste() (toxicWaste, error) {
 	return res, nil
 }
 
+// setRandom sets e to a uniformly random element read from rnd, or from crypto/rand if rnd is nil
+func setRandom(e *fr.Element, rnd io.Reader) error {
+	if rnd == nil {
+		_, err := e.SetRandom()
+		return err
+	}
+
+	// rejection sampling, so that the same source yields the same elements on all platforms
+	q := fr.Modulus()
+	excess := uint(fr.Bytes*8 - q.BitLen())
+	var buf [fr.Bytes]byte
+	var v big.Int
+	for {
+		if _, err := io.ReadFull(rnd, buf[:]); err != nil {
+			return err
+		}
+		buf[0] &= 0xff >> excess
+		v.SetBytes(buf[:])
+		if v.Cmp(q) < 0 {
+			e.SetBigInt(&v)
+			return nil
+		}
+	}
+}
+
 // DummySetup fills a random ProvingKey
 // used for test or benchmarking purposes
 func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
@@ -432,6 +489,7 @@ func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
 	pk.InfinityB = make([]bool, nbWires)
 	pk.NbInfinityA = uint64(nbZeroesA)
 	pk.NbInfinityB = uint64(nbZeroesB)
+	pk.CircuitDigest = r1cs.Digest()
 	for i := 0; i < nbZeroesA; i++ {
 		pk.InfinityA[i] = true
 	}
@@ -440,7 +498,7 @@ func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
 	}
 
 	// samples toxic waste
-	toxicWaste, err := sampleToxicWaste()
+	toxicWaste, err := sampleToxicWaste(nil)
 	if err != nil {
 		return err
 	}
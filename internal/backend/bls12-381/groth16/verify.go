@@ -28,6 +28,8 @@ import (
 	"time"
 
 	"github.com/consensys/gnark/logger"
+
+	bls12_381pairing "github.com/consensys/gnark/internal/backend/bls12-381/pairing"
 )
 
 var (
@@ -37,6 +39,16 @@ var (
 
 // Verify verifies a proof with given VerifyingKey and publicWitness
 func Verify(proof *Proof, vk *VerifyingKey, publicWitness bls12_381witness.Witness) error {
+	return verify(proof, vk, nil, publicWitness)
+}
+
+// VerifyPrepared verifies a proof with given PreparedVerifyingKey and publicWitness
+func VerifyPrepared(proof *Proof, pvk *PreparedVerifyingKey, publicWitness bls12_381witness.Witness) error {
+	return verify(proof, &pvk.VerifyingKey, pvk, publicWitness)
+}
+
+// verify verifies the proof, with the precomputed lines of pvk if it is not nil
+func verify(proof *Proof, vk *VerifyingKey, pvk *PreparedVerifyingKey, publicWitness bls12_381witness.Witness) error {
 
 	if len(publicWitness) != (len(vk.G1.K) - 1) {
 		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), len(vk.G1.K)-1)
@@ -52,10 +64,14 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bls12_381witness.Witne
 	var doubleML curve.GT
 	chDone := make(chan error, 1)
 
-	// compute (eKrsδ, eArBs)
+	// compute (eKrsδ, eArBs), or eArBs only if the lines of -[δ]2 are precomputed
 	go func() {
 		var errML error
-		doubleML, errML = curve.MillerLoop([]curve.G1Affine{proof.Krs, proof.Ar}, []curve.G2Affine{vk.G2.deltaNeg, proof.Bs})
+		if pvk != nil {
+			doubleML, errML = curve.MillerLoop([]curve.G1Affine{proof.Ar}, []curve.G2Affine{proof.Bs})
+		} else {
+			doubleML, errML = curve.MillerLoop([]curve.G1Affine{proof.Krs, proof.Ar}, []curve.G2Affine{vk.G2.deltaNeg, proof.Bs})
+		}
 		chDone <- errML
 		close(chDone)
 	}()
@@ -69,7 +85,14 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bls12_381witness.Witne
 	var kSumAff curve.G1Affine
 	kSumAff.FromJacobian(&kSum)
 
-	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
+	var right curve.GT
+	var err error
+	if pvk != nil {
+		// and eKrsδ
+		right, err = bls12_381pairing.MillerLoop([]curve.G1Affine{kSumAff, proof.Krs}, []bls12_381pairing.Lines{pvk.gammaNegLines, pvk.deltaNegLines})
+	} else {
+		right, err = curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
+	}
 	if err != nil {
 		return err
 	}
@@ -88,6 +111,31 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bls12_381witness.Witne
 	return nil
 }
 
+// PreparedVerifyingKey is a VerifyingKey with the precomputations of the pairings of VerifyPrepared.
+//
+// Its serialization includes e(α, β) and the lines of the Miller loops of -[γ]2 and -[δ]2,
+// so that reading it doesn't compute a pairing.
+type PreparedVerifyingKey struct {
+	VerifyingKey
+
+	// gammaNegLines, deltaNegLines are the lines of the Miller loops of -[γ]2 and -[δ]2
+	gammaNegLines, deltaNegLines bls12_381pairing.Lines
+}
+
+// Prepare returns the PreparedVerifyingKey of vk
+func Prepare(vk *VerifyingKey) *PreparedVerifyingKey {
+	pvk := &PreparedVerifyingKey{VerifyingKey: *vk}
+	pvk.gammaNegLines = bls12_381pairing.PrecomputeLines(&vk.G2.gammaNeg)
+	pvk.deltaNegLines = bls12_381pairing.PrecomputeLines(&vk.G2.deltaNeg)
+	return pvk
+}
+
+// IsDifferent returns true if the keys differ (see VerifyingKey.IsDifferent)
+func (pvk *PreparedVerifyingKey) IsDifferent(_other interface{}) bool {
+	pvk2 := _other.(*PreparedVerifyingKey)
+	return pvk.VerifyingKey.IsDifferent(&pvk2.VerifyingKey)
+}
+
 // ExportSolidity not implemented for BLS12-381
 func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
 	return errors.New("not implemented")
@@ -17,6 +17,7 @@
 package cs
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"github.com/fxamacker/cbor/v2"
@@ -46,6 +47,9 @@ import (
 type R1CS struct {
 	compiled.R1CS
 	Coefficients []fr.Element // R1C coefficients indexes point here
+
+	digest     [32]byte  // computed once by Digest
+	digestOnce sync.Once // the constraint system must not be modified after Digest is called
 }
 
 // NewR1CS returns a new R1CS and sets cs.Coefficient (fr.Element) from provided big.Int values
@@ -104,7 +108,7 @@ func (cs *R1CS) Solve(witness, a, b, c []fr.Element, opt backend.ProverConfig) (
 	// (or sooner, if a constraint is not satisfied)
 	defer solution.printLogs(opt.CircuitLogger, cs.Logs)
 
-	if err := cs.parallelSolve(a, b, c, &solution); err != nil {
+	if err := cs.parallelSolve(a, b, c, &solution, opt.NbTasks); err != nil {
 		if unsatisfiedErr, ok := err.(*UnsatisfiedConstraintError); ok {
 			log.Err(errors.New("unsatisfied constraint")).Int("id", unsatisfiedErr.CID).Send()
 		} else {
@@ -124,7 +128,7 @@ func (cs *R1CS) Solve(witness, a, b, c []fr.Element, opt backend.ProverConfig) (
 	return solution.values, nil
 }
 
-func (cs *R1CS) parallelSolve(a, b, c []fr.Element, solution *solution) error {
+func (cs *R1CS) parallelSolve(a, b, c []fr.Element, solution *solution, nbWorkers int) error {
 	// minWorkPerCPU is the minimum target number of constraint a task should hold
 	// in other words, if a level has less than minWorkPerCPU, it will not be parallelized and executed
 	// sequentially without sync.
@@ -138,14 +142,20 @@ func (cs *R1CS) parallelSolve(a, b, c []fr.Element, solution *solution) error {
 	// then we check that the constraint is valid
 	// if a[i] * b[i] != c[i]; it means the constraint is not satisfied
 
+	if nbWorkers <= 0 {
+		nbWorkers = runtime.NumCPU()
+	}
+	// with a single worker, the levels are solved sequentially in the calling go routine
+	sequential := nbWorkers == 1
+
 	var wg sync.WaitGroup
-	chTasks := make(chan []int, runtime.NumCPU())
-	chError := make(chan *UnsatisfiedConstraintError, runtime.NumCPU())
+	chTasks := make(chan []int, nbWorkers)
+	chError := make(chan *UnsatisfiedConstraintError, nbWorkers)
 
 	// start a worker pool
 	// each worker wait on chTasks
 	// a task is a slice of constraint indexes to be solved
-	for i := 0; i < runtime.NumCPU(); i++ {
+	for i := 0; i < nbWorkers && !sequential; i++ {
 		go func() {
 			for t := range chTasks {
 				for _, i := range t {
@@ -178,7 +188,7 @@ func (cs *R1CS) parallelSolve(a, b, c []fr.Element, solution *solution) error {
 		// max CPU to use
 		maxCPU := float64(len(level)) / minWorkPerCPU
 
-		if maxCPU <= 1.0 {
+		if maxCPU <= 1.0 || sequential {
 			// we do it sequentially
 			for _, i := range level {
 				if err := cs.solveConstraint(cs.Constraints[i], solution, &a[i], &b[i], &c[i]); err != nil {
@@ -195,7 +205,7 @@ func (cs *R1CS) parallelSolve(a, b, c []fr.Element, solution *solution) error {
 
 		// number of tasks for this level is set to num cpus
 		// but if we don't have enough work for all our CPUS, it can be lower.
-		nbTasks := runtime.NumCPU()
+		nbTasks := nbWorkers
 		maxTasks := int(math.Ceil(maxCPU))
 		if nbTasks > maxTasks {
 			nbTasks = maxTasks
@@ -253,6 +263,30 @@ func (cs *R1CS) IsSolved(witness *witness.Witness, opts ...backend.ProverOption)
 	return err
 }
 
+// SolveWires solves the R1CS with the given witness and returns all its wires (including the ONE_WIRE),
+// labelled with the input names and the hints that computed them
+func (cs *R1CS) SolveWires(witness *witness.Witness, opts ...backend.ProverOption) (*compiled.Solution, error) {
+	opt, err := backend.NewProverConfig(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	a := make([]fr.Element, len(cs.Constraints))
+	b := make([]fr.Element, len(cs.Constraints))
+	c := make([]fr.Element, len(cs.Constraints))
+	v := witness.Vector.(*bls24_315witness.Witness)
+	wires, err := cs.Solve(*v, a, b, c, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]big.Int, len(wires))
+	for i := 0; i < len(wires); i++ {
+		wires[i].ToBigIntRegular(&values[i])
+	}
+	return cs.NewSolution(values), nil
+}
+
 // divByCoeff sets res = res / t.Coeff
 func (cs *R1CS) divByCoeff(res *fr.Element, t compiled.Term) {
 	cID := t.CoeffID()
@@ -452,6 +486,32 @@ func (cs *R1CS) GetNbCoefficients() int {
 	return len(cs.Coefficients)
 }
 
+// Digest returns a deterministic sha256 digest of the constraint system (curve, schema, constraints,
+// coefficients and hints). It is recorded in the proving and verifying keys at setup, and checked by Prove.
+//
+// The digest is computed on the first call, and cached for the following ones.
+func (cs *R1CS) Digest() [32]byte {
+	cs.digestOnce.Do(func() {
+		cs.digest = cs.computeDigest()
+	})
+	return cs.digest
+}
+
+func (cs *R1CS) computeDigest() [32]byte {
+	h := sha256.New()
+
+	// writing to a hash.Hash never returns an error
+	_ = cs.R1CS.WriteDigest(h)
+	for i := 0; i < len(cs.Coefficients); i++ {
+		b := cs.Coefficients[i].Bytes()
+		h.Write(b[:])
+	}
+
+	var res [32]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
 // CurveID returns curve ID as defined in gnark-crypto
 func (cs *R1CS) CurveID() ecc.ID {
 	return ecc.BLS24_315
@@ -486,6 +546,8 @@ func (cs *R1CS) ReadFrom(r io.Reader) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	// the digest of the decoded constraint system is computed anew
+	cs.digestOnce = sync.Once{}
 	decoder := dm.NewDecoder(r)
 	if err := decoder.Decode(&cs); err != nil {
 		return int64(decoder.NumBytesRead()), err
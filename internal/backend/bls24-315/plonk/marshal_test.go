@@ -21,8 +21,12 @@ import (
 
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 
-	"bytes"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
+
+	"bytes"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/kzg"
+	"io"
+	"math/big"
 	"reflect"
 	"testing"
 )
@@ -48,6 +52,13 @@ func TestProvingKeySerialization(t *testing.T) {
 	var pk ProvingKey
 	pk.Vk = &vk
 	pk.Domain[0] = *fft.NewDomain(42)
+	srs, err := kzg.NewSRS(pk.Domain[0].Cardinality+3, new(big.Int).SetUint64(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pk.InitKZG(srs); err != nil {
+		t.Fatal(err)
+	}
 	pk.Domain[1] = *fft.NewDomain(4 * 42)
 	pk.Ql = make([]fr.Element, pk.Domain[0].Cardinality)
 	pk.Qr = make([]fr.Element, pk.Domain[0].Cardinality)
@@ -66,25 +77,42 @@ func TestProvingKeySerialization(t *testing.T) {
 	pk.Permutation[0] = -12
 	pk.Permutation[len(pk.Permutation)-1] = 8888
 
-	var buf bytes.Buffer
-	written, err := pk.WriteTo(&buf)
-	if err != nil {
-		t.Fatal("coudln't serialize", err)
-	}
+	pk.S1Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S2Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S3Canonical = make([]fr.Element, pk.Domain[0].Cardinality)
+	pk.S1Canonical[0].SetUint64(1)
+	pk.S2Canonical[1].SetUint64(2)
+	pk.S3Canonical[2].SetUint64(3)
 
-	var reconstructed ProvingKey
+	// derived data, recomputed by ReadFrom
+	vk.CosetShift.Set(&pk.Domain[0].FrMultiplicativeGen)
+	evaluatePermutationBigDomain(&pk, 1)
 
-	read, err := reconstructed.ReadFrom(&buf)
-	if err != nil {
-		t.Fatal("coudln't deserialize", err)
-	}
+	roundTripCheck(t, &pk, func() roundTripper { return new(ProvingKey) })
 
-	if !reflect.DeepEqual(&pk, &reconstructed) {
-		t.Fatal("reconstructed object don't match original")
+	// the verifying key doesn't retain the powers of α in G1
+	if len(pk.Vk.KZGSRS.G1) != 1 || pk.Vk.KZGSRS.G2 != srs.G2 {
+		t.Fatal("verifying key should only keep [1]1, [1]2 and [α]2")
 	}
 
-	if written != read {
-		t.Fatal("bytes written / read don't match")
+	// only the part of a larger SRS used by the prover is serialized
+	larger, err := kzg.NewSRS(2*pk.Domain[0].Cardinality, new(big.Int).SetUint64(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pk.InitKZG(larger); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := pk.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var reconstructed ProvingKey
+	if _, err := reconstructed.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reconstructed.KZGSRS, srs) {
+		t.Fatal("reconstructed SRS doesn't match the one used by the prover")
 	}
 }
 
@@ -103,24 +131,65 @@ func TestVerifyingKeySerialization(t *testing.T) {
 	vk.Qm = g1gen
 	vk.Qo = g1gen
 	vk.Qk = g1gen
+	vk.CosetShift.Set(&fft.NewDomain(1).FrMultiplicativeGen)
 
-	var buf bytes.Buffer
-	written, err := vk.WriteTo(&buf)
+	// without SRS
+	roundTripCheck(t, &vk, func() roundTripper { return new(VerifyingKey) })
+
+	srs, err := kzg.NewSRS(64, new(big.Int).SetUint64(42))
 	if err != nil {
-		t.Fatal("coudln't serialize", err)
+		t.Fatal(err)
 	}
+	if err := vk.InitKZG(srs); err != nil {
+		t.Fatal(err)
+	}
+	roundTripCheck(t, &vk, func() roundTripper { return new(VerifyingKey) })
+}
 
-	var reconstructed VerifyingKey
+type roundTripper interface {
+	io.WriterTo
+	io.ReaderFrom
+	WriteRawTo(w io.Writer) (int64, error)
+	UnsafeReadFrom(r io.Reader) (int64, error)
+}
 
-	read, err := reconstructed.ReadFrom(&buf)
+// roundTripCheck checks that from is reconstructed by ReadFrom and UnsafeReadFrom, after
+// WriteTo and WriteRawTo
+func roundTripCheck(t *testing.T, from roundTripper, reconstruct func() roundTripper) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	written, err := from.WriteTo(&buf)
 	if err != nil {
-		t.Fatal("coudln't deserialize", err)
+		t.Fatal("couldn't serialize", err)
 	}
 
-	if !reflect.DeepEqual(&vk, &reconstructed) {
+	reconstructed := reconstruct()
+	read, err := reconstructed.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal("couldn't deserialize", err)
+	}
+	if !reflect.DeepEqual(from, reconstructed) {
 		t.Fatal("reconstructed object don't match original")
 	}
+	if written != read {
+		t.Fatal("bytes written / read don't match")
+	}
 
+	buf.Reset()
+	written, err = from.WriteRawTo(&buf)
+	if err != nil {
+		t.Fatal("couldn't serialize", err)
+	}
+
+	reconstructed = reconstruct()
+	read, err = reconstructed.UnsafeReadFrom(&buf)
+	if err != nil {
+		t.Fatal("couldn't deserialize", err)
+	}
+	if !reflect.DeepEqual(from, reconstructed) {
+		t.Fatal("reconstructed object don't match original")
+	}
 	if written != read {
 		t.Fatal("bytes written / read don't match")
 	}
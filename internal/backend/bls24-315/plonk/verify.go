@@ -33,13 +33,26 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark/logger"
+
+	bls24_315pairing "github.com/consensys/gnark/internal/backend/bls24-315/pairing"
 )
 
 var (
 	errWrongClaimedQuotient = errors.New("claimed quotient is not as expected")
 )
 
+// Verify verifies a proof with given VerifyingKey and publicWitness
 func Verify(proof *Proof, vk *VerifyingKey, publicWitness bls24_315witness.Witness) error {
+	return verify(proof, vk, nil, publicWitness)
+}
+
+// VerifyPrepared verifies a proof with given PreparedVerifyingKey and publicWitness
+func VerifyPrepared(proof *Proof, pvk *PreparedVerifyingKey, publicWitness bls24_315witness.Witness) error {
+	return verify(proof, &pvk.VerifyingKey, pvk, publicWitness)
+}
+
+// verify verifies the proof, with the precomputed lines of pvk if it is not nil
+func verify(proof *Proof, vk *VerifyingKey, pvk *PreparedVerifyingKey, publicWitness bls24_315witness.Witness) error {
 	log := logger.Logger().With().Str("curve", "bls24_315").Str("backend", "plonk").Logger()
 	start := time.Now()
 
@@ -226,26 +239,118 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bls24_315witness.Witne
 	// Batch verify
 	var shiftedZeta fr.Element
 	shiftedZeta.Mul(&zeta, &vk.Generator)
-	err = kzg.BatchVerifyMultiPoints([]kzg.Digest{
+	digests := []kzg.Digest{
 		foldedDigest,
 		proof.Z,
-	},
-		[]kzg.OpeningProof{
-			foldedProof,
-			proof.ZShiftedOpening,
-		},
-		[]fr.Element{
-			zeta,
-			shiftedZeta,
-		},
-		vk.KZGSRS,
-	)
+	}
+	openingProofs := []kzg.OpeningProof{
+		foldedProof,
+		proof.ZShiftedOpening,
+	}
+	zetas := []fr.Element{
+		zeta,
+		shiftedZeta,
+	}
+	if pvk != nil {
+		err = pvk.batchVerifyMultiPoints(digests, openingProofs, zetas)
+	} else {
+		err = kzg.BatchVerifyMultiPoints(digests, openingProofs, zetas, vk.KZGSRS)
+	}
 
 	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
 
 	return err
 }
 
+// PreparedVerifyingKey is a VerifyingKey with the precomputations of the pairings of VerifyPrepared.
+//
+// Its serialization includes the lines of the Miller loops of [1]2 and [α]2.
+type PreparedVerifyingKey struct {
+	VerifyingKey
+
+	// g2Lines are the lines of the Miller loops of [1]2 and [α]2
+	g2Lines [2]bls24_315pairing.Lines
+}
+
+// Prepare returns the PreparedVerifyingKey of vk, whose KZG SRS must be set (see InitKZG)
+func Prepare(vk *VerifyingKey) (*PreparedVerifyingKey, error) {
+	if vk.KZGSRS == nil {
+		return nil, errors.New("the KZG SRS of the verifying key is not set")
+	}
+	pvk := &PreparedVerifyingKey{VerifyingKey: *vk}
+	for i := range pvk.g2Lines {
+		pvk.g2Lines[i] = bls24_315pairing.PrecomputeLines(&vk.KZGSRS.G2[i])
+	}
+
+	return pvk, nil
+}
+
+// batchVerifyMultiPoints is kzg.BatchVerifyMultiPoints with the lines of the Miller loops of the
+// SRS precomputed
+func (pvk *PreparedVerifyingKey) batchVerifyMultiPoints(digests []kzg.Digest, proofs []kzg.OpeningProof, points []fr.Element) error {
+
+	// sample random numbers λᵢ for sampling
+	randomNumbers := make([]fr.Element, len(digests))
+	randomNumbers[0].SetOne()
+	for i := 1; i < len(randomNumbers); i++ {
+		if _, err := randomNumbers[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	// fold the evals: ∑ᵢλᵢfᵢ(aᵢ)
+	var foldedEvals, tmp fr.Element
+	quotients := make([]curve.G1Affine, len(proofs))
+	for i := range proofs {
+		quotients[i].Set(&proofs[i].H)
+		tmp.Mul(&randomNumbers[i], &proofs[i].ClaimedValue)
+		foldedEvals.Add(&foldedEvals, &tmp)
+	}
+
+	// fold the committed quotients ∑ᵢλᵢ[Hᵢ(α)]G₁ and the digests ∑ᵢλᵢ[f_i(α)]G₁
+	config := ecc.MultiExpConfig{ScalarsMont: true}
+	var foldedQuotients, foldedDigests curve.G1Affine
+	if _, err := foldedQuotients.MultiExp(quotients, randomNumbers, config); err != nil {
+		return err
+	}
+	if _, err := foldedDigests.MultiExp(digests, randomNumbers, config); err != nil {
+		return err
+	}
+
+	// ∑ᵢλᵢ[f_i(α)]G₁ - [∑ᵢλᵢfᵢ(aᵢ)]G₁
+	var foldedEvalsCommit curve.G1Affine
+	var foldedEvalsBigInt big.Int
+	foldedEvals.ToBigIntRegular(&foldedEvalsBigInt)
+	foldedEvalsCommit.ScalarMultiplication(&pvk.KZGSRS.G1[0], &foldedEvalsBigInt)
+	foldedDigests.Sub(&foldedDigests, &foldedEvalsCommit)
+
+	// + ∑ᵢλᵢ[p_i]([Hᵢ(α)]G₁)
+	var foldedPointsQuotients curve.G1Affine
+	for i := range randomNumbers {
+		randomNumbers[i].Mul(&randomNumbers[i], &points[i])
+	}
+	if _, err := foldedPointsQuotients.MultiExp(quotients, randomNumbers, config); err != nil {
+		return err
+	}
+	foldedDigests.Add(&foldedDigests, &foldedPointsQuotients)
+
+	// -∑ᵢλᵢ[Qᵢ(α)]G₁
+	foldedQuotients.Neg(&foldedQuotients)
+
+	// pairing check
+	// e([∑ᵢλᵢ(fᵢ(α) - fᵢ(pᵢ) + pᵢHᵢ(α))]G₁, G₂).e([-∑ᵢλᵢ[Hᵢ(α)]G₁), [α]G₂)
+	ml, err := bls24_315pairing.MillerLoop([]curve.G1Affine{foldedDigests, foldedQuotients}, pvk.g2Lines[:])
+	if err != nil {
+		return err
+	}
+	var one curve.GT
+	one.SetOne()
+	if res := curve.FinalExponentiation(&ml); !res.Equal(&one) {
+		return kzg.ErrVerifyOpeningProof
+	}
+	return nil
+}
+
 func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk VerifyingKey, publicInputs []fr.Element) error {
 
 	// permutation
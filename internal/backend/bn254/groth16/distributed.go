@@ -0,0 +1,602 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package groth16
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/consensys/gnark/internal/backend/bn254/cs"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	bn254witness "github.com/consensys/gnark/internal/backend/bn254/witness"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gnark/logger"
+)
+
+// WorkerService is the name under which a Worker is registered in a net/rpc server
+const WorkerService = "Groth16Worker"
+
+// Basis identifies a multi-exponentiation basis of the ProvingKey
+type Basis uint8
+
+const (
+	BasisA   Basis = iota // G1.A
+	BasisB                // G1.B
+	BasisK                // G1.K
+	BasisZ                // G1.Z
+	BasisG2B              // G2.B
+)
+
+// KeyShard is the part of a ProvingKey held by a worker of a distributed prover: a contiguous
+// range of each of the multi-exponentiation bases.
+type KeyShard struct {
+	// CircuitDigest is the digest of the R1CS used at setup (see R1CS.Digest)
+	CircuitDigest [32]byte
+
+	G1 struct {
+		A, B, K, Z []curve.G1Affine
+	}
+	G2 struct {
+		B []curve.G2Affine
+	}
+}
+
+// CurveID returns the curveID
+func (shard *KeyShard) CurveID() ecc.ID {
+	return curve.ID
+}
+
+// Split returns a copy of pk without the multi-exponentiation bases, for the coordinator of
+// a distributed prover, and the nbShards KeyShard to be held by its workers (see ProveDistributed).
+//
+// The shards reference the bases of pk, and are meant to be serialized to the workers.
+func (pk *ProvingKey) Split(nbShards int) (*ProvingKey, []*KeyShard) {
+	coordinator := *pk
+	coordinator.G1.A, coordinator.G1.B, coordinator.G1.K, coordinator.G1.Z = nil, nil, nil, nil
+	coordinator.G2.B = nil
+
+	shards := make([]*KeyShard, nbShards)
+	for i := range shards {
+		shard := &KeyShard{CircuitDigest: pk.CircuitDigest}
+		shard.G1.A = pk.G1.A[shardStart(len(pk.G1.A), i, nbShards):shardStart(len(pk.G1.A), i+1, nbShards)]
+		shard.G1.B = pk.G1.B[shardStart(len(pk.G1.B), i, nbShards):shardStart(len(pk.G1.B), i+1, nbShards)]
+		shard.G1.K = pk.G1.K[shardStart(len(pk.G1.K), i, nbShards):shardStart(len(pk.G1.K), i+1, nbShards)]
+		shard.G1.Z = pk.G1.Z[shardStart(len(pk.G1.Z), i, nbShards):shardStart(len(pk.G1.Z), i+1, nbShards)]
+		shard.G2.B = pk.G2.B[shardStart(len(pk.G2.B), i, nbShards):shardStart(len(pk.G2.B), i+1, nbShards)]
+		shards[i] = shard
+	}
+	return &coordinator, shards
+}
+
+// shardStart returns the start of the i-th of nbShards contiguous ranges of [0, n)
+func shardStart(n, i, nbShards int) int {
+	return i * n / nbShards
+}
+
+// WriteTo writes binary encoding of the KeyShard to w
+func (shard *KeyShard) WriteTo(w io.Writer) (int64, error) {
+	enc := curve.NewEncoder(w)
+	toEncode := []interface{}{
+		&shard.CircuitDigest,
+		shard.G1.A,
+		shard.G1.B,
+		shard.G1.K,
+		shard.G1.Z,
+		shard.G2.B,
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads binary encoding of a KeyShard from r
+func (shard *KeyShard) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+	toDecode := []interface{}{
+		&shard.CircuitDigest,
+		&shard.G1.A,
+		&shard.G1.B,
+		&shard.G1.K,
+		&shard.G1.Z,
+		&shard.G2.B,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	return dec.BytesRead(), nil
+}
+
+// MultiExpRequest asks a worker for the multi-exponentiation of its shard of a basis
+type MultiExpRequest struct {
+	CircuitDigest [32]byte
+	Basis         Basis
+
+	// Scalars are in regular form, one per point of the shard
+	Scalars []fr.Element
+}
+
+// MultiExpResponse is the partial multi-exponentiation of a shard
+type MultiExpResponse struct {
+	G1 curve.G1Jac
+	G2 curve.G2Jac
+}
+
+// FFTRequest asks a worker for the FFT (or inverse FFT) of a batch of vectors, in natural order.
+//
+// If Twiddle is set, the k-th element of the i-th transformed vector is then multiplied by
+// Omega^(k*(First+i)).
+type FFTRequest struct {
+	Inverse bool
+	Vectors [][]fr.Element
+
+	Twiddle bool
+	Omega   fr.Element
+	First   int
+}
+
+// FFTResponse holds the transformed vectors of a FFTRequest
+type FFTResponse struct {
+	Vectors [][]fr.Element
+}
+
+// WorkerClient calls the methods of a Worker, typically through a *rpc.Client
+type WorkerClient interface {
+	Call(serviceMethod string, args interface{}, reply interface{}) error
+}
+
+// Worker computes the multi-exponentiations and the FFTs of a distributed proof.
+//
+// Its exported methods have the net/rpc signature, it is registered under WorkerService.
+type Worker struct {
+	shard   *KeyShard
+	nbTasks int
+
+	lock    sync.Mutex
+	domains map[int]*fft.Domain
+}
+
+// NewWorker returns a Worker holding shard; nbTasks bounds the number of tasks of each
+// request (see backend.WithNbTasks), 0 meaning unbounded.
+func NewWorker(shard *KeyShard, nbTasks int) *Worker {
+	return &Worker{
+		shard:   shard,
+		nbTasks: nbTasks,
+		domains: make(map[int]*fft.Domain),
+	}
+}
+
+// MultiExp computes the multi-exponentiation of the shard of req.Basis with req.Scalars
+func (w *Worker) MultiExp(req *MultiExpRequest, res *MultiExpResponse) error {
+	if req.CircuitDigest != w.shard.CircuitDigest {
+		return fmt.Errorf("%w: circuit digest is %x, key shard was generated for %x", backend.ErrCircuitMismatch, req.CircuitDigest, w.shard.CircuitDigest)
+	}
+	config := ecc.MultiExpConfig{NbTasks: runtime.NumCPU()}
+
+	var points []curve.G1Affine
+	switch req.Basis {
+	case BasisA:
+		points = w.shard.G1.A
+	case BasisB:
+		points = w.shard.G1.B
+	case BasisK:
+		points = w.shard.G1.K
+	case BasisZ:
+		points = w.shard.G1.Z
+	case BasisG2B:
+		if len(req.Scalars) != len(w.shard.G2.B) {
+			return fmt.Errorf("invalid number of scalars, got %d, expected %d", len(req.Scalars), len(w.shard.G2.B))
+		}
+		if len(req.Scalars) == 0 {
+			return nil
+		}
+		_, err := boundedMultiExpG2(&res.G2, w.shard.G2.B, req.Scalars, config, w.nbTasks)
+		return err
+	default:
+		return fmt.Errorf("unknown basis %d", req.Basis)
+	}
+
+	if len(req.Scalars) != len(points) {
+		return fmt.Errorf("invalid number of scalars, got %d, expected %d", len(req.Scalars), len(points))
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	_, err := boundedMultiExpG1(&res.G1, points, req.Scalars, config, w.nbTasks)
+	return err
+}
+
+// FFT computes the FFTs of req.Vectors, see FFTRequest
+func (w *Worker) FFT(req *FFTRequest, res *FFTResponse) error {
+	for i, v := range req.Vectors {
+		if len(v) == 0 || bits.OnesCount(uint(len(v))) != 1 {
+			return fmt.Errorf("invalid vector size %d, expected a power of 2", len(v))
+		}
+		if len(v) > 1 {
+			domain := w.domain(len(v))
+			if req.Inverse {
+				boundedFFTInverse(domain, v, fft.DIF, false, w.nbTasks)
+			} else {
+				boundedFFT(domain, v, fft.DIF, false, w.nbTasks)
+			}
+			fft.BitReverse(v)
+		}
+		if req.Twiddle {
+			var omega, t fr.Element
+			omega.Exp(req.Omega, big.NewInt(int64(req.First+i)))
+			t.SetOne()
+			for k := range v {
+				v[k].Mul(&v[k], &t)
+				t.Mul(&t, &omega)
+			}
+		}
+	}
+	res.Vectors = req.Vectors
+	return nil
+}
+
+// Call calls the method of the Worker in process, so that a Worker is its own WorkerClient
+func (w *Worker) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	switch serviceMethod {
+	case WorkerService + ".MultiExp":
+		return w.MultiExp(args.(*MultiExpRequest), reply.(*MultiExpResponse))
+	case WorkerService + ".FFT":
+		return w.FFT(args.(*FFTRequest), reply.(*FFTResponse))
+	default:
+		return fmt.Errorf("unknown method %s", serviceMethod)
+	}
+}
+
+// domain returns the cached fft domain of cardinality n
+func (w *Worker) domain(n int) *fft.Domain {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	d, ok := w.domains[n]
+	if !ok {
+		d = fft.NewDomain(uint64(n))
+		w.domains[n] = d
+	}
+	return d
+}
+
+// ProveDistributed generates the proof of knowledge of a r1cs with full witness (secret + public part),
+// with the multi-exponentiations and the FFTs computed by workers.
+//
+// workers[i] must hold the i-th KeyShard of pk.Split(len(workers)); pk may be the coordinator key
+// returned by Split. The proof is the one Prove returns with the same random source.
+func ProveDistributed(r1cs *cs.R1CS, pk *ProvingKey, witness bn254witness.Witness, workers []WorkerClient, opt backend.ProverConfig) (*Proof, error) {
+	if len(workers) == 0 {
+		return nil, errors.New("no workers")
+	}
+	if err := checkWitnessSize(r1cs, witness); err != nil {
+		return nil, err
+	}
+	// the digest is cached on r1cs (see R1CS.Digest); it is sent with each request, for the workers
+	// to check their key shards against it
+	digest := r1cs.Digest()
+	if digest != pk.CircuitDigest {
+		return nil, fmt.Errorf("%w: circuit digest is %x, proving key was generated for %x", backend.ErrCircuitMismatch, digest, pk.CircuitDigest)
+	}
+
+	solution := newSolution(r1cs)
+	if err := solve(r1cs, witness, solution, opt); err != nil {
+		return nil, err
+	}
+	if err := opt.Checkpoint("fft", 20); err != nil {
+		return nil, err
+	}
+
+	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Int("nbConstraints", len(r1cs.Constraints)).Str("backend", "groth16").Int("nbWorkers", len(workers)).Logger()
+	start := time.Now()
+
+	wireValues := solution.Wires
+	utils.Parallelize(len(wireValues), func(start, end int) {
+		for i := start; i < end; i++ {
+			wireValues[i].FromMont()
+		}
+	}, opt.NbTasks)
+
+	h, err := computeHDistributed(workers, solution.A, solution.B, solution.C, &pk.Domain, opt.NbTasks)
+	if err != nil {
+		return nil, err
+	}
+
+	// sample random r and s, as Prove does
+	var r, s big.Int
+	var _r, _s, _kr fr.Element
+	if err := setRandom(&_r, opt.RandomSource); err != nil {
+		return nil, err
+	}
+	if err := setRandom(&_s, opt.RandomSource); err != nil {
+		return nil, err
+	}
+	_kr.Mul(&_r, &_s).Neg(&_kr)
+
+	_r.FromMont()
+	_s.FromMont()
+	_kr.FromMont()
+	_r.ToBigInt(&r)
+	_s.ToBigInt(&s)
+
+	// computes r[δ], s[δ], kr[δ]
+	deltas := boundedBatchScalarMultiplicationG1(&pk.G1.Delta, []fr.Element{_r, _s, _kr}, opt.NbTasks)
+
+	if err := opt.Checkpoint("msm", 50); err != nil {
+		return nil, err
+	}
+
+	wireValuesA := filterInfinity(nil, wireValues, pk.InfinityA, pk.NbInfinityA)
+	wireValuesB := filterInfinity(nil, wireValues, pk.InfinityB, pk.NbInfinityB)
+	msm, err := multiExpDistributed(workers, digest, map[Basis][]fr.Element{
+		BasisA:   wireValuesA,
+		BasisB:   wireValuesB,
+		BasisK:   wireValues[r1cs.NbPublicVariables:],
+		BasisZ:   h,
+		BasisG2B: wireValuesB,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &Proof{}
+
+	var ar, bs1, krs, p1 curve.G1Jac
+	ar.Set(&msm[BasisA].G1)
+	ar.AddMixed(&pk.G1.Alpha)
+	ar.AddMixed(&deltas[0])
+	proof.Ar.FromJacobian(&ar)
+
+	bs1.Set(&msm[BasisB].G1)
+	bs1.AddMixed(&pk.G1.Beta)
+	bs1.AddMixed(&deltas[1])
+
+	krs.Set(&msm[BasisK].G1)
+	krs.AddMixed(&deltas[2])
+	krs.AddAssign(&msm[BasisZ].G1)
+	p1.ScalarMultiplication(&ar, &s)
+	krs.AddAssign(&p1)
+	p1.ScalarMultiplication(&bs1, &r)
+	krs.AddAssign(&p1)
+	proof.Krs.FromJacobian(&krs)
+
+	var Bs, deltaS curve.G2Jac
+	Bs.Set(&msm[BasisG2B].G2)
+	deltaS.FromAffine(&pk.G2.Delta)
+	deltaS.ScalarMultiplication(&deltaS, &s)
+	Bs.AddAssign(&deltaS)
+	Bs.AddMixed(&pk.G2.Beta)
+	proof.Bs.FromJacobian(&Bs)
+
+	log.Debug().Dur("took", time.Since(start)).Msg("distributed prover done")
+
+	if opt.Progress != nil {
+		opt.Progress(backend.PhaseDone, 100)
+	}
+	return proof, nil
+}
+
+// multiExpDistributed sends each worker its range of the scalars of each basis, and returns the
+// sums of the partial multi-exponentiations
+func multiExpDistributed(workers []WorkerClient, digest [32]byte, scalars map[Basis][]fr.Element) (map[Basis]*MultiExpResponse, error) {
+	type partial struct {
+		basis Basis
+		res   MultiExpResponse
+		err   error
+	}
+	chPartials := make(chan partial, len(workers)*len(scalars))
+	for i, worker := range workers {
+		for basis, s := range scalars {
+			req := &MultiExpRequest{
+				CircuitDigest: digest,
+				Basis:         basis,
+				Scalars:       s[shardStart(len(s), i, len(workers)):shardStart(len(s), i+1, len(workers))],
+			}
+			go func(worker WorkerClient, req *MultiExpRequest) {
+				p := partial{basis: req.Basis}
+				p.err = worker.Call(WorkerService+".MultiExp", req, &p.res)
+				chPartials <- p
+			}(worker, req)
+		}
+	}
+
+	res := make(map[Basis]*MultiExpResponse, len(scalars))
+	for basis := range scalars {
+		res[basis] = &MultiExpResponse{}
+	}
+	var err error
+	for n := 0; n < len(workers)*len(scalars); n++ {
+		p := <-chPartials
+		if p.err != nil {
+			if err == nil {
+				err = fmt.Errorf("multi-exponentiation %d: %w", p.basis, p.err)
+			}
+			continue
+		}
+		res[p.basis].G1.AddAssign(&p.res.G1)
+		res[p.basis].G2.AddAssign(&p.res.G2)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// computeHDistributed computes h like computeH, with the FFTs computed by the workers
+func computeHDistributed(workers []WorkerClient, a, b, c []fr.Element, domain *fft.Domain, nbTasks int) ([]fr.Element, error) {
+	// add padding to ensure input length is domain cardinality
+	padding := make([]fr.Element, int(domain.Cardinality)-len(a))
+	a = append(a, padding...)
+	b = append(b, padding...)
+	c = append(c, padding...)
+	n := len(a)
+
+	// evaluations of a, b, c on the coset: ifft, then fft of the coefficients scaled by the coset shift powers
+	for _, v := range [][]fr.Element{a, b, c} {
+		if err := fftDistributed(workers, domain, v, true); err != nil {
+			return nil, err
+		}
+		scaleByPowers(v, domain.FrMultiplicativeGen, nbTasks)
+		if err := fftDistributed(workers, domain, v, false); err != nil {
+			return nil, err
+		}
+	}
+
+	var den, one fr.Element
+	one.SetOne()
+	den.Exp(domain.FrMultiplicativeGen, big.NewInt(int64(domain.Cardinality)))
+	den.Sub(&den, &one).Inverse(&den)
+
+	// h = ifft_coset(ca o cb - cc)
+	utils.Parallelize(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			a[i].Mul(&a[i], &b[i]).
+				Sub(&a[i], &c[i]).
+				Mul(&a[i], &den)
+		}
+	}, nbTasks)
+
+	if err := fftDistributed(workers, domain, a, true); err != nil {
+		return nil, err
+	}
+	scaleByPowers(a, domain.FrMultiplicativeGenInv, nbTasks)
+
+	// computeH returns h in bit-reversed order, as expected by pk.G1.Z
+	fft.BitReverse(a)
+
+	utils.Parallelize(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			a[i].FromMont()
+		}
+	}, nbTasks)
+
+	return a, nil
+}
+
+// fftDistributed computes the FFT (or inverse FFT) of a in natural order, with the four-step algorithm:
+// a is seen as the n1 x n2 matrix a[n2*j1 + j2]. The workers compute the FFTs of size n1 of the n2
+// columns, twiddled by ω^(j2*k1), then the FFTs of size n2 of the n1 rows; the k2-th element of
+// the k1-th row is the (k1 + n1*k2)-th element of the result.
+func fftDistributed(workers []WorkerClient, domain *fft.Domain, a []fr.Element, inverse bool) error {
+	n := len(a)
+	n1 := 1 << (bits.TrailingZeros(uint(n)) / 2)
+	n2 := n / n1
+
+	omega := domain.Generator
+	if inverse {
+		omega = domain.GeneratorInv
+	}
+
+	// columns
+	columns := make([][]fr.Element, n2)
+	for j2 := range columns {
+		columns[j2] = make([]fr.Element, n1)
+		for j1 := 0; j1 < n1; j1++ {
+			columns[j2][j1] = a[n2*j1+j2]
+		}
+	}
+	if err := fftBatches(workers, columns, inverse, true, omega); err != nil {
+		return err
+	}
+
+	// rows
+	rows := make([][]fr.Element, n1)
+	for k1 := range rows {
+		rows[k1] = make([]fr.Element, n2)
+		for j2 := 0; j2 < n2; j2++ {
+			rows[k1][j2] = columns[j2][k1]
+		}
+	}
+	if err := fftBatches(workers, rows, inverse, false, omega); err != nil {
+		return err
+	}
+
+	for k1 := range rows {
+		for k2 := 0; k2 < n2; k2++ {
+			a[k1+n1*k2] = rows[k1][k2]
+		}
+	}
+	return nil
+}
+
+// fftBatches splits vectors in one contiguous batch per worker, and replaces them with their FFTs
+func fftBatches(workers []WorkerClient, vectors [][]fr.Element, inverse, twiddle bool, omega fr.Element) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(workers))
+	for i, worker := range workers {
+		start, end := shardStart(len(vectors), i, len(workers)), shardStart(len(vectors), i+1, len(workers))
+		if start == end {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, worker WorkerClient) {
+			defer wg.Done()
+			req := &FFTRequest{
+				Inverse: inverse,
+				Vectors: vectors[start:end],
+				Twiddle: twiddle,
+				Omega:   omega,
+				First:   start,
+			}
+			var res FFTResponse
+			if errs[i] = worker.Call(WorkerService+".FFT", req, &res); errs[i] != nil {
+				return
+			}
+			if len(res.Vectors) != end-start {
+				errs[i] = errors.New("invalid fft response size")
+				return
+			}
+			copy(vectors[start:end], res.Vectors)
+		}(i, worker)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("fft: %w", err)
+		}
+	}
+	return nil
+}
+
+// scaleByPowers sets a[i] = a[i] * g^i
+func scaleByPowers(a []fr.Element, g fr.Element, nbTasks int) {
+	utils.Parallelize(len(a), func(start, end int) {
+		var t fr.Element
+		t.Exp(g, big.NewInt(int64(start)))
+		for i := start; i < end; i++ {
+			a[i].Mul(&a[i], &t)
+			t.Mul(&t, &g)
+		}
+	}, nbTasks)
+}
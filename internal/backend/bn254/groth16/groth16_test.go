@@ -26,8 +26,13 @@ import (
 	bn254witness "github.com/consensys/gnark/internal/backend/bn254/witness"
 
 	"bytes"
+	"errors"
 	bn254groth16 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	"io"
+	"math/rand"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark/backend"
@@ -35,6 +40,274 @@ import (
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 )
 
+// testProblem is the fixture of the tests: refCircuit compiled with nbConstraints, the witnesses
+// of X = 2, and the keys of a setup with the default options
+type testProblem struct {
+	nbConstraints              int
+	ccs                        *cs.R1CS
+	fullWitness, publicWitness bn254witness.Witness
+	pk                         *bn254groth16.ProvingKey
+	vk                         *bn254groth16.VerifyingKey
+}
+
+func newTestProblem(t *testing.T, nbConstraints int) *testProblem {
+	t.Helper()
+	ccs, err := frontend.Compile(curve.ID, r1cs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProblem{nbConstraints: nbConstraints, ccs: ccs.(*cs.R1CS)}
+	p.fullWitness, p.publicWitness = p.witnesses(t, 2)
+	p.pk, p.vk = p.setup(t, backend.SetupConfig{})
+	return p
+}
+
+// witnesses returns the full and public witnesses of X = x, Y = X**(2**nbConstraints)
+func (p *testProblem) witnesses(t *testing.T, x uint64) (full, public bn254witness.Witness) {
+	t.Helper()
+	var y fr.Element
+	y.SetUint64(x)
+	for i := 0; i < p.nbConstraints; i++ {
+		y.Square(&y)
+	}
+	assignment := refCircuit{X: x, Y: y}
+	if _, err := full.FromAssignment(&assignment, tVariable, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := public.FromAssignment(&assignment, tVariable, true); err != nil {
+		t.Fatal(err)
+	}
+	return full, public
+}
+
+func (p *testProblem) setup(t *testing.T, opt backend.SetupConfig) (*bn254groth16.ProvingKey, *bn254groth16.VerifyingKey) {
+	t.Helper()
+	var pk bn254groth16.ProvingKey
+	var vk bn254groth16.VerifyingKey
+	if err := bn254groth16.Setup(p.ccs, &pk, &vk, opt); err != nil {
+		t.Fatal(err)
+	}
+	return &pk, &vk
+}
+
+// prove proves the witness of X = 2 with pk, and checks the proof against vk
+func (p *testProblem) prove(t *testing.T, pk *bn254groth16.ProvingKey, vk *bn254groth16.VerifyingKey, opt backend.ProverConfig) *bn254groth16.Proof {
+	t.Helper()
+	proof, err := bn254groth16.Prove(p.ccs, pk, p.fullWitness, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bn254groth16.Verify(proof, vk, p.publicWitness); err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+// serialize returns the concatenated binary encodings of objects
+func serialize(objects ...io.WriterTo) []byte {
+	var buf bytes.Buffer
+	for _, o := range objects {
+		_, _ = o.WriteTo(&buf)
+	}
+	return buf.Bytes()
+}
+
+func TestRandomSource(t *testing.T) {
+	p := newTestProblem(t, 3)
+
+	// setupAndProve returns the serialized keys and proof generated from seed
+	setupAndProve := func(seed int64) []byte {
+		rnd := rand.New(rand.NewSource(seed))
+		pk, vk := p.setup(t, backend.SetupConfig{RandomSource: rnd})
+		proof := p.prove(t, pk, vk, backend.ProverConfig{RandomSource: rnd})
+		return serialize(pk, vk, proof)
+	}
+
+	if !bytes.Equal(setupAndProve(42), setupAndProve(42)) {
+		t.Fatal("keys and proof differ for the same seed")
+	}
+	if bytes.Equal(setupAndProve(42), setupAndProve(43)) {
+		t.Fatal("keys and proof are equal for different seeds")
+	}
+}
+
+func TestNbTasks(t *testing.T) {
+	// large enough for the bounded FFTs and multi exps to split their work
+	p := newTestProblem(t, 300)
+
+	// setupAndProve returns the serialized keys and proof generated with nbTasks
+	setupAndProve := func(nbTasks int) []byte {
+		rnd := rand.New(rand.NewSource(42))
+		pk, vk := p.setup(t, backend.SetupConfig{RandomSource: rnd, NbTasks: nbTasks})
+		proof := p.prove(t, pk, vk, backend.ProverConfig{RandomSource: rnd, NbTasks: nbTasks})
+		return serialize(pk, vk, proof)
+	}
+
+	// the number of tasks doesn't change the result
+	expected := setupAndProve(0)
+	for _, nbTasks := range []int{1, 2, 3} {
+		if !bytes.Equal(setupAndProve(nbTasks), expected) {
+			t.Fatalf("keys and proof computed with %d tasks differ", nbTasks)
+		}
+	}
+}
+
+func TestProveDistributed(t *testing.T) {
+	p := newTestProblem(t, 300)
+	proof := p.prove(t, p.pk, p.vk, backend.ProverConfig{RandomSource: rand.New(rand.NewSource(42))})
+
+	// the distributed prover returns the same proof, whatever the number of workers
+	for _, nbWorkers := range []int{1, 2, 3} {
+		coordinator, shards := p.pk.Split(nbWorkers)
+		workers := make([]bn254groth16.WorkerClient, nbWorkers)
+		for i, shard := range shards {
+			// the shards are sent to the workers serialized
+			var read bn254groth16.KeyShard
+			if _, err := read.ReadFrom(bytes.NewReader(serialize(shard))); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(shard, &read) {
+				t.Fatal("reconstructed key shard doesn't match original")
+			}
+			workers[i] = bn254groth16.NewWorker(&read, 1)
+		}
+
+		distributedProof, err := bn254groth16.ProveDistributed(p.ccs, coordinator, p.fullWitness, workers, backend.ProverConfig{RandomSource: rand.New(rand.NewSource(42))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(proof, distributedProof) {
+			t.Fatalf("proof computed with %d workers differs", nbWorkers)
+		}
+	}
+}
+
+func TestProver(t *testing.T) {
+	p := newTestProblem(t, 300)
+
+	// witnesses X = 2, 3, 4
+	witnesses := make([]bn254witness.Witness, 3)
+	publicWitnesses := make([]bn254witness.Witness, 3)
+	for i := range witnesses {
+		witnesses[i], publicWitnesses[i] = p.witnesses(t, uint64(i+2))
+	}
+
+	// proofs of successive Prove calls
+	rnd := rand.New(rand.NewSource(42))
+	expected := make([]*bn254groth16.Proof, len(witnesses))
+	for i := range witnesses {
+		var err error
+		if expected[i], err = bn254groth16.Prove(p.ccs, p.pk, witnesses[i], backend.ProverConfig{RandomSource: rnd}); err != nil {
+			t.Fatal(err)
+		}
+		if err := bn254groth16.Verify(expected[i], p.vk, publicWitnesses[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prover, err := bn254groth16.NewProver(p.ccs, p.pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the prover reuses its buffers, with the solver run sequentially or along the prover
+	for _, nbTasks := range []int{1, runtime.NumCPU() + 1} {
+		proofs, err := prover.ProveBatch(witnesses, backend.ProverConfig{RandomSource: rand.New(rand.NewSource(42)), NbTasks: nbTasks})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, proofs) {
+			t.Fatalf("batch proofs with %d tasks differ from successive Prove calls", nbTasks)
+		}
+
+		rnd := rand.New(rand.NewSource(42))
+		for i := range witnesses {
+			proof, err := prover.Prove(witnesses[i], backend.ProverConfig{RandomSource: rnd, NbTasks: nbTasks})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expected[i], proof) {
+				t.Fatalf("proof %d with %d tasks differs from Prove", i, nbTasks)
+			}
+		}
+	}
+
+	// the invalid witness of a batch is reported
+	invalid := append(bn254witness.Witness{}, witnesses[1]...)
+	invalid[0].SetOne()
+	_, err = prover.ProveBatch([]bn254witness.Witness{witnesses[0], invalid, witnesses[2]}, backend.ProverConfig{NbTasks: runtime.NumCPU() + 1})
+	if err == nil || !strings.Contains(err.Error(), "witness 1") {
+		t.Fatalf("expected an error on witness 1, got %v", err)
+	}
+
+	// the proving key must match the circuit
+	other, err := frontend.Compile(curve.ID, r1cs.NewBuilder, &refCircuit{nbConstraints: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bn254groth16.NewProver(other.(*cs.R1CS), p.pk); !errors.Is(err, backend.ErrCircuitMismatch) {
+		t.Fatalf("expected a circuit mismatch, got %v", err)
+	}
+}
+
+func TestVerifyPrepared(t *testing.T) {
+	p := newTestProblem(t, 3)
+	proof := p.prove(t, p.pk, p.vk, backend.ProverConfig{})
+
+	// the prepared key survives serialization
+	var pvk bn254groth16.PreparedVerifyingKey
+	if _, err := pvk.ReadFrom(bytes.NewReader(serialize(bn254groth16.Prepare(p.vk)))); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bn254groth16.VerifyPrepared(proof, &pvk, p.publicWitness); err != nil {
+		t.Fatal(err)
+	}
+	wrongWitness := append(bn254witness.Witness{}, p.publicWitness...)
+	wrongWitness[0].SetUint64(255)
+	if err := bn254groth16.VerifyPrepared(proof, &pvk, wrongWitness); err == nil {
+		t.Fatal("proof verifies with a wrong public witness")
+	}
+}
+
+func TestRerandomize(t *testing.T) {
+	p := newTestProblem(t, 3)
+	proof := p.prove(t, p.pk, p.vk, backend.ProverConfig{})
+
+	rerandomized, err := bn254groth16.Rerandomize(proof, p.vk, backend.ProverConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bn254groth16.Verify(rerandomized, p.vk, p.publicWitness); err != nil {
+		t.Fatal(err)
+	}
+	if proof.Ar.Equal(&rerandomized.Ar) || proof.Bs.Equal(&rerandomized.Bs) || proof.Krs.Equal(&rerandomized.Krs) {
+		t.Fatal("rerandomized proof shares points with the original proof")
+	}
+
+	// the rerandomized proof is still bound to the statement
+	wrongWitness := append(bn254witness.Witness{}, p.publicWitness...)
+	wrongWitness[0].SetUint64(255)
+	if err := bn254groth16.Verify(rerandomized, p.vk, wrongWitness); err == nil {
+		t.Fatal("rerandomized proof verifies with a wrong public witness")
+	}
+
+	// the rerandomization is determined by the random source
+	rerandomize := func(seed int64) *bn254groth16.Proof {
+		res, err := bn254groth16.Rerandomize(proof, p.vk, backend.ProverConfig{RandomSource: rand.New(rand.NewSource(seed))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	if !reflect.DeepEqual(rerandomize(42), rerandomize(42)) {
+		t.Fatal("rerandomizing with the same seed gives different proofs")
+	}
+	if reflect.DeepEqual(rerandomize(42), rerandomize(43)) {
+		t.Fatal("rerandomizing with different seeds gives the same proof")
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -88,7 +361,7 @@ func BenchmarkSetup(b *testing.B) {
 
 	b.Run("setup", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			bn254groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk)
+			bn254groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk, backend.SetupConfig{})
 		}
 	})
 }
@@ -127,7 +400,7 @@ func BenchmarkVerifier(b *testing.B) {
 
 	var pk bn254groth16.ProvingKey
 	var vk bn254groth16.VerifyingKey
-	bn254groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk)
+	bn254groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk, backend.SetupConfig{})
 	proof, err := bn254groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, backend.ProverConfig{})
 	if err != nil {
 		panic(err)
@@ -139,6 +412,12 @@ func BenchmarkVerifier(b *testing.B) {
 			_ = bn254groth16.Verify(proof, &vk, publicWitness)
 		}
 	})
+	pvk := bn254groth16.Prepare(&vk)
+	b.Run("prepared verifier", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = bn254groth16.VerifyPrepared(proof, pvk, publicWitness)
+		}
+	})
 }
 
 func BenchmarkProofSerialization(b *testing.B) {
@@ -151,7 +430,7 @@ func BenchmarkProofSerialization(b *testing.B) {
 
 	var pk bn254groth16.ProvingKey
 	var vk bn254groth16.VerifyingKey
-	bn254groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk)
+	bn254groth16.Setup(r1cs.(*cs.R1CS), &pk, &vk, backend.SetupConfig{})
 	proof, err := bn254groth16.Prove(r1cs.(*cs.R1CS), &pk, fullWitness, backend.ProverConfig{})
 	if err != nil {
 		panic(err)
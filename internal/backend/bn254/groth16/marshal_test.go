@@ -157,7 +157,28 @@ func TestVerifyingKeySerialization(t *testing.T) {
 				return false
 			}
 
-			return reflect.DeepEqual(&vk, &vkCompressed) && reflect.DeepEqual(&vk, &vkRaw)
+			// the precomputations of the prepared key are serialized
+			pvk := Prepare(&vk)
+			var bufPrepared bytes.Buffer
+			written, err = pvk.WriteTo(&bufPrepared)
+			if err != nil {
+				t.Log(err)
+				return false
+			}
+
+			var pvkRead PreparedVerifyingKey
+			read, err = pvkRead.ReadFrom(&bufPrepared)
+			if err != nil {
+				t.Log(err)
+				return false
+			}
+
+			if read != written {
+				t.Log("read prepared != written")
+				return false
+			}
+
+			return reflect.DeepEqual(&vk, &vkCompressed) && reflect.DeepEqual(&vk, &vkRaw) && reflect.DeepEqual(pvk, &pvkRead)
 		},
 		GenG1(),
 		GenG2(),
@@ -37,6 +37,18 @@ var (
 
 // Verify verifies a proof with given VerifyingKey and publicWitness
 func Verify(proof *Proof, vk *VerifyingKey, publicWitness bw6_633witness.Witness) error {
+	return verify(proof, vk, nil, publicWitness)
+}
+
+// VerifyPrepared verifies a proof with given PreparedVerifyingKey and publicWitness
+func VerifyPrepared(proof *Proof, pvk *PreparedVerifyingKey, publicWitness bw6_633witness.Witness) error {
+	return verify(proof, &pvk.VerifyingKey, pvk, publicWitness)
+}
+
+// verify verifies the proof, with the precomputed lines of pvk if it is not nil
+//
+// The Miller loop of BW6-633 iterates on the G1 point, so pvk has no precomputed lines.
+func verify(proof *Proof, vk *VerifyingKey, pvk *PreparedVerifyingKey, publicWitness bw6_633witness.Witness) error {
 
 	if len(publicWitness) != (len(vk.G1.K) - 1) {
 		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), len(vk.G1.K)-1)
@@ -52,7 +64,7 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bw6_633witness.Witness
 	var doubleML curve.GT
 	chDone := make(chan error, 1)
 
-	// compute (eKrsδ, eArBs)
+	// compute (eKrsδ, eArBs), or eArBs only if the lines of -[δ]2 are precomputed
 	go func() {
 		var errML error
 		doubleML, errML = curve.MillerLoop([]curve.G1Affine{proof.Krs, proof.Ar}, []curve.G2Affine{vk.G2.deltaNeg, proof.Bs})
@@ -88,6 +100,26 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bw6_633witness.Witness
 	return nil
 }
 
+// PreparedVerifyingKey is a VerifyingKey with the precomputations of the pairings of VerifyPrepared.
+//
+// Its serialization includes e(α, β),
+// so that reading it doesn't compute a pairing.
+type PreparedVerifyingKey struct {
+	VerifyingKey
+}
+
+// Prepare returns the PreparedVerifyingKey of vk
+func Prepare(vk *VerifyingKey) *PreparedVerifyingKey {
+	pvk := &PreparedVerifyingKey{VerifyingKey: *vk}
+	return pvk
+}
+
+// IsDifferent returns true if the keys differ (see VerifyingKey.IsDifferent)
+func (pvk *PreparedVerifyingKey) IsDifferent(_other interface{}) bool {
+	pvk2 := _other.(*PreparedVerifyingKey)
+	return pvk.VerifyingKey.IsDifferent(&pvk2.VerifyingKey)
+}
+
 // ExportSolidity not implemented for BW6-633
 func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
 	return errors.New("not implemented")
@@ -28,9 +28,14 @@ import (
 	bw6_633plonk "github.com/consensys/gnark/internal/backend/bw6-633/plonk"
 
 	"bytes"
+	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bw6-633/fr/kzg"
+	"io"
 	"math/big"
+	"math/rand"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -39,6 +44,243 @@ import (
 	"github.com/consensys/gnark/frontend/cs/scs"
 )
 
+// testProblem is the fixture of the tests: refCircuit compiled with nbConstraints, the witnesses
+// of X = 2, an SRS, and the keys of a setup with the default options
+type testProblem struct {
+	nbConstraints              int
+	ccs                        *cs.SparseR1CS
+	fullWitness, publicWitness bw6_633witness.Witness
+	srs                        *kzg.SRS
+	pk                         *bw6_633plonk.ProvingKey
+	vk                         *bw6_633plonk.VerifyingKey
+}
+
+func newTestProblem(t *testing.T, nbConstraints int) *testProblem {
+	t.Helper()
+	ccs, err := frontend.Compile(curve.ID, scs.NewBuilder, &refCircuit{nbConstraints: nbConstraints})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spr := ccs.(*cs.SparseR1CS)
+	srs, err := kzg.NewSRS(ecc.NextPowerOfTwo(uint64(spr.NbPublicVariables+len(spr.Constraints)))+3, new(big.Int).SetUint64(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testProblem{nbConstraints: nbConstraints, ccs: spr, srs: srs}
+	p.fullWitness, p.publicWitness = p.witnesses(t, 2)
+	p.pk, p.vk = p.setup(t, backend.SetupConfig{})
+	return p
+}
+
+// witnesses returns the full and public witnesses of X = x, Y = X**(2**nbConstraints)
+func (p *testProblem) witnesses(t *testing.T, x uint64) (full, public bw6_633witness.Witness) {
+	t.Helper()
+	var y fr.Element
+	y.SetUint64(x)
+	for i := 0; i < p.nbConstraints; i++ {
+		y.Square(&y)
+	}
+	assignment := refCircuit{X: x, Y: y}
+	if _, err := full.FromAssignment(&assignment, tVariable, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := public.FromAssignment(&assignment, tVariable, true); err != nil {
+		t.Fatal(err)
+	}
+	return full, public
+}
+
+func (p *testProblem) setup(t *testing.T, opt backend.SetupConfig) (*bw6_633plonk.ProvingKey, *bw6_633plonk.VerifyingKey) {
+	t.Helper()
+	pk, vk, err := bw6_633plonk.Setup(p.ccs, p.srs, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk, vk
+}
+
+// prove proves the witness of X = 2 with pk, and checks the proof against vk
+func (p *testProblem) prove(t *testing.T, pk *bw6_633plonk.ProvingKey, vk *bw6_633plonk.VerifyingKey, opt backend.ProverConfig) *bw6_633plonk.Proof {
+	t.Helper()
+	proof, err := bw6_633plonk.Prove(p.ccs, pk, p.fullWitness, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bw6_633plonk.Verify(proof, vk, p.publicWitness); err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+// serialize returns the concatenated binary encodings of objects
+func serialize(objects ...io.WriterTo) []byte {
+	var buf bytes.Buffer
+	for _, o := range objects {
+		_, _ = o.WriteTo(&buf)
+	}
+	return buf.Bytes()
+}
+
+func TestRandomSource(t *testing.T) {
+	p := newTestProblem(t, 3)
+
+	// setupAndProve returns the serialized verifying key and proof generated from seed
+	setupAndProve := func(seed int64) []byte {
+		rnd := rand.New(rand.NewSource(seed))
+		pk, vk := p.setup(t, backend.SetupConfig{RandomSource: rnd})
+		proof := p.prove(t, pk, vk, backend.ProverConfig{RandomSource: rnd})
+		return serialize(vk, proof)
+	}
+
+	if !bytes.Equal(setupAndProve(42), setupAndProve(42)) {
+		t.Fatal("proof differs for the same seed")
+	}
+	if bytes.Equal(setupAndProve(42), setupAndProve(43)) {
+		t.Fatal("proof is equal for different seeds")
+	}
+}
+
+func TestNbTasks(t *testing.T) {
+	// large enough for the bounded FFTs and multi exps to split their work
+	p := newTestProblem(t, 300)
+
+	// setupAndProve returns the serialized verifying key and proof generated with nbTasks
+	setupAndProve := func(nbTasks int) []byte {
+		rnd := rand.New(rand.NewSource(42))
+		pk, vk := p.setup(t, backend.SetupConfig{RandomSource: rnd, NbTasks: nbTasks})
+		proof := p.prove(t, pk, vk, backend.ProverConfig{RandomSource: rnd, NbTasks: nbTasks})
+		return serialize(vk, proof)
+	}
+
+	// the number of tasks doesn't change the result
+	expected := setupAndProve(0)
+	for _, nbTasks := range []int{1, 2, 3} {
+		if !bytes.Equal(setupAndProve(nbTasks), expected) {
+			t.Fatalf("verifying key and proof computed with %d tasks differ", nbTasks)
+		}
+	}
+}
+
+func TestProver(t *testing.T) {
+	p := newTestProblem(t, 300)
+
+	// witnesses X = 2, 3, 4
+	witnesses := make([]bw6_633witness.Witness, 3)
+	publicWitnesses := make([]bw6_633witness.Witness, 3)
+	for i := range witnesses {
+		witnesses[i], publicWitnesses[i] = p.witnesses(t, uint64(i+2))
+	}
+
+	// proofs of successive Prove calls
+	rnd := rand.New(rand.NewSource(42))
+	expected := make([]*bw6_633plonk.Proof, len(witnesses))
+	for i := range witnesses {
+		var err error
+		if expected[i], err = bw6_633plonk.Prove(p.ccs, p.pk, witnesses[i], backend.ProverConfig{RandomSource: rnd}); err != nil {
+			t.Fatal(err)
+		}
+		if err := bw6_633plonk.Verify(expected[i], p.vk, publicWitnesses[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prover, err := bw6_633plonk.NewProver(p.ccs, p.pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the prover reuses its buffers, with the solver run sequentially or along the prover
+	for _, nbTasks := range []int{1, runtime.NumCPU() + 1} {
+		proofs, err := prover.ProveBatch(witnesses, backend.ProverConfig{RandomSource: rand.New(rand.NewSource(42)), NbTasks: nbTasks})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, proofs) {
+			t.Fatalf("batch proofs with %d tasks differ from successive Prove calls", nbTasks)
+		}
+
+		rnd := rand.New(rand.NewSource(42))
+		for i := range witnesses {
+			proof, err := prover.Prove(witnesses[i], backend.ProverConfig{RandomSource: rnd, NbTasks: nbTasks})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expected[i], proof) {
+				t.Fatalf("proof %d with %d tasks differs from Prove", i, nbTasks)
+			}
+		}
+	}
+
+	// the invalid witness of a batch is reported
+	invalid := append(bw6_633witness.Witness{}, witnesses[1]...)
+	invalid[0].SetOne()
+	_, err = prover.ProveBatch([]bw6_633witness.Witness{witnesses[0], invalid, witnesses[2]}, backend.ProverConfig{NbTasks: runtime.NumCPU() + 1})
+	if err == nil || !strings.Contains(err.Error(), "witness 1") {
+		t.Fatalf("expected an error on witness 1, got %v", err)
+	}
+
+	// the proving key must match the circuit
+	other, err := frontend.Compile(curve.ID, scs.NewBuilder, &refCircuit{nbConstraints: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bw6_633plonk.NewProver(other.(*cs.SparseR1CS), p.pk); !errors.Is(err, backend.ErrCircuitMismatch) {
+		t.Fatalf("expected a circuit mismatch, got %v", err)
+	}
+}
+
+func TestWitnessSize(t *testing.T) {
+	p := newTestProblem(t, 3)
+	prover, err := bw6_633plonk.NewProver(p.ccs, p.pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a short or long witness is rejected before solving, even when forcing an invalid witness
+	for _, witness := range []bw6_633witness.Witness{p.fullWitness[:1], append(p.fullWitness, fr.Element{})} {
+		opt := backend.ProverConfig{Force: true}
+		if _, err := bw6_633plonk.Prove(p.ccs, p.pk, witness, opt); err == nil || !strings.Contains(err.Error(), "invalid witness size") {
+			t.Fatalf("Prove: expected an invalid witness size, got %v", err)
+		}
+		if _, err := bw6_633plonk.Solve(p.ccs, witness, opt); err == nil || !strings.Contains(err.Error(), "invalid witness size") {
+			t.Fatalf("Solve: expected an invalid witness size, got %v", err)
+		}
+		if _, err := prover.Prove(witness, opt); err == nil || !strings.Contains(err.Error(), "invalid witness size") {
+			t.Fatalf("Prover.Prove: expected an invalid witness size, got %v", err)
+		}
+	}
+}
+
+func TestVerifyPrepared(t *testing.T) {
+	p := newTestProblem(t, 3)
+	proof := p.prove(t, p.pk, p.vk, backend.ProverConfig{})
+
+	// the prepared key survives serialization, without the SRS
+	prepared, err := bw6_633plonk.Prepare(p.vk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pvk bw6_633plonk.PreparedVerifyingKey
+	if _, err := pvk.ReadFrom(bytes.NewReader(serialize(prepared))); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bw6_633plonk.VerifyPrepared(proof, &pvk, p.publicWitness); err != nil {
+		t.Fatal(err)
+	}
+	wrongWitness := append(bw6_633witness.Witness{}, p.publicWitness...)
+	wrongWitness[0].SetUint64(255)
+	if err := bw6_633plonk.VerifyPrepared(proof, &pvk, wrongWitness); err == nil {
+		t.Fatal("proof verifies with a wrong public witness")
+	}
+
+	// the SRS is needed to prepare a key
+	p.vk.KZGSRS = nil
+	if _, err := bw6_633plonk.Prepare(p.vk); err == nil {
+		t.Fatal("expected an error without SRS")
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -94,7 +336,7 @@ func BenchmarkSetup(b *testing.B) {
 
 	b.Run("setup", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _, _ = bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs)
+			_, _, _ = bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs, backend.SetupConfig{})
 		}
 	})
 }
@@ -107,7 +349,7 @@ func BenchmarkProver(b *testing.B) {
 		b.Fatal(err)
 	}
 
-	pk, _, err := bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs)
+	pk, _, err := bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs, backend.SetupConfig{})
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -134,7 +376,7 @@ func BenchmarkVerifier(b *testing.B) {
 		b.Fatal(err)
 	}
 
-	pk, vk, err := bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs)
+	pk, vk, err := bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs, backend.SetupConfig{})
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -158,7 +400,7 @@ func BenchmarkSerialization(b *testing.B) {
 		b.Fatal(err)
 	}
 
-	pk, _, err := bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs)
+	pk, _, err := bw6_633plonk.Setup(ccs.(*cs.SparseR1CS), srs, backend.SetupConfig{})
 	if err != nil {
 		b.Fatal(err)
 	}
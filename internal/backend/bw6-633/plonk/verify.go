@@ -39,7 +39,20 @@ var (
 	errWrongClaimedQuotient = errors.New("claimed quotient is not as expected")
 )
 
+// Verify verifies a proof with given VerifyingKey and publicWitness
 func Verify(proof *Proof, vk *VerifyingKey, publicWitness bw6_633witness.Witness) error {
+	return verify(proof, vk, nil, publicWitness)
+}
+
+// VerifyPrepared verifies a proof with given PreparedVerifyingKey and publicWitness
+func VerifyPrepared(proof *Proof, pvk *PreparedVerifyingKey, publicWitness bw6_633witness.Witness) error {
+	return verify(proof, &pvk.VerifyingKey, pvk, publicWitness)
+}
+
+// verify verifies the proof, with the precomputed lines of pvk if it is not nil
+//
+// The Miller loop of BW6-633 iterates on the G1 point, so pvk has no precomputed lines.
+func verify(proof *Proof, vk *VerifyingKey, pvk *PreparedVerifyingKey, publicWitness bw6_633witness.Witness) error {
 	log := logger.Logger().With().Str("curve", "bw6_633").Str("backend", "plonk").Logger()
 	start := time.Now()
 
@@ -226,26 +239,40 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bw6_633witness.Witness
 	// Batch verify
 	var shiftedZeta fr.Element
 	shiftedZeta.Mul(&zeta, &vk.Generator)
-	err = kzg.BatchVerifyMultiPoints([]kzg.Digest{
+	digests := []kzg.Digest{
 		foldedDigest,
 		proof.Z,
-	},
-		[]kzg.OpeningProof{
-			foldedProof,
-			proof.ZShiftedOpening,
-		},
-		[]fr.Element{
-			zeta,
-			shiftedZeta,
-		},
-		vk.KZGSRS,
-	)
+	}
+	openingProofs := []kzg.OpeningProof{
+		foldedProof,
+		proof.ZShiftedOpening,
+	}
+	zetas := []fr.Element{
+		zeta,
+		shiftedZeta,
+	}
+	err = kzg.BatchVerifyMultiPoints(digests, openingProofs, zetas, vk.KZGSRS)
 
 	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
 
 	return err
 }
 
+// PreparedVerifyingKey is a VerifyingKey with the precomputations of the pairings of VerifyPrepared.
+type PreparedVerifyingKey struct {
+	VerifyingKey
+}
+
+// Prepare returns the PreparedVerifyingKey of vk, whose KZG SRS must be set (see InitKZG)
+func Prepare(vk *VerifyingKey) (*PreparedVerifyingKey, error) {
+	if vk.KZGSRS == nil {
+		return nil, errors.New("the KZG SRS of the verifying key is not set")
+	}
+	pvk := &PreparedVerifyingKey{VerifyingKey: *vk}
+
+	return pvk, nil
+}
+
 func bindPublicData(fs *fiatshamir.Transcript, challenge string, vk VerifyingKey, publicInputs []fr.Element) error {
 
 	// permutation
@@ -111,9 +111,13 @@ func TestSerialization(t *testing.T) {
 					t.Fatal(nil)
 				}
 
-				if !reflect.DeepEqual(r, r2) {
+				if !reflect.DeepEqual(&r, &r2) {
 					t.Fatal("compilation of R1CS is not deterministic (reconstruction)")
 				}
+
+				if r1cs1.Digest() != r1cs2.Digest() || r.Digest() != r1cs1.Digest() {
+					t.Fatal("circuit digest is not deterministic")
+				}
 			}
 		})
 
@@ -0,0 +1,60 @@
+package circuits
+
+import (
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/frontend"
+)
+
+// circuit designed to test XOR and OR with a constant operand, which
+// the plonk compiler encodes with a single constraint
+type orXorConstantCircuit struct {
+	A                              frontend.Variable
+	XorZero, XorOne, OrZero, OrOne frontend.Variable
+}
+
+func (circuit *orXorConstantCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Xor(circuit.A, 0), circuit.XorZero)
+	api.AssertIsEqual(api.Xor(1, circuit.A), circuit.XorOne)
+	api.AssertIsEqual(api.Or(0, circuit.A), circuit.OrZero)
+	api.AssertIsEqual(api.Or(circuit.A, 1), circuit.OrOne)
+	return nil
+}
+
+func init() {
+
+	good := []frontend.Circuit{
+		&orXorConstantCircuit{
+			A:       (0),
+			XorZero: (0),
+			XorOne:  (1),
+			OrZero:  (0),
+			OrOne:   (1),
+		},
+		&orXorConstantCircuit{
+			A:       (1),
+			XorZero: (1),
+			XorOne:  (0),
+			OrZero:  (1),
+			OrOne:   (1),
+		},
+	}
+
+	bad := []frontend.Circuit{
+		&orXorConstantCircuit{
+			A:       (1),
+			XorZero: (1),
+			XorOne:  (1),
+			OrZero:  (1),
+			OrOne:   (1),
+		},
+		&orXorConstantCircuit{
+			A:       (1),
+			XorZero: (1),
+			XorOne:  (0),
+			OrZero:  (1),
+			OrOne:   (0),
+		},
+	}
+
+	addNewEntry("orXorConstant", &orXorConstantCircuit{}, good, bad, gnark.Curves())
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/consensys/bavard"
@@ -102,11 +103,35 @@ func main() {
 				panic(err)
 			}
 
+			// the Miller loop of BW6 curves iterates on the G1 point, its lines can't be precomputed from G2
+			if !strings.HasPrefix(d.Curve, "BW6") {
+				pairingDir := filepath.Join(d.RootPath, "pairing")
+				if err := os.MkdirAll(pairingDir, 0700); err != nil {
+					panic(err)
+				}
+				entries = []bavard.Entry{
+					{File: filepath.Join(pairingDir, "pairing.go"), Templates: []string{"pairing.go.tmpl", importCurve}},
+				}
+				if err := bgen.Generate(d, "pairing", "./template/pairing/", entries...); err != nil {
+					panic(err)
+				}
+
+				entries = []bavard.Entry{
+					{File: filepath.Join(pairingDir, "pairing_test.go"), Templates: []string{"tests/pairing.go.tmpl", importCurve}},
+				}
+				if err := bgen.Generate(d, "pairing_test", "./template/pairing/", entries...); err != nil {
+					panic(err)
+				}
+			}
+
 			entries = []bavard.Entry{
 				{File: filepath.Join(groth16Dir, "verify.go"), Templates: []string{"groth16/groth16.verify.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "prove.go"), Templates: []string{"groth16/groth16.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "setup.go"), Templates: []string{"groth16/groth16.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal.go"), Templates: []string{"groth16/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "tasks.go"), Templates: []string{"groth16/groth16.tasks.go.tmpl", "tasks.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "distributed.go"), Templates: []string{"groth16/groth16.distributed.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "prover.go"), Templates: []string{"groth16/groth16.prover.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal_test.go"), Templates: []string{"groth16/tests/groth16.marshal.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "groth16", "./template/zkpschemes/", entries...); err != nil {
@@ -126,6 +151,8 @@ func main() {
 				{File: filepath.Join(plonkDir, "prove.go"), Templates: []string{"plonk/plonk.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "setup.go"), Templates: []string{"plonk/plonk.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal.go"), Templates: []string{"plonk/plonk.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "tasks.go"), Templates: []string{"plonk/plonk.tasks.go.tmpl", "tasks.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "prover.go"), Templates: []string{"plonk/plonk.prover.go.tmpl", importCurve}},
 				{File: filepath.Join(plonkDir, "marshal_test.go"), Templates: []string{"plonk/tests/marshal.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "plonk", "./template/zkpschemes/", entries...); err != nil {
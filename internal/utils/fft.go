@@ -0,0 +1,76 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import "math/bits"
+
+// butterflyThreshold is the size under which the butterflies of a FFT stage are not parallelized
+const butterflyThreshold = 16
+
+// FFTButterflies applies the butterflies of a stage of a radix-2 FFT to the pairs (offset+i, offset+i+m),
+// for start <= i < end; the sub-FFT of the stage has size 2m and starts at offset.
+type FFTButterflies func(offset, m, stage, start, end int)
+
+// BoundedFFT schedules the stages of a radix-2 FFT of size n (a power of 2) as the FFTs of gnark-crypto do,
+// with at most nbTasks go routines. With dit (decimation in time), the sub-FFTs of a stage are computed
+// before its butterflies; otherwise (decimation in frequency), after them.
+//
+// The butterflies depend on the field of the curve; the backends provide them, and share the scheduling.
+func BoundedFFT(n int, dit bool, butterflies FFTButterflies, nbTasks int) {
+	maxSplits := bits.Len(uint(NbTasks(nbTasks))) - 1
+	fftStage(0, n, 0, maxSplits, dit, butterflies, nbTasks)
+}
+
+func fftStage(offset, n, stage, maxSplits int, dit bool, butterflies FFTButterflies, nbTasks int) {
+	if n == 1 {
+		return
+	}
+	m := n >> 1
+
+	// at this stage, 1 << stage go routines share the nbTasks
+	applyButterflies := func() {
+		if (m > butterflyThreshold) && (stage < maxSplits) {
+			Parallelize(m, func(start, end int) {
+				butterflies(offset, m, stage, start, end)
+			}, nbTasks>>stage)
+		} else {
+			butterflies(offset, m, stage, 0, m)
+		}
+	}
+
+	if !dit {
+		applyButterflies()
+	}
+
+	nextStage := stage + 1
+	if stage < maxSplits {
+		chDone := make(chan struct{})
+		go func() {
+			fftStage(offset+m, m, nextStage, maxSplits, dit, butterflies, nbTasks)
+			close(chDone)
+		}()
+		fftStage(offset, m, nextStage, maxSplits, dit, butterflies, nbTasks)
+		<-chDone
+	} else {
+		fftStage(offset, m, nextStage, maxSplits, dit, butterflies, nbTasks)
+		fftStage(offset+m, m, nextStage, maxSplits, dit, butterflies, nbTasks)
+	}
+
+	if dit {
+		applyButterflies()
+	}
+}
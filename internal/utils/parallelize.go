@@ -6,12 +6,18 @@ import (
 )
 
 // Parallelize process in parallel the work function
+//
+// maxCpus bounds the number of go routines; if it is 1, work is called in the calling go routine.
 func Parallelize(nbIterations int, work func(int, int), maxCpus ...int) {
 
 	nbTasks := runtime.NumCPU()
-	if len(maxCpus) == 1 {
+	if len(maxCpus) == 1 && maxCpus[0] > 0 {
 		nbTasks = maxCpus[0]
 	}
+	if nbTasks == 1 {
+		work(0, nbIterations)
+		return
+	}
 	nbIterationsPerCpus := nbIterations / nbTasks
 
 	// more CPUs than tasks: a CPU will work on exactly one iteration
@@ -42,3 +48,31 @@ func Parallelize(nbIterations int, work func(int, int), maxCpus ...int) {
 
 	wg.Wait()
 }
+
+// Bounded returns true if nbTasks limits the parallelism of a computation below runtime.NumCPU().
+// nbTasks <= 0 means no limit; nbTasks == 1 is always bounded (sequential computation).
+//
+// gnark-crypto sizes its FFTs on runtime.NumCPU(); the backends schedule them with BoundedFFT,
+// and pass nbTasks to the multi exponentiations, when this returns true.
+func Bounded(nbTasks int) bool {
+	return nbTasks == 1 || (nbTasks > 0 && nbTasks < runtime.NumCPU())
+}
+
+// NbTasks returns the number of go routines a computation bounded by nbTasks may use:
+// nbTasks, or runtime.NumCPU() if nbTasks <= 0 (no limit).
+func NbTasks(nbTasks int) int {
+	if nbTasks <= 0 {
+		return runtime.NumCPU()
+	}
+	return nbTasks
+}
+
+// Go calls f in a new go routine, unless nbTasks is Bounded: f is then called in the
+// calling go routine, and the independent parts of a computation run one after the other.
+func Go(nbTasks int, f func()) {
+	if Bounded(nbTasks) {
+		f()
+		return
+	}
+	go f()
+}
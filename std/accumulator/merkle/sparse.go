@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// The gadgets below verify paths of sparse Merkle trees, as built by SparseTree: a tree of
+// depth len(path) whose leaf at index is the field element leaf, empty leaves being 0.
+// path[i] is the sibling of the node of height i on the path from the leaf to the root,
+// and bit i of index tells if that node is a right child.
+//
+// h is reset before each hash.
+
+// SparseRoot returns the root of the sparse Merkle tree with leaf at index, and
+// siblings path. index is constrained to be less than 2^len(path).
+func SparseRoot(api frontend.API, h hash.Hash, index, leaf frontend.Variable, path []frontend.Variable) frontend.Variable {
+	return sparseRoot(api, h, indexBits(api, index, len(path)), leaf, path)
+}
+
+// VerifySparseMembership checks that leaf is at index in the sparse Merkle tree of root root.
+func VerifySparseMembership(api frontend.API, h hash.Hash, root, index, leaf frontend.Variable, path []frontend.Variable) {
+	api.AssertIsEqual(SparseRoot(api, h, index, leaf, path), root)
+}
+
+// VerifySparseNonMembership checks that the leaf at index is empty in the sparse Merkle
+// tree of root root.
+func VerifySparseNonMembership(api frontend.API, h hash.Hash, root, index frontend.Variable, path []frontend.Variable) {
+	VerifySparseMembership(api, h, root, index, 0, path)
+}
+
+// VerifySparseUpdate checks that setting the leaf at index from oldLeaf to newLeaf takes the
+// sparse Merkle tree of root oldRoot to the tree of root newRoot.
+//
+// Updating a leaf doesn't change its siblings, so the same path opens both trees. Insertions
+// and deletions are updates from and to an empty leaf (0).
+func VerifySparseUpdate(api frontend.API, h hash.Hash, oldRoot, newRoot, index, oldLeaf, newLeaf frontend.Variable, path []frontend.Variable) {
+	b := indexBits(api, index, len(path))
+	api.AssertIsEqual(sparseRoot(api, h, b, oldLeaf, path), oldRoot)
+	api.AssertIsEqual(sparseRoot(api, h, b, newLeaf, path), newRoot)
+}
+
+// indexBits returns the depth bits of index, least significant first
+func indexBits(api frontend.API, index frontend.Variable, depth int) []frontend.Variable {
+	return bits.ToBinary(api, index, bits.WithNbDigits(depth))
+}
+
+func sparseRoot(api frontend.API, h hash.Hash, indexBits []frontend.Variable, leaf frontend.Variable, path []frontend.Variable) frontend.Variable {
+	node := leaf
+	for i := 0; i < len(path); i++ {
+		// indexBits[i] == 1 if node is a right child
+		left := api.Select(indexBits[i], path[i], node)
+		right := api.Select(indexBits[i], node, path[i])
+		node = nodeSum(api, h, left, right)
+	}
+	return node
+}
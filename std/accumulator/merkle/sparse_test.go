@@ -0,0 +1,193 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const sparseDepth = 6
+
+type sparseMembershipCircuit struct {
+	Root        frontend.Variable `gnark:",public"`
+	Index, Leaf frontend.Variable
+	Path        [sparseDepth]frontend.Variable
+}
+
+func (circuit *sparseMembershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifySparseMembership(api, &h, circuit.Root, circuit.Index, circuit.Leaf, circuit.Path[:])
+	return nil
+}
+
+type sparseNonMembershipCircuit struct {
+	Root  frontend.Variable `gnark:",public"`
+	Index frontend.Variable
+	Path  [sparseDepth]frontend.Variable
+}
+
+func (circuit *sparseNonMembershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifySparseNonMembership(api, &h, circuit.Root, circuit.Index, circuit.Path[:])
+	return nil
+}
+
+type sparseUpdateCircuit struct {
+	OldRoot, NewRoot frontend.Variable `gnark:",public"`
+	Index            frontend.Variable
+	OldLeaf, NewLeaf frontend.Variable
+	Path             [sparseDepth]frontend.Variable
+}
+
+func (circuit *sparseUpdateCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifySparseUpdate(api, &h, circuit.OldRoot, circuit.NewRoot, circuit.Index, circuit.OldLeaf, circuit.NewLeaf, circuit.Path[:])
+	return nil
+}
+
+func randomLeaf(t *testing.T) []byte {
+	var leaf fr.Element
+	if _, err := leaf.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	b := leaf.Bytes()
+	return b[:]
+}
+
+func toPath(path [][]byte) (res [sparseDepth]frontend.Variable) {
+	for i := range path {
+		res[i] = path[i]
+	}
+	return
+}
+
+func TestSparseTree(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	tree, err := NewSparseTree(bn254.NewMiMC(), sparseDepth)
+	assert.NoError(err)
+	emptyRoot := tree.Root()
+
+	leaves := map[uint64][]byte{3: randomLeaf(t), 42: randomLeaf(t), 63: randomLeaf(t)}
+	for index, leaf := range leaves {
+		assert.NoError(tree.Set(index, leaf))
+	}
+
+	// the root doesn't depend on the order of the insertions
+	other, err := NewSparseTree(bn254.NewMiMC(), sparseDepth)
+	assert.NoError(err)
+	for _, index := range []uint64{63, 3, 42} {
+		assert.NoError(other.Set(index, leaves[index]))
+	}
+	assert.Equal(tree.Root(), other.Root())
+
+	// deleting the leaves gives back the empty tree
+	for index := range leaves {
+		assert.NoError(other.Set(index, make([]byte, fr.Bytes)))
+	}
+	assert.Equal(emptyRoot, other.Root())
+
+	// membership
+	path, err := tree.Prove(42)
+	assert.NoError(err)
+	assert.ProverSucceeded(&sparseMembershipCircuit{}, &sparseMembershipCircuit{
+		Root:  tree.Root(),
+		Index: 42,
+		Leaf:  leaves[42],
+		Path:  toPath(path),
+	}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&sparseMembershipCircuit{}, &sparseMembershipCircuit{
+		Root:  tree.Root(),
+		Index: 42,
+		Leaf:  leaves[3],
+		Path:  toPath(path),
+	}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&sparseMembershipCircuit{}, &sparseMembershipCircuit{
+		Root:  tree.Root(),
+		Index: 42 + (1 << sparseDepth),
+		Leaf:  leaves[42],
+		Path:  toPath(path),
+	}, test.WithCurves(ecc.BN254))
+
+	// non-membership
+	path, err = tree.Prove(7)
+	assert.NoError(err)
+	assert.ProverSucceeded(&sparseNonMembershipCircuit{}, &sparseNonMembershipCircuit{
+		Root:  tree.Root(),
+		Index: 7,
+		Path:  toPath(path),
+	}, test.WithCurves(ecc.BN254))
+	path, err = tree.Prove(3)
+	assert.NoError(err)
+	assert.ProverFailed(&sparseNonMembershipCircuit{}, &sparseNonMembershipCircuit{
+		Root:  tree.Root(),
+		Index: 3,
+		Path:  toPath(path),
+	}, test.WithCurves(ecc.BN254))
+
+	// updates of a leaf, and insertion of a new one
+	for _, index := range []uint64{3, 8} {
+		oldRoot := tree.Root()
+		oldLeaf, err := tree.Leaf(index)
+		assert.NoError(err)
+		path, err := tree.Prove(index)
+		assert.NoError(err)
+		newLeaf := randomLeaf(t)
+		assert.NoError(tree.Set(index, newLeaf))
+
+		assert.ProverSucceeded(&sparseUpdateCircuit{}, &sparseUpdateCircuit{
+			OldRoot: oldRoot,
+			NewRoot: tree.Root(),
+			Index:   index,
+			OldLeaf: oldLeaf,
+			NewLeaf: newLeaf,
+			Path:    toPath(path),
+		}, test.WithCurves(ecc.BN254))
+		assert.ProverFailed(&sparseUpdateCircuit{}, &sparseUpdateCircuit{
+			OldRoot: oldRoot,
+			NewRoot: tree.Root(),
+			Index:   index,
+			OldLeaf: oldLeaf,
+			NewLeaf: randomLeaf(t),
+			Path:    toPath(path),
+		}, test.WithCurves(ecc.BN254))
+	}
+
+	// invalid parameters
+	_, err = NewSparseTree(bn254.NewMiMC(), 65)
+	assert.Error(err)
+	assert.Error(tree.Set(1<<sparseDepth, randomLeaf(t)))
+	assert.Error(tree.Set(0, []byte{1}))
+	_, err = tree.Prove(1 << sparseDepth)
+	assert.Error(err)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+)
+
+var (
+	errInvalidDepth = errors.New("depth must be between 1 and 64")
+	errInvalidIndex = errors.New("index is out of the tree")
+	errInvalidLeaf  = errors.New("leaf size doesn't match the hash size")
+)
+
+// SparseTree is a sparse Merkle tree of fixed depth, computed natively with the hash function
+// of the gadgets (for instance gnark-crypto's mimc for std/hash/mimc). It produces the roots
+// and paths checked by SparseRoot, VerifySparseMembership, VerifySparseNonMembership and
+// VerifySparseUpdate.
+//
+// Leaves are h.Size() bytes, typically the hash of the data they commit to, and must encode
+// field elements for the hash function; empty leaves are 0. A node is h(left ∥ right).
+type SparseTree struct {
+	h     hash.Hash
+	depth int
+	nodes []map[uint64][]byte // nodes[i] holds the nodes of height i which are not empty, by position
+	empty [][]byte            // empty[i] is the root of an empty tree of height i
+}
+
+// NewSparseTree returns an empty sparse Merkle tree of the given depth, with 2^depth leaves
+func NewSparseTree(h hash.Hash, depth int) (*SparseTree, error) {
+	if depth < 1 || depth > 64 {
+		return nil, errInvalidDepth
+	}
+	t := &SparseTree{
+		h:     h,
+		depth: depth,
+		nodes: make([]map[uint64][]byte, depth+1),
+		empty: make([][]byte, depth+1),
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make(map[uint64][]byte)
+	}
+	t.empty[0] = make([]byte, h.Size())
+	for i := 1; i <= depth; i++ {
+		t.empty[i] = t.nodeSum(t.empty[i-1], t.empty[i-1])
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree, which is the length of its paths
+func (t *SparseTree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree
+func (t *SparseTree) Root() []byte {
+	return t.node(t.depth, 0)
+}
+
+// Leaf returns the leaf at index
+func (t *SparseTree) Leaf(index uint64) ([]byte, error) {
+	if err := t.checkIndex(index); err != nil {
+		return nil, err
+	}
+	return t.node(0, index), nil
+}
+
+// Set sets the leaf at index, and updates the nodes up to the root
+func (t *SparseTree) Set(index uint64, leaf []byte) error {
+	if err := t.checkIndex(index); err != nil {
+		return err
+	}
+	if len(leaf) != t.h.Size() {
+		return errInvalidLeaf
+	}
+
+	t.setNode(0, index, append([]byte(nil), leaf...))
+	for i := 1; i <= t.depth; i++ {
+		index >>= 1
+		t.setNode(i, index, t.nodeSum(t.node(i-1, 2*index), t.node(i-1, 2*index+1)))
+	}
+	return nil
+}
+
+// Prove returns the path of the leaf at index: the siblings of the nodes from the leaf
+// to the root.
+//
+// The path of a leaf doesn't depend on the leaf, so the path returned before a Set is
+// also the path of the updated leaf, as checked by VerifySparseUpdate.
+func (t *SparseTree) Prove(index uint64) ([][]byte, error) {
+	if err := t.checkIndex(index); err != nil {
+		return nil, err
+	}
+	path := make([][]byte, t.depth)
+	for i := range path {
+		path[i] = t.node(i, index^1)
+		index >>= 1
+	}
+	return path, nil
+}
+
+func (t *SparseTree) checkIndex(index uint64) error {
+	if t.depth < 64 && index>>t.depth != 0 {
+		return errInvalidIndex
+	}
+	return nil
+}
+
+// node returns the node of height i at position index
+func (t *SparseTree) node(i int, index uint64) []byte {
+	if n, ok := t.nodes[i][index]; ok {
+		return n
+	}
+	return t.empty[i]
+}
+
+func (t *SparseTree) setNode(i int, index uint64, n []byte) {
+	if bytes.Equal(n, t.empty[i]) {
+		delete(t.nodes[i], index)
+		return
+	}
+	t.nodes[i][index] = n
+}
+
+func (t *SparseTree) nodeSum(left, right []byte) []byte {
+	t.h.Reset()
+	_, _ = t.h.Write(left)
+	_, _ = t.h.Write(right)
+	return t.h.Sum(nil)
+}
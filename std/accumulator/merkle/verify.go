@@ -42,18 +42,20 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package merkle provides a ZKP-circuit function to verify merkle proofs.
+// Package merkle provides ZKP-circuit functions to verify merkle proofs, of
+// NebulousLabs-style trees (see VerifyProof) and of sparse Merkle trees (see SparseTree).
 package merkle
 
 import (
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash"
 )
 
 // leafSum returns the hash created from data inserted to form a leaf.
 // Without domain separation.
-func leafSum(api frontend.API, h mimc.MiMC, data frontend.Variable) frontend.Variable {
+func leafSum(api frontend.API, h hash.Hash, data frontend.Variable) frontend.Variable {
 
+	h.Reset()
 	h.Write(data)
 	res := h.Sum()
 
@@ -62,10 +64,10 @@ func leafSum(api frontend.API, h mimc.MiMC, data frontend.Variable) frontend.Var
 
 // nodeSum returns the hash created from data inserted to form a leaf.
 // Without domain separation.
-func nodeSum(api frontend.API, h mimc.MiMC, a, b frontend.Variable) frontend.Variable {
+func nodeSum(api frontend.API, h hash.Hash, a, b frontend.Variable) frontend.Variable {
 
+	h.Reset()
 	h.Write(a, b)
-	//res := h.Sum(a, b)
 	res := h.Sum()
 
 	return res
@@ -132,7 +134,9 @@ func GenerateProofHelper(proofSet [][]byte, proofIndex, numLeaves uint64) []int
 // true if the first element of the proof set is a leaf of data in the Merkle
 // root. False is returned if the proof set or Merkle root is nil, and if
 // 'numLeaves' equals 0.
-func VerifyProof(api frontend.API, h mimc.MiMC, merkleRoot frontend.Variable, proofSet, helper []frontend.Variable) {
+//
+// h is reset before each hash.
+func VerifyProof(api frontend.API, h hash.Hash, merkleRoot frontend.Variable, proofSet, helper []frontend.Variable) {
 
 	sum := leafSum(api, h, proofSet[0])
 
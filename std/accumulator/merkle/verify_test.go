@@ -40,7 +40,7 @@ func (circuit *merkleCircuit) Define(api frontend.API) error {
 	if err != nil {
 		return err
 	}
-	VerifyProof(api, hFunc, circuit.RootHash, circuit.Path, circuit.Helper)
+	VerifyProof(api, &hFunc, circuit.RootHash, circuit.Path, circuit.Helper)
 	return nil
 }
 
@@ -0,0 +1,39 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package twistededwards
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+)
+
+func init() {
+	hint.Register(RecoverX)
+}
+
+// RecoverX returns the x coordinate at most (p-1)/2 of the point of ordinate y on the curve
+// ax² + y² = 1 + dx²y², to decompress points in circuits. The inputs are a, d and y.
+func RecoverX(curveID ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	x := recoverX(curveID.Info().Fr.Modulus(), inputs[0], inputs[1], inputs[2])
+	if x == nil {
+		return errors.New("no point with this y coordinate")
+	}
+	results[0].Set(x)
+	return nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package twistededwards
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+)
+
+// NativePoint is a point of a twisted Edwards curve out of circuits, with coordinates in [0, p)
+type NativePoint struct {
+	X, Y *big.Int
+}
+
+// NativeCurve implements the arithmetic of a twisted Edwards curve out of circuits, with big.Int
+// coordinates. It is meant to compute witnesses and test vectors of the gadgets built on Curve,
+// not to be fast.
+type NativeCurve struct {
+	params  *CurveParams
+	modulus *big.Int
+}
+
+// NewNativeCurve returns the twisted Edwards curve id, out of circuits
+func NewNativeCurve(id twistededwards.ID) (*NativeCurve, error) {
+	params, err := GetCurveParams(id)
+	if err != nil {
+		return nil, err
+	}
+	snarkCurve, err := GetSnarkCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	return &NativeCurve{params: params, modulus: snarkCurve.Info().Fr.Modulus()}, nil
+}
+
+// Params returns the parameters of the curve
+func (c *NativeCurve) Params() *CurveParams {
+	return c.params
+}
+
+// Modulus returns the modulus p of the field of definition of the curve
+func (c *NativeCurve) Modulus() *big.Int {
+	return new(big.Int).Set(c.modulus)
+}
+
+// Base returns the base point of the curve
+func (c *NativeCurve) Base() NativePoint {
+	return NativePoint{X: new(big.Int).Set(c.params.Base[0]), Y: new(big.Int).Set(c.params.Base[1])}
+}
+
+// Identity returns the neutral element (0, 1)
+func (c *NativeCurve) Identity() NativePoint {
+	return NativePoint{X: big.NewInt(0), Y: big.NewInt(1)}
+}
+
+// IsOnCurve returns true if p1 is on the curve, with reduced coordinates
+func (c *NativeCurve) IsOnCurve(p1 NativePoint) bool {
+	if p1.X == nil || p1.Y == nil || !c.isReduced(p1.X) || !c.isReduced(p1.Y) {
+		return false
+	}
+	// ax² + y² == 1 + dx²y²
+	x2 := new(big.Int).Mul(p1.X, p1.X)
+	y2 := new(big.Int).Mul(p1.Y, p1.Y)
+	lhs := new(big.Int).Mul(c.params.A, x2)
+	lhs.Add(lhs, y2).Mod(lhs, c.modulus)
+	rhs := new(big.Int).Mul(c.params.D, x2)
+	rhs.Mul(rhs, y2).Add(rhs, big.NewInt(1)).Mod(rhs, c.modulus)
+	return lhs.Cmp(rhs) == 0
+}
+
+// Equal returns true if p1 == p2
+func (c *NativeCurve) Equal(p1, p2 NativePoint) bool {
+	return p1.X.Cmp(p2.X) == 0 && p1.Y.Cmp(p2.Y) == 0
+}
+
+// Neg returns -p1
+func (c *NativeCurve) Neg(p1 NativePoint) NativePoint {
+	x := new(big.Int).Neg(p1.X)
+	return NativePoint{X: x.Mod(x, c.modulus), Y: new(big.Int).Set(p1.Y)}
+}
+
+// Add returns p1 + p2, using the complete addition law
+// (x1y2 + y1x2) / (1 + dx1x2y1y2), (y1y2 - ax1x2) / (1 - dx1x2y1y2)
+func (c *NativeCurve) Add(p1, p2 NativePoint) NativePoint {
+	p := c.modulus
+	x1x2 := new(big.Int).Mul(p1.X, p2.X)
+	y1y2 := new(big.Int).Mul(p1.Y, p2.Y)
+	t := new(big.Int).Mul(c.params.D, x1x2)
+	t.Mul(t, y1y2).Mod(t, p)
+
+	x := new(big.Int).Mul(p1.X, p2.Y)
+	x.Add(x, new(big.Int).Mul(p1.Y, p2.X))
+	den := new(big.Int).Add(big.NewInt(1), t)
+	x.Mul(x, den.ModInverse(den.Mod(den, p), p)).Mod(x, p)
+
+	y := new(big.Int).Mul(c.params.A, x1x2)
+	y.Sub(y1y2, y)
+	den = new(big.Int).Sub(big.NewInt(1), t)
+	y.Mul(y, den.ModInverse(den.Mod(den, p), p)).Mod(y, p)
+
+	return NativePoint{X: x, Y: y}
+}
+
+// ScalarMul returns [s]p1. A negative s multiplies -p1 by -s.
+func (c *NativeCurve) ScalarMul(p1 NativePoint, s *big.Int) NativePoint {
+	if s.Sign() < 0 {
+		return c.ScalarMul(c.Neg(p1), new(big.Int).Neg(s))
+	}
+	res := c.Identity()
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = c.Add(res, res)
+		if s.Bit(i) == 1 {
+			res = c.Add(res, p1)
+		}
+	}
+	return res
+}
+
+// RecoverX returns the x coordinate at most (p-1)/2 of the point of the curve whose y
+// coordinate is y, or nil if there is none. The other point is (p-x, y).
+func (c *NativeCurve) RecoverX(y *big.Int) *big.Int {
+	return recoverX(c.modulus, c.params.A, c.params.D, y)
+}
+
+func (c *NativeCurve) isReduced(v *big.Int) bool {
+	return v.Sign() >= 0 && v.Cmp(c.modulus) < 0
+}
+
+// recoverX returns the x coordinate at most (p-1)/2 of the point of ordinate y on the curve
+// ax² + y² = 1 + dx²y² over F_p, or nil if there is none
+func recoverX(p, a, d, y *big.Int) *big.Int {
+	// x² = (1 - y²) / (a - dy²)
+	y2 := new(big.Int).Mul(y, y)
+	num := new(big.Int).Sub(big.NewInt(1), y2)
+	num.Mod(num, p)
+	den := new(big.Int).Mul(d, y2)
+	den.Sub(a, den).Mod(den, p)
+	if den.ModInverse(den, p) == nil {
+		return nil
+	}
+	x2 := num.Mul(num, den).Mod(num, p)
+	x := new(big.Int).ModSqrt(x2, p)
+	if x == nil {
+		return nil
+	}
+	if x.Cmp(new(big.Int).Rsh(p, 1)) > 0 {
+		x.Sub(p, x)
+	}
+	return x
+}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package twistededwards
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	tbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+func TestNativeCurve(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	curve, err := NewNativeCurve(twistededwards.BN254)
+	assert.NoError(err)
+	params := tbn254.GetEdwardsCurve()
+
+	s, err := rand.Int(rand.Reader, &params.Order)
+	assert.NoError(err)
+	var expected tbn254.PointAffine
+	expected.ScalarMul(&params.Base, s)
+	var x, y big.Int
+	expected.X.ToBigIntRegular(&x)
+	expected.Y.ToBigIntRegular(&y)
+
+	p := curve.ScalarMul(curve.Base(), s)
+	assert.True(curve.IsOnCurve(p))
+	assert.True(curve.Equal(p, NativePoint{X: &x, Y: &y}))
+	assert.True(curve.Equal(curve.Add(p, curve.Neg(p)), curve.Identity()))
+	assert.True(curve.Equal(curve.ScalarMul(p, big.NewInt(-1)), curve.Neg(p)))
+
+	// decompression
+	rx := curve.RecoverX(p.Y)
+	assert.True(rx.Cmp(p.X) == 0 || curve.Equal(NativePoint{X: rx, Y: p.Y}, curve.Neg(p)))
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package pedersen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// seed is the domain separator of the derivation of the generators
+const seed = "gnark/std/commitments/pedersen"
+
+// NativeCommitter computes Pedersen commitments out of circuits
+type NativeCommitter struct {
+	curve *twistededwards.NativeCurve
+	h     twistededwards.NativePoint
+	g     []twistededwards.NativePoint
+}
+
+// NewNativeCommitter returns a NativeCommitter to at most n values on the twisted Edwards curve
+// id, with the generators of the Committer returned by New(api, id, n)
+func NewNativeCommitter(id tedwards.ID, n int) (*NativeCommitter, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	h, g, err := generators(curve, n)
+	if err != nil {
+		return nil, err
+	}
+	return &NativeCommitter{curve: curve, h: h, g: g}, nil
+}
+
+// Generators returns the generators H and G_0, ..., G_{n-1}
+func (c *NativeCommitter) Generators() (twistededwards.NativePoint, []twistededwards.NativePoint) {
+	g := make([]twistededwards.NativePoint, len(c.g))
+	copy(g, c.g)
+	return c.h, g
+}
+
+// Commit returns the commitment [v]G_0 + [r]H to the value v with the randomness r
+func (c *NativeCommitter) Commit(v, r *big.Int) twistededwards.NativePoint {
+	res, err := c.CommitVector([]*big.Int{v}, r)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CommitVector returns the commitment [v_0]G_0 + ... + [v_{n-1}]G_{n-1} + [r]H to the values v
+// with the randomness r
+func (c *NativeCommitter) CommitVector(v []*big.Int, r *big.Int) (twistededwards.NativePoint, error) {
+	if len(v) == 0 || len(v) > len(c.g) {
+		return twistededwards.NativePoint{}, errors.New("committer expects between 1 and " + strconv.Itoa(len(c.g)) + " values")
+	}
+	res := c.curve.ScalarMul(c.h, r)
+	for i := range v {
+		res = c.curve.Add(res, c.curve.ScalarMul(c.g[i], v[i]))
+	}
+	return res, nil
+}
+
+// generators returns the generators H, G_0, ..., G_{n-1} of the prime order subgroup of curve.
+// The i-th generator (H being the 0-th) is [c](x, y), c being the cofactor and y the first
+// SHA256(seed || i || j) mod p, for j = 0, 1, ..., which is the ordinate of a point (x, y) of
+// the curve, x <= (p-1)/2, such that [c](x, y) isn't the neutral element.
+func generators(curve *twistededwards.NativeCurve, n int) (twistededwards.NativePoint, []twistededwards.NativePoint, error) {
+	if n < 1 {
+		return twistededwards.NativePoint{}, nil, errors.New("committer expects at least 1 value")
+	}
+	res := make([]twistededwards.NativePoint, n+1)
+	p := curve.Modulus()
+	var buf [16]byte
+	for i := range res {
+		binary.BigEndian.PutUint64(buf[:8], uint64(i))
+		for j := uint64(0); ; j++ {
+			binary.BigEndian.PutUint64(buf[8:], j)
+			h := sha256.New()
+			h.Write([]byte(seed))
+			h.Write(buf[:])
+			y := new(big.Int).SetBytes(h.Sum(nil))
+			y.Mod(y, p)
+			x := curve.RecoverX(y)
+			if x == nil {
+				continue
+			}
+			g := curve.ScalarMul(twistededwards.NativePoint{X: x, Y: y}, curve.Params().Cofactor)
+			if !curve.Equal(g, curve.Identity()) {
+				res[i] = g
+				break
+			}
+		}
+	}
+	return res[0], res[1:], nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package pedersen provides ZKP-circuit functions to compute and open Pedersen commitments on
+// the twisted Edwards curves embedded in the SNARK fields.
+//
+// The commitment to the values v_0, ..., v_{n-1} with the randomness r is
+// C = [v_0]G_0 + ... + [v_{n-1}]G_{n-1} + [r]H. The generators H, G_0, ..., G_{n-1} are derived
+// deterministically by hashing to the prime order subgroup of the curve, such that their
+// discrete logarithms with respect to each other are unknown. The commitments are perfectly
+// hiding, and additively homomorphic: the sum of the commitments to v and v' with the randomness
+// r and r' is the commitment to v+v' with the randomness r+r'.
+//
+// Under the discrete logarithm assumption, a commitment binds the values only modulo the order ℓ
+// of the subgroup: Open accepts v as well as v+ℓ. Callers committing to amounts must range-check
+// them, for instance with api.ToBinary, such that they can't wrap around ℓ.
+//
+// NativeCommitter computes the same commitments out of circuits.
+package pedersen
+
+import (
+	"strconv"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// Committer computes Pedersen commitments in a circuit
+type Committer struct {
+	curve twistededwards.Curve
+	h     twistededwards.Point
+	g     []twistededwards.Point
+}
+
+// New returns a Committer to at most n values on the twisted Edwards curve id
+func New(api frontend.API, id tedwards.ID, n int) (*Committer, error) {
+	curve, err := twistededwards.NewEdCurve(api, id)
+	if err != nil {
+		return nil, err
+	}
+	native, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	h, g, err := generators(native, n)
+	if err != nil {
+		return nil, err
+	}
+	res := &Committer{curve: curve, h: toPoint(h), g: make([]twistededwards.Point, n)}
+	for i := range g {
+		res.g[i] = toPoint(g[i])
+	}
+	return res, nil
+}
+
+// Commit returns the commitment [v]G_0 + [r]H to the value v with the randomness r
+func (c *Committer) Commit(v, r frontend.Variable) twistededwards.Point {
+	return c.CommitVector([]frontend.Variable{v}, r)
+}
+
+// Open fails if commitment isn't the commitment to the value v with the randomness r, v being
+// bound only modulo the order of the subgroup
+func (c *Committer) Open(commitment twistededwards.Point, v, r frontend.Variable) {
+	c.OpenVector(commitment, []frontend.Variable{v}, r)
+}
+
+// CommitVector returns the commitment [v_0]G_0 + ... + [v_{n-1}]G_{n-1} + [r]H to the values v
+// with the randomness r. It panics if there are more values than generators.
+func (c *Committer) CommitVector(v []frontend.Variable, r frontend.Variable) twistededwards.Point {
+	if len(v) == 0 || len(v) > len(c.g) {
+		panic("committer expects between 1 and " + strconv.Itoa(len(c.g)) + " values")
+	}
+	res := c.curve.DoubleBaseScalarMul(c.g[0], c.h, v[0], r)
+	for i := 1; i+1 < len(v); i += 2 {
+		res = c.curve.Add(res, c.curve.DoubleBaseScalarMul(c.g[i], c.g[i+1], v[i], v[i+1]))
+	}
+	if len(v)%2 == 0 {
+		res = c.curve.Add(res, c.curve.ScalarMul(c.g[len(v)-1], v[len(v)-1]))
+	}
+	return res
+}
+
+// OpenVector fails if commitment isn't the commitment to the values v with the randomness r
+func (c *Committer) OpenVector(commitment twistededwards.Point, v []frontend.Variable, r frontend.Variable) {
+	res := c.CommitVector(v, r)
+	api := c.curve.API()
+	api.AssertIsEqual(commitment.X, res.X)
+	api.AssertIsEqual(commitment.Y, res.Y)
+}
+
+func toPoint(p twistededwards.NativePoint) twistededwards.Point {
+	return twistededwards.Point{X: p.X, Y: p.Y}
+}
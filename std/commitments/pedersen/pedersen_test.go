@@ -0,0 +1,152 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package pedersen
+
+import (
+	"math/big"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+const nbValues = 3
+
+type openCircuit struct {
+	curveID    tedwards.ID
+	Commitment twistededwards.Point `gnark:",public"`
+	Value      frontend.Variable
+	Randomness frontend.Variable
+}
+
+func (circuit *openCircuit) Define(api frontend.API) error {
+	committer, err := New(api, circuit.curveID, 1)
+	if err != nil {
+		return err
+	}
+	committer.Open(circuit.Commitment, circuit.Value, circuit.Randomness)
+	return nil
+}
+
+type openVectorCircuit struct {
+	curveID    tedwards.ID
+	Commitment twistededwards.Point `gnark:",public"`
+	Values     [nbValues]frontend.Variable
+	Randomness frontend.Variable
+}
+
+func (circuit *openVectorCircuit) Define(api frontend.API) error {
+	committer, err := New(api, circuit.curveID, nbValues)
+	if err != nil {
+		return err
+	}
+	committer.OpenVector(circuit.Commitment, circuit.Values[:], circuit.Randomness)
+	return nil
+}
+
+func TestGenerators(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	c, err := NewNativeCommitter(tedwards.BN254, nbValues)
+	assert.NoError(err)
+	h, g := c.Generators()
+
+	// the generators are deterministic, and the first ones don't depend on n
+	other, err := NewNativeCommitter(tedwards.BN254, 1)
+	assert.NoError(err)
+	otherH, otherG := other.Generators()
+	assert.Equal(h, otherH)
+	assert.Equal(g[0], otherG[0])
+
+	// the generators are distinct points of the prime order subgroup
+	curve, err := twistededwards.NewNativeCurve(tedwards.BN254)
+	assert.NoError(err)
+	points := append([]twistededwards.NativePoint{h}, g...)
+	for i := range points {
+		assert.True(curve.IsOnCurve(points[i]))
+		assert.True(curve.Equal(curve.ScalarMul(points[i], curve.Params().Order), curve.Identity()))
+		for j := 0; j < i; j++ {
+			assert.False(curve.Equal(points[i], points[j]))
+		}
+	}
+
+	_, err = NewNativeCommitter(tedwards.BN254, 0)
+	assert.Error(err)
+}
+
+func TestPedersen(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, id := range []tedwards.ID{tedwards.BN254, tedwards.BLS12_381} {
+		snarkCurve, err := twistededwards.GetSnarkCurve(id)
+		assert.NoError(err)
+
+		// single value
+		c, err := NewNativeCommitter(id, 1)
+		assert.NoError(err)
+		v, r := big.NewInt(42), big.NewInt(1234567)
+		commitment := c.Commit(v, r)
+
+		witness := openCircuit{
+			Commitment: twistededwards.Point{X: commitment.X, Y: commitment.Y},
+			Value:      v,
+			Randomness: r,
+		}
+		assert.SolvingSucceeded(&openCircuit{curveID: id}, &witness, test.WithCurves(snarkCurve))
+		witness.Value = 43
+		assert.SolvingFailed(&openCircuit{curveID: id}, &witness, test.WithCurves(snarkCurve))
+
+		// vector
+		c, err = NewNativeCommitter(id, nbValues)
+		assert.NoError(err)
+		values := []*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(1 << 40)}
+		commitment, err = c.CommitVector(values, r)
+		assert.NoError(err)
+
+		vectorWitness := openVectorCircuit{
+			Commitment: twistededwards.Point{X: commitment.X, Y: commitment.Y},
+			Randomness: r,
+		}
+		for i := range values {
+			vectorWitness.Values[i] = values[i]
+		}
+		assert.SolvingSucceeded(&openVectorCircuit{curveID: id}, &vectorWitness, test.WithCurves(snarkCurve))
+		vectorWitness.Values[0], vectorWitness.Values[1] = values[1], values[0]
+		assert.SolvingFailed(&openVectorCircuit{curveID: id}, &vectorWitness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestHomomorphism(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	c, err := NewNativeCommitter(tedwards.BN254, 2)
+	assert.NoError(err)
+	curve, err := twistededwards.NewNativeCurve(tedwards.BN254)
+	assert.NoError(err)
+
+	c1, err := c.CommitVector([]*big.Int{big.NewInt(3), big.NewInt(5)}, big.NewInt(7))
+	assert.NoError(err)
+	c2, err := c.CommitVector([]*big.Int{big.NewInt(10), big.NewInt(20)}, big.NewInt(30))
+	assert.NoError(err)
+	sum, err := c.CommitVector([]*big.Int{big.NewInt(13), big.NewInt(25)}, big.NewInt(37))
+	assert.NoError(err)
+	assert.True(curve.Equal(curve.Add(c1, c2), sum))
+
+	_, err = c.CommitVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, big.NewInt(0))
+	assert.Error(err)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package elgamal provides ZKP-circuit functions to prove the correct ElGamal encryption and
+// decryption of points of the twisted Edwards curves embedded in the SNARK fields.
+//
+// The secret key is a scalar sk, and the public key is P = [sk]G, G being the base point of the
+// curve. The encryption of the point M with the randomness r is (C1, C2) = ([r]G, M + [r]P), and
+// its decryption is M = C2 - [sk]C1. The scheme is additively homomorphic: an amount m is
+// encrypted as the point [m]G, such that the sum of ciphertexts encrypts the sum of amounts.
+//
+// SecretKey, EncryptMessage and AmountPoint compute the same points out of circuits.
+package elgamal
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// PublicKey stores an ElGamal public key (to be used in gnark circuit)
+type PublicKey struct {
+	P twistededwards.Point
+}
+
+// Ciphertext stores an ElGamal ciphertext (to be used in gnark circuit)
+type Ciphertext struct {
+	C1, C2 twistededwards.Point
+}
+
+// Assign is a helper to assign a native public key
+func (pk *PublicKey) Assign(p twistededwards.NativePoint) {
+	pk.P.X, pk.P.Y = p.X, p.Y
+}
+
+// Assign is a helper to assign a native ciphertext
+func (ct *Ciphertext) Assign(c NativeCiphertext) {
+	ct.C1.X, ct.C1.Y = c.C1.X, c.C1.Y
+	ct.C2.X, ct.C2.Y = c.C2.X, c.C2.Y
+}
+
+// EncodeAmount returns the point [m]G encrypting the amount m
+func EncodeAmount(curve twistededwards.Curve, m frontend.Variable) twistededwards.Point {
+	return curve.ScalarMul(base(curve), m)
+}
+
+// Encrypt returns the encryption of msg to pk with the randomness r
+func Encrypt(curve twistededwards.Curve, pk PublicKey, msg twistededwards.Point, r frontend.Variable) Ciphertext {
+	return Ciphertext{
+		C1: curve.ScalarMul(base(curve), r),
+		C2: curve.Add(msg, curve.ScalarMul(pk.P, r)),
+	}
+}
+
+// Decrypt returns the decryption of ct with the secret key sk
+func Decrypt(curve twistededwards.Curve, sk frontend.Variable, ct Ciphertext) twistededwards.Point {
+	return curve.Add(ct.C2, curve.Neg(curve.ScalarMul(ct.C1, sk)))
+}
+
+// AssertIsEncryption fails if ct isn't the encryption of msg to pk with the randomness r
+func AssertIsEncryption(curve twistededwards.Curve, pk PublicKey, msg twistededwards.Point, r frontend.Variable, ct Ciphertext) {
+	curve.AssertIsOnCurve(pk.P)
+	curve.AssertIsOnCurve(msg)
+	assertIsEqual(curve.API(), Encrypt(curve, pk, msg, r), ct)
+}
+
+// AssertIsDecryption fails if sk isn't the secret key of pk, or if msg isn't the decryption of
+// ct with sk
+//
+// sk is constrained to be smaller than the order ℓ of the subgroup: otherwise sk+kℓ, which is
+// also a secret key of pk, would decrypt a ciphertext whose C1 has a torsion component to
+// another message.
+func AssertIsDecryption(curve twistededwards.Curve, pk PublicKey, sk frontend.Variable, ct Ciphertext, msg twistededwards.Point) {
+	api := curve.API()
+	curve.AssertIsOnCurve(ct.C1)
+	curve.AssertIsOnCurve(ct.C2)
+	api.AssertIsLessOrEqual(sk, new(big.Int).Sub(curve.Params().Order, big.NewInt(1)))
+
+	P := curve.ScalarMul(base(curve), sk)
+	api.AssertIsEqual(P.X, pk.P.X)
+	api.AssertIsEqual(P.Y, pk.P.Y)
+
+	M := Decrypt(curve, sk, ct)
+	api.AssertIsEqual(M.X, msg.X)
+	api.AssertIsEqual(M.Y, msg.Y)
+}
+
+func assertIsEqual(api frontend.API, a, b Ciphertext) {
+	api.AssertIsEqual(a.C1.X, b.C1.X)
+	api.AssertIsEqual(a.C1.Y, b.C1.Y)
+	api.AssertIsEqual(a.C2.X, b.C2.X)
+	api.AssertIsEqual(a.C2.Y, b.C2.Y)
+}
+
+func base(curve twistededwards.Curve) twistededwards.Point {
+	params := curve.Params()
+	return twistededwards.Point{X: params.Base[0], Y: params.Base[1]}
+}
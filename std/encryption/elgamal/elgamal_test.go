@@ -0,0 +1,159 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package elgamal
+
+import (
+	"math/big"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+type encryptionCircuit struct {
+	curveID    tedwards.ID
+	PublicKey  PublicKey  `gnark:",public"`
+	Ciphertext Ciphertext `gnark:",public"`
+	Amount     frontend.Variable
+	Randomness frontend.Variable
+}
+
+func (circuit *encryptionCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	msg := EncodeAmount(curve, circuit.Amount)
+	AssertIsEncryption(curve, circuit.PublicKey, msg, circuit.Randomness, circuit.Ciphertext)
+	return nil
+}
+
+type decryptionCircuit struct {
+	curveID    tedwards.ID
+	PublicKey  PublicKey            `gnark:",public"`
+	Ciphertext Ciphertext           `gnark:",public"`
+	Message    twistededwards.Point `gnark:",public"`
+	SecretKey  frontend.Variable
+}
+
+func (circuit *decryptionCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	AssertIsDecryption(curve, circuit.PublicKey, circuit.SecretKey, circuit.Ciphertext, circuit.Message)
+	return nil
+}
+
+func TestElGamal(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, id := range []tedwards.ID{tedwards.BN254, tedwards.BLS12_377} {
+		snarkCurve, err := twistededwards.GetSnarkCurve(id)
+		assert.NoError(err)
+
+		sk, err := GenerateKey(id, nil)
+		assert.NoError(err)
+		amount := big.NewInt(1000)
+		msg, err := AmountPoint(id, amount)
+		assert.NoError(err)
+		ct, r, err := EncryptMessage(id, sk.PublicKey, msg, nil)
+		assert.NoError(err)
+		assert.Equal(msg, sk.Decrypt(ct))
+
+		// encryption
+		var witness encryptionCircuit
+		witness.PublicKey.Assign(sk.PublicKey)
+		witness.Ciphertext.Assign(ct)
+		witness.Amount = amount
+		witness.Randomness = r
+		assert.SolvingSucceeded(&encryptionCircuit{curveID: id}, &witness, test.WithCurves(snarkCurve))
+		witness.Amount = 1001
+		assert.SolvingFailed(&encryptionCircuit{curveID: id}, &witness, test.WithCurves(snarkCurve))
+
+		// decryption
+		var decryption decryptionCircuit
+		decryption.PublicKey.Assign(sk.PublicKey)
+		decryption.Ciphertext.Assign(ct)
+		decryption.Message = twistededwards.Point{X: msg.X, Y: msg.Y}
+		decryption.SecretKey = sk.Scalar
+		assert.SolvingSucceeded(&decryptionCircuit{curveID: id}, &decryption, test.WithCurves(snarkCurve))
+		decryption.SecretKey = new(big.Int).Add(sk.Scalar, big.NewInt(1))
+		assert.SolvingFailed(&decryptionCircuit{curveID: id}, &decryption, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestDecryptionTorsion(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	id := tedwards.BN254
+	snarkCurve, err := twistededwards.GetSnarkCurve(id)
+	assert.NoError(err)
+	curve, err := twistededwards.NewNativeCurve(id)
+	assert.NoError(err)
+
+	sk, err := GenerateKey(id, nil)
+	assert.NoError(err)
+	msg, err := AmountPoint(id, big.NewInt(1000))
+	assert.NoError(err)
+	ct, _, err := EncryptMessage(id, sk.PublicKey, msg, nil)
+	assert.NoError(err)
+
+	// shift C1 by the point of order 2: sk+ℓ is also a secret key of pk, but [sk+ℓ]C1 ≠ [sk]C1
+	ct.C1 = curve.Add(ct.C1, twistededwards.NativePoint{X: big.NewInt(0), Y: new(big.Int).Sub(curve.Modulus(), big.NewInt(1))})
+	skShifted := new(big.Int).Add(sk.Scalar, curve.Params().Order)
+	assert.True(curve.Equal(sk.PublicKey, curve.ScalarMul(curve.Base(), skShifted)))
+
+	var decryption decryptionCircuit
+	decryption.PublicKey.Assign(sk.PublicKey)
+	decryption.Ciphertext.Assign(ct)
+	decryption.SecretKey = sk.Scalar
+	m := sk.Decrypt(ct)
+	decryption.Message = twistededwards.Point{X: m.X, Y: m.Y}
+	assert.SolvingSucceeded(&decryptionCircuit{curveID: id}, &decryption, test.WithCurves(snarkCurve))
+
+	decryption.SecretKey = skShifted
+	m = curve.Add(ct.C2, curve.Neg(curve.ScalarMul(ct.C1, skShifted)))
+	assert.False(curve.Equal(m, sk.Decrypt(ct)))
+	decryption.Message = twistededwards.Point{X: m.X, Y: m.Y}
+	assert.SolvingFailed(&decryptionCircuit{curveID: id}, &decryption, test.WithCurves(snarkCurve))
+}
+
+func TestHomomorphism(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	id := tedwards.BN254
+	curve, err := twistededwards.NewNativeCurve(id)
+	assert.NoError(err)
+	sk, err := GenerateKey(id, nil)
+	assert.NoError(err)
+
+	m1, err := AmountPoint(id, big.NewInt(30))
+	assert.NoError(err)
+	m2, err := AmountPoint(id, big.NewInt(12))
+	assert.NoError(err)
+	ct1, _, err := EncryptMessage(id, sk.PublicKey, m1, nil)
+	assert.NoError(err)
+	ct2, _, err := EncryptMessage(id, sk.PublicKey, m2, nil)
+	assert.NoError(err)
+
+	sum := NativeCiphertext{C1: curve.Add(ct1.C1, ct2.C1), C2: curve.Add(ct1.C2, ct2.C2)}
+	expected, err := AmountPoint(id, big.NewInt(42))
+	assert.NoError(err)
+	assert.True(curve.Equal(expected, sk.Decrypt(sum)))
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package elgamal
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// NativeCiphertext is an ElGamal ciphertext, out of circuits
+type NativeCiphertext struct {
+	C1, C2 twistededwards.NativePoint
+}
+
+// SecretKey is an ElGamal secret key, to decrypt ciphertexts out of circuits
+type SecretKey struct {
+	curve     *twistededwards.NativeCurve
+	Scalar    *big.Int
+	PublicKey twistededwards.NativePoint
+}
+
+// GenerateKey returns a random secret key on the curve id, reading randomness from r (if r is
+// nil, crypto/rand.Reader is used)
+func GenerateKey(id tedwards.ID, r io.Reader) (*SecretKey, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	scalar, err := randomScalar(curve, r)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretKey{
+		curve:     curve,
+		Scalar:    scalar,
+		PublicKey: curve.ScalarMul(curve.Base(), scalar),
+	}, nil
+}
+
+// Decrypt returns the decryption of ct
+func (sk *SecretKey) Decrypt(ct NativeCiphertext) twistededwards.NativePoint {
+	return sk.curve.Add(ct.C2, sk.curve.Neg(sk.curve.ScalarMul(ct.C1, sk.Scalar)))
+}
+
+// EncryptMessage returns the encryption of msg to pk on the curve id, and the randomness used,
+// reading it from r (if r is nil, crypto/rand.Reader is used)
+func EncryptMessage(id tedwards.ID, pk, msg twistededwards.NativePoint, r io.Reader) (NativeCiphertext, *big.Int, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return NativeCiphertext{}, nil, err
+	}
+	k, err := randomScalar(curve, r)
+	if err != nil {
+		return NativeCiphertext{}, nil, err
+	}
+	return NativeCiphertext{
+		C1: curve.ScalarMul(curve.Base(), k),
+		C2: curve.Add(msg, curve.ScalarMul(pk, k)),
+	}, k, nil
+}
+
+// AmountPoint returns the point [m]G encrypting the amount m on the curve id, as EncodeAmount
+func AmountPoint(id tedwards.ID, m *big.Int) (twistededwards.NativePoint, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return twistededwards.NativePoint{}, err
+	}
+	return curve.ScalarMul(curve.Base(), m), nil
+}
+
+// randomScalar returns a random scalar in [1, ℓ-1]
+func randomScalar(curve *twistededwards.NativeCurve, r io.Reader) (*big.Int, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	max := new(big.Int).Sub(curve.Params().Order, big.NewInt(1))
+	k, err := rand.Int(r, max)
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
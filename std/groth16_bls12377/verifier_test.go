@@ -81,7 +81,7 @@ func generateBls12377InnerProof(t *testing.T, vk *groth16_bls12377.VerifyingKey,
 
 	// generate the data to return for the bls12377 proof
 	var pk groth16_bls12377.ProvingKey
-	groth16_bls12377.Setup(r1cs.(*backend_bls12377.R1CS), &pk, vk)
+	groth16_bls12377.Setup(r1cs.(*backend_bls12377.R1CS), &pk, vk, backend.SetupConfig{})
 
 	_proof, err := groth16_bls12377.Prove(r1cs.(*backend_bls12377.R1CS), &pk, witness, backend.ProverConfig{})
 	if err != nil {
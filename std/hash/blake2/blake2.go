@@ -0,0 +1,158 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package blake2 provides ZKP-circuit functions to compute BLAKE2s and BLAKE2b (RFC 7693)
+// digests, with an optional key, salt and personalization.
+//
+// The hashes are built on the fixed-width integers of std/math/uints: the words are decomposed
+// in bits, such that the XORs and rotations of the compression function are cheap, and the
+// additions are checked with a decomposition of their sum. As in std/hash, the data is written
+// with Write, and hashed by Sum; unlike the field-oriented std/hash.Hash, the data and digests
+// are bytes (uints.U8), and the length of the data must be known when the circuit is compiled.
+package blake2
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// sigma is the permutation of the message words of each round
+var sigma = [10][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// Option configures a BLAKE2 hash
+type Option func(*config) error
+
+type config struct {
+	size            int
+	key             []uints.U8
+	salt            []byte
+	personalization []byte
+}
+
+// WithSize sets the size of the digest, in bytes. It defaults to the maximum size: 32 bytes for
+// BLAKE2s, and 64 bytes for BLAKE2b.
+func WithSize(size int) Option {
+	return func(cfg *config) error {
+		cfg.size = size
+		return nil
+	}
+}
+
+// WithKey sets the key of the hash, turning it into a MAC. The key may be secret.
+func WithKey(key []uints.U8) Option {
+	return func(cfg *config) error {
+		if len(key) == 0 {
+			return errors.New("empty key")
+		}
+		cfg.key = key
+		return nil
+	}
+}
+
+// WithSalt sets the salt of the hash, padded with zeros
+func WithSalt(salt []byte) Option {
+	return func(cfg *config) error {
+		cfg.salt = salt
+		return nil
+	}
+}
+
+// WithPersonalization sets the personalization of the hash, padded with zeros
+func WithPersonalization(personalization []byte) Option {
+	return func(cfg *config) error {
+		cfg.personalization = personalization
+		return nil
+	}
+}
+
+// newConfig applies opts, for a hash whose words are wordSize bytes long. The maximum size of
+// the digest and key is 8 words, and the size of the salt and personalization is 2 words.
+func newConfig(wordSize int, opts []Option) (config, error) {
+	cfg := config{size: 8 * wordSize}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.size < 1 || cfg.size > 8*wordSize {
+		return cfg, errors.New("invalid digest size")
+	}
+	if len(cfg.key) > 8*wordSize {
+		return cfg, errors.New("key too long")
+	}
+	if len(cfg.salt) > 2*wordSize {
+		return cfg, errors.New("salt too long")
+	}
+	if len(cfg.personalization) > 2*wordSize {
+		return cfg, errors.New("personalization too long")
+	}
+	return cfg, nil
+}
+
+// parameterBlock returns the first bytes of the parameter block of the sequential mode, 8 words
+// long: the sizes of the digest and key, the fanout and depth 1, then the salt and the
+// personalization in the last 4 words
+func (cfg *config) parameterBlock(wordSize int) []byte {
+	res := make([]byte, 8*wordSize)
+	res[0] = byte(cfg.size)
+	res[1] = byte(len(cfg.key))
+	res[2], res[3] = 1, 1
+	copy(res[4*wordSize:], cfg.salt)
+	copy(res[6*wordSize:], cfg.personalization)
+	return res
+}
+
+// blocks returns the data to compress, prefixed by the key padded to a block if any, and padded
+// with zeros to a multiple of blockSize (and at least one block)
+func (cfg *config) blocks(blockSize int, data []uints.U8) []uints.U8 {
+	var res []uints.U8
+	if len(cfg.key) > 0 {
+		res = append(res, cfg.key...)
+		res = append(res, zeros(blockSize-len(cfg.key))...)
+	}
+	res = append(res, data...)
+	if len(res) == 0 || len(res)%blockSize != 0 {
+		res = append(res, zeros(blockSize-len(res)%blockSize)...)
+	}
+	return res
+}
+
+// length returns the number of bytes to hash, including the key block if any
+func (cfg *config) length(blockSize int, data []uints.U8) uint64 {
+	if len(cfg.key) > 0 {
+		return uint64(blockSize + len(data))
+	}
+	return uint64(len(data))
+}
+
+func zeros(n int) []uints.U8 {
+	res := make([]uints.U8, n)
+	for i := range res {
+		res[i] = uints.NewU8(0)
+	}
+	return res
+}
@@ -0,0 +1,242 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package blake2
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+)
+
+type hasher interface {
+	Write(data ...uints.U8)
+	Sum() []uints.U8
+}
+
+type blake2Circuit struct {
+	is2b                  bool
+	size                  int
+	salt, personalization []byte
+
+	Data   []frontend.Variable
+	Key    []frontend.Variable
+	Digest []frontend.Variable `gnark:",public"`
+}
+
+func (circuit *blake2Circuit) Define(api frontend.API) error {
+	bf := uints.New(api)
+	toBytes := func(v []frontend.Variable) []uints.U8 {
+		res := make([]uints.U8, len(v))
+		for i := range v {
+			res[i] = bf.ValueOf8(v[i])
+		}
+		return res
+	}
+
+	opts := []Option{WithSize(circuit.size), WithSalt(circuit.salt), WithPersonalization(circuit.personalization)}
+	if len(circuit.Key) > 0 {
+		opts = append(opts, WithKey(toBytes(circuit.Key)))
+	}
+	var h hasher
+	var err error
+	if circuit.is2b {
+		h, err = NewBlake2b(api, opts...)
+	} else {
+		h, err = NewBlake2s(api, opts...)
+	}
+	if err != nil {
+		return err
+	}
+
+	// write the data in two parts, to check that Write appends
+	data := toBytes(circuit.Data)
+	h.Write(data[:len(data)/2]...)
+	h.Write(data[len(data)/2:]...)
+	digest := h.Sum()
+	if len(digest) != len(circuit.Digest) {
+		return errors.New("invalid digest size")
+	}
+	for i := range digest {
+		api.AssertIsEqual(bf.ToValue8(digest[i]), circuit.Digest[i])
+	}
+	return nil
+}
+
+type testCase struct {
+	is2b                  bool
+	size                  int
+	data, key             []byte
+	salt, personalization []byte
+	digest                []byte
+}
+
+// check solves the circuit of tc with a new Assert, as the compiled circuits are cached by
+// address, and the circuits of the test cases have different sizes
+func (tc *testCase) check(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := blake2Circuit{
+		is2b:            tc.is2b,
+		size:            tc.size,
+		salt:            tc.salt,
+		personalization: tc.personalization,
+		Data:            make([]frontend.Variable, len(tc.data)),
+		Key:             make([]frontend.Variable, len(tc.key)),
+		Digest:          make([]frontend.Variable, len(tc.digest)),
+	}
+	witness := blake2Circuit{
+		Data:   toVariables(tc.data),
+		Key:    toVariables(tc.key),
+		Digest: toVariables(tc.digest),
+	}
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+	witness.Digest = toVariables(tc.digest)
+	witness.Digest[0] = tc.digest[0] ^ 1
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BN254))
+}
+
+func toVariables(b []byte) []frontend.Variable {
+	res := make([]frontend.Variable, len(b))
+	for i := range b {
+		res[i] = b[i]
+	}
+	return res
+}
+
+func testData(n int) []byte {
+	res := make([]byte, n)
+	for i := range res {
+		res[i] = byte(i % 251)
+	}
+	return res
+}
+
+func mustDecode(s string) []byte {
+	res, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func TestBlake2s(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	key := testData(32)[1:11]
+	lengths := []int{0, 3, BlockSize2s, 100}
+	if testing.Short() {
+		lengths = []int{3}
+	}
+	for _, n := range lengths {
+		data := testData(n)
+
+		digest := blake2s.Sum256(data)
+		tc := testCase{size: Size2s, data: data, digest: digest[:]}
+		tc.check(t)
+
+		h, err := blake2s.New128(key)
+		assert.NoError(err)
+		h.Write(data)
+		tc = testCase{size: 16, data: data, key: key, digest: h.Sum(nil)}
+		tc.check(t)
+	}
+
+	// vectors of Python's hashlib, whose BLAKE2 supports salt and personalization
+	tc := testCase{
+		size:            Size2s,
+		data:            testData(100),
+		salt:            []byte("saltsalt"),
+		personalization: []byte("Zcash_PH"),
+		digest:          mustDecode("7c74de09b1ef7e01e919bada4f8189e303aa705eb80dec04e5f70d484f7e68cb"),
+	}
+	tc.check(t)
+	tc = testCase{
+		size:            20,
+		data:            testData(100),
+		key:             key,
+		personalization: []byte("Personal"),
+		digest:          mustDecode("7e3e485a39e62c2f3011cc2fe9965a6b7aa60f27"),
+	}
+	tc.check(t)
+}
+
+func TestBlake2b(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	key := testData(64)[1:11]
+	lengths := []int{0, 3, BlockSize2b, 200}
+	if testing.Short() {
+		lengths = []int{3}
+	}
+	for _, n := range lengths {
+		data := testData(n)
+
+		digest := blake2b.Sum512(data)
+		tc := testCase{is2b: true, size: Size2b, data: data, digest: digest[:]}
+		tc.check(t)
+
+		h, err := blake2b.New(32, key)
+		assert.NoError(err)
+		h.Write(data)
+		tc = testCase{is2b: true, size: 32, data: data, key: key, digest: h.Sum(nil)}
+		tc.check(t)
+	}
+
+	// vectors of Python's hashlib, whose BLAKE2 supports salt and personalization
+	tc := testCase{
+		is2b:            true,
+		size:            32,
+		data:            testData(100),
+		personalization: []byte("ZcashPoW"),
+		digest:          mustDecode("c2a448bbd8bdd38681ece8eaed5ff62e05391ab7704f1299bf07aa54696786f5"),
+	}
+	tc.check(t)
+	tc = testCase{
+		is2b:            true,
+		size:            Size2b,
+		data:            testData(100),
+		key:             key,
+		salt:            []byte("0123456789abcdef"),
+		personalization: []byte("Filecoin"),
+		digest:          mustDecode("57a68e92adc9dbb2e6323b37741a9771a5eeb673018c428fe2ee29315730e3faa9404857be70f56ac47073013bf93ae6830684424924a9662a954c9793b5203e"),
+	}
+	tc.check(t)
+}
+
+func TestOptions(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, opt := range []Option{
+		WithSize(0),
+		WithSize(Size2s + 1),
+		WithKey(nil),
+		WithKey(make([]uints.U8, Size2s+1)),
+		WithSalt(make([]byte, 9)),
+		WithPersonalization(make([]byte, 9)),
+	} {
+		_, err := NewBlake2s(nil, opt)
+		assert.Error(err)
+	}
+	_, err := NewBlake2b(nil, WithSize(Size2s+1), WithPersonalization(make([]byte, 16)))
+	assert.NoError(err)
+}
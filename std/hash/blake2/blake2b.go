@@ -0,0 +1,135 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package blake2
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// BlockSize2b is the block size of BLAKE2b, in bytes
+	BlockSize2b = 128
+	// Size2b is the maximum digest size of BLAKE2b, in bytes
+	Size2b = 64
+)
+
+var iv2b = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// Blake2b computes BLAKE2b digests in a circuit
+type Blake2b struct {
+	bf   *uints.BinaryField
+	cfg  config
+	data []uints.U8
+}
+
+// NewBlake2b returns a BLAKE2b hash configured by opts
+func NewBlake2b(api frontend.API, opts ...Option) (*Blake2b, error) {
+	cfg, err := newConfig(8, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Blake2b{bf: uints.New(api), cfg: cfg}, nil
+}
+
+// Write appends data to the data to hash
+func (h *Blake2b) Write(data ...uints.U8) {
+	h.data = append(h.data, data...)
+}
+
+// Reset empties the data to hash
+func (h *Blake2b) Reset() {
+	h.data = nil
+}
+
+// Sum returns the digest of the data written so far
+func (h *Blake2b) Sum() []uints.U8 {
+	var state [8]uints.U64
+	params := h.cfg.parameterBlock(8)
+	for i := range state {
+		state[i] = uints.NewU64(iv2b[i] ^ binary.LittleEndian.Uint64(params[8*i:]))
+	}
+
+	blocks := h.cfg.blocks(BlockSize2b, h.data)
+	length := h.cfg.length(BlockSize2b, h.data)
+	for offset := 0; offset < len(blocks); offset += BlockSize2b {
+		var m [16]uints.U64
+		for i := range m {
+			var b [8]uints.U8
+			copy(b[:], blocks[offset+8*i:])
+			m[i] = h.bf.FromBytes64(b)
+		}
+		last := offset+BlockSize2b == len(blocks)
+		counter := uint64(offset + BlockSize2b)
+		if last {
+			counter = length
+		}
+		h.compress(&state, &m, counter, last)
+	}
+
+	res := make([]uints.U8, 0, Size2b)
+	for i := range state {
+		b := h.bf.ToBytes64(state[i])
+		res = append(res, b[:]...)
+	}
+	return res[:h.cfg.size]
+}
+
+// compress updates the state with the block m, counter being the number of bytes hashed
+func (h *Blake2b) compress(state *[8]uints.U64, m *[16]uints.U64, counter uint64, last bool) {
+	bf := h.bf
+	var v [16]uints.U64
+	copy(v[:8], state[:])
+	for i := 0; i < 8; i++ {
+		v[8+i] = uints.NewU64(iv2b[i])
+	}
+	// the counter is 128 bits long, and its high word is 0 as the length of the data is a uint64
+	v[12] = uints.NewU64(iv2b[4] ^ counter)
+	if last {
+		v[14] = uints.NewU64(^iv2b[6])
+	}
+
+	g := func(a, b, c, d int, x, y uints.U64) {
+		v[a] = bf.Add64(v[a], v[b], x)
+		v[d] = bf.RotateLeft64(bf.Xor64(v[d], v[a]), -32)
+		v[c] = bf.Add64(v[c], v[d])
+		v[b] = bf.RotateLeft64(bf.Xor64(v[b], v[c]), -24)
+		v[a] = bf.Add64(v[a], v[b], y)
+		v[d] = bf.RotateLeft64(bf.Xor64(v[d], v[a]), -16)
+		v[c] = bf.Add64(v[c], v[d])
+		v[b] = bf.RotateLeft64(bf.Xor64(v[b], v[c]), -63)
+	}
+	for r := 0; r < 12; r++ {
+		s := &sigma[r%10]
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+
+	for i := range state {
+		state[i] = bf.Xor64(state[i], bf.Xor64(v[i], v[i+8]))
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package blake2
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+const (
+	// BlockSize2s is the block size of BLAKE2s, in bytes
+	BlockSize2s = 64
+	// Size2s is the maximum digest size of BLAKE2s, in bytes
+	Size2s = 32
+)
+
+var iv2s = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+// Blake2s computes BLAKE2s digests in a circuit
+type Blake2s struct {
+	bf   *uints.BinaryField
+	cfg  config
+	data []uints.U8
+}
+
+// NewBlake2s returns a BLAKE2s hash configured by opts
+func NewBlake2s(api frontend.API, opts ...Option) (*Blake2s, error) {
+	cfg, err := newConfig(4, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Blake2s{bf: uints.New(api), cfg: cfg}, nil
+}
+
+// Write appends data to the data to hash
+func (h *Blake2s) Write(data ...uints.U8) {
+	h.data = append(h.data, data...)
+}
+
+// Reset empties the data to hash
+func (h *Blake2s) Reset() {
+	h.data = nil
+}
+
+// Sum returns the digest of the data written so far
+func (h *Blake2s) Sum() []uints.U8 {
+	var state [8]uints.U32
+	params := h.cfg.parameterBlock(4)
+	for i := range state {
+		state[i] = uints.NewU32(iv2s[i] ^ binary.LittleEndian.Uint32(params[4*i:]))
+	}
+
+	blocks := h.cfg.blocks(BlockSize2s, h.data)
+	length := h.cfg.length(BlockSize2s, h.data)
+	for offset := 0; offset < len(blocks); offset += BlockSize2s {
+		var m [16]uints.U32
+		for i := range m {
+			var b [4]uints.U8
+			copy(b[:], blocks[offset+4*i:])
+			m[i] = h.bf.FromBytes32(b)
+		}
+		last := offset+BlockSize2s == len(blocks)
+		counter := uint64(offset + BlockSize2s)
+		if last {
+			counter = length
+		}
+		h.compress(&state, &m, counter, last)
+	}
+
+	res := make([]uints.U8, 0, Size2s)
+	for i := range state {
+		b := h.bf.ToBytes32(state[i])
+		res = append(res, b[:]...)
+	}
+	return res[:h.cfg.size]
+}
+
+// compress updates the state with the block m, counter being the number of bytes hashed
+func (h *Blake2s) compress(state *[8]uints.U32, m *[16]uints.U32, counter uint64, last bool) {
+	bf := h.bf
+	var v [16]uints.U32
+	copy(v[:8], state[:])
+	for i := 0; i < 8; i++ {
+		v[8+i] = uints.NewU32(iv2s[i])
+	}
+	v[12] = uints.NewU32(iv2s[4] ^ uint32(counter))
+	v[13] = uints.NewU32(iv2s[5] ^ uint32(counter>>32))
+	if last {
+		v[14] = uints.NewU32(^iv2s[6])
+	}
+
+	g := func(a, b, c, d int, x, y uints.U32) {
+		v[a] = bf.Add32(v[a], v[b], x)
+		v[d] = bf.RotateLeft32(bf.Xor32(v[d], v[a]), -16)
+		v[c] = bf.Add32(v[c], v[d])
+		v[b] = bf.RotateLeft32(bf.Xor32(v[b], v[c]), -12)
+		v[a] = bf.Add32(v[a], v[b], y)
+		v[d] = bf.RotateLeft32(bf.Xor32(v[d], v[a]), -8)
+		v[c] = bf.Add32(v[c], v[d])
+		v[b] = bf.RotateLeft32(bf.Xor32(v[b], v[c]), -7)
+	}
+	for r := 0; r < 10; r++ {
+		s := &sigma[r]
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+
+	for i := range state {
+		state[i] = bf.Xor32(state[i], bf.Xor32(v[i], v[i+8]))
+	}
+}
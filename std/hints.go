@@ -6,7 +6,13 @@ import (
 	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/math/bigint"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/memory"
+	"github.com/consensys/gnark/std/permutation"
+	"github.com/consensys/gnark/std/selector"
+	"github.com/consensys/gnark/std/signature/bls"
 )
 
 var registerOnce sync.Once
@@ -27,4 +33,15 @@ func registerHints() {
 	hint.Register(bits.NNAF)
 	hint.Register(bits.IthBit)
 	hint.Register(bits.NBits)
+	hint.Register(bigint.MulLimbs)
+	hint.Register(bigint.QuoRemLimbs)
+	hint.Register(bigint.SubLimbs)
+	hint.Register(bigint.Carries)
+	hint.Register(bls.SquareRoot)
+	hint.Register(twistededwards.RecoverX)
+	hint.Register(selector.MuxIndicators)
+	hint.Register(selector.MapIndicators)
+	hint.Register(permutation.SortHint)
+	hint.Register(memory.ReadHint)
+	hint.Register(memory.SortAccesses)
 }
@@ -0,0 +1,173 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bigint provides arithmetic on unsigned integers larger than the native field.
+//
+// An Int is represented by limbs of LimbBits bits, in little-endian order. Products, quotients
+// and remainders are computed out of the circuit with hints, and checked in the circuit by an
+// exact comparison of integers: the limbs of both sides are compared as polynomials in
+// 2^LimbBits, whose difference is shown to vanish with range-checked carries.
+//
+// The functions of this package expect valid Ints, whose limbs are < 2^LimbBits, and return
+// valid Ints. Ints coming from the witness must be checked with AssertIsValid first.
+package bigint
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// LimbBits is the size of the limbs of an Int
+const LimbBits = 64
+
+// Int is an unsigned integer, as limbs of LimbBits bits in little-endian order
+type Int struct {
+	Limbs []frontend.Variable
+}
+
+// NbLimbs returns the number of limbs of an Int of nbBits bits
+func NbLimbs(nbBits int) int {
+	return (nbBits + LimbBits - 1) / LimbBits
+}
+
+// Placeholder returns an Int of nbBits bits, to be used in circuit definitions
+func Placeholder(nbBits int) Int {
+	return Int{Limbs: make([]frontend.Variable, NbLimbs(nbBits))}
+}
+
+// ValueOf returns v as an Int of nbBits bits, to be used as a constant or in a witness
+// assignment. It panics if v is negative or doesn't fit in nbBits bits.
+func ValueOf(v *big.Int, nbBits int) Int {
+	if v.Sign() < 0 || v.BitLen() > nbBits {
+		panic("value doesn't fit in " + strconv.Itoa(nbBits) + " bits")
+	}
+	limbs := make([]*big.Int, NbLimbs(nbBits))
+	for i := range limbs {
+		limbs[i] = new(big.Int)
+	}
+	if err := toLimbs(v, limbs); err != nil {
+		panic(err)
+	}
+	res := Int{Limbs: make([]frontend.Variable, len(limbs))}
+	for i := range limbs {
+		res.Limbs[i] = limbs[i]
+	}
+	return res
+}
+
+// AssertIsValid checks that the limbs of a are < 2^LimbBits
+func AssertIsValid(api frontend.API, a Int) {
+	for i := range a.Limbs {
+		bits.ToBinary(api, a.Limbs[i], bits.WithNbDigits(LimbBits))
+	}
+}
+
+// AssertIsEqual fails if a != b. a and b may have a different number of limbs.
+func AssertIsEqual(api frontend.API, a, b Int) {
+	for i := 0; i < len(a.Limbs) || i < len(b.Limbs); i++ {
+		switch {
+		case i >= len(a.Limbs):
+			api.AssertIsEqual(b.Limbs[i], 0)
+		case i >= len(b.Limbs):
+			api.AssertIsEqual(a.Limbs[i], 0)
+		default:
+			api.AssertIsEqual(a.Limbs[i], b.Limbs[i])
+		}
+	}
+}
+
+// AssertIsLess fails if a >= b
+func AssertIsLess(api frontend.API, a, b Int) {
+	// d = b - a - 1 >= 0
+	d := newHintInt(api, SubLimbs, len(b.Limbs), append(append([]frontend.Variable{len(a.Limbs)}, a.Limbs...), b.Limbs...)...)
+	assertPolyEqual(api, addPoly(api, addPoly(api, intPoly(a), intPoly(d)), poly{coeffs: []frontend.Variable{1}, bound: 1}), intPoly(b))
+}
+
+// Mul returns a * b
+func Mul(api frontend.API, a, b Int) Int {
+	res := newHintInt(api, MulLimbs, len(a.Limbs)+len(b.Limbs), append(append([]frontend.Variable{len(a.Limbs)}, a.Limbs...), b.Limbs...)...)
+	assertPolyEqual(api, mulPoly(api, a, b), intPoly(res))
+	return res
+}
+
+// Mod returns a mod n. The most significant limb of n must not be 0, which bounds the
+// number of limbs of the quotient.
+func Mod(api frontend.API, a, n Int) Int {
+	return quoRem(api, a, nil, n)
+}
+
+// MulMod returns a * b mod n. The most significant limb of n must not be 0.
+func MulMod(api frontend.API, a, b, n Int) Int {
+	return quoRem(api, a, &b, n)
+}
+
+// ExpMod returns a^e mod n, for a constant exponent e > 0. The most significant limb of n
+// must not be 0.
+func ExpMod(api frontend.API, a Int, e int, n Int) Int {
+	if e <= 0 {
+		panic("exponent must be positive")
+	}
+	// square and multiply, from the most significant bit of e
+	res := Mod(api, a, n)
+	for i := big.NewInt(int64(e)).BitLen() - 2; i >= 0; i-- {
+		res = MulMod(api, res, res, n)
+		if (e>>i)&1 == 1 {
+			res = MulMod(api, res, a, n)
+		}
+	}
+	return res
+}
+
+// quoRem returns r = a * b mod n (a mod n if b is nil), checking a * b = q * n + r with r < n
+func quoRem(api frontend.API, a Int, b *Int, n Int) Int {
+	inputs := []frontend.Variable{len(a.Limbs), 0}
+	inputs = append(inputs, a.Limbs...)
+	nbQuotient := len(a.Limbs) - len(n.Limbs) + 1
+	lhs := intPoly(a)
+	if b != nil {
+		inputs[1] = len(b.Limbs)
+		inputs = append(inputs, b.Limbs...)
+		nbQuotient += len(b.Limbs)
+		lhs = mulPoly(api, a, *b)
+	}
+	inputs = append(inputs, n.Limbs...)
+	if nbQuotient < 1 {
+		nbQuotient = 1
+	}
+
+	qr := newHintInt(api, QuoRemLimbs, nbQuotient+len(n.Limbs), inputs...)
+	q := Int{Limbs: qr.Limbs[:nbQuotient]}
+	r := Int{Limbs: qr.Limbs[nbQuotient:]}
+
+	assertPolyEqual(api, lhs, addPoly(api, mulPoly(api, q, n), intPoly(r)))
+	AssertIsLess(api, r, n)
+	return r
+}
+
+// newHintInt returns the nbLimbs outputs of the hint f as a valid Int
+func newHintInt(api frontend.API, f hint.Function, nbLimbs int, inputs ...frontend.Variable) Int {
+	limbs, err := api.Compiler().NewHint(f, nbLimbs, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	res := Int{Limbs: limbs}
+	AssertIsValid(api, res)
+	return res
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bigint_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bigint"
+	"github.com/consensys/gnark/test"
+)
+
+const nbBits = 256
+
+type bigintCircuit struct {
+	A, B, N            bigint.Int
+	Prod, Rem, ProdMod bigint.Int
+	Exp3, Exp65537     bigint.Int
+}
+
+func (c *bigintCircuit) Define(api frontend.API) error {
+	for _, a := range []bigint.Int{c.A, c.B, c.N} {
+		bigint.AssertIsValid(api, a)
+	}
+	bigint.AssertIsEqual(api, bigint.Mul(api, c.A, c.B), c.Prod)
+	bigint.AssertIsEqual(api, bigint.Mod(api, c.Prod, c.N), c.ProdMod)
+	bigint.AssertIsEqual(api, bigint.Mod(api, c.A, c.N), c.Rem)
+	bigint.AssertIsEqual(api, bigint.MulMod(api, c.A, c.B, c.N), c.ProdMod)
+	bigint.AssertIsEqual(api, bigint.ExpMod(api, c.A, 3, c.N), c.Exp3)
+	bigint.AssertIsEqual(api, bigint.ExpMod(api, c.A, 65537, c.N), c.Exp65537)
+	bigint.AssertIsLess(api, c.Rem, c.N)
+	return nil
+}
+
+func newBigintCircuit() *bigintCircuit {
+	return &bigintCircuit{
+		A: bigint.Placeholder(nbBits), B: bigint.Placeholder(nbBits), N: bigint.Placeholder(nbBits / 2),
+		Prod: bigint.Placeholder(2 * nbBits), Rem: bigint.Placeholder(nbBits / 2), ProdMod: bigint.Placeholder(nbBits / 2),
+		Exp3: bigint.Placeholder(nbBits / 2), Exp65537: bigint.Placeholder(nbBits / 2),
+	}
+}
+
+func bigintAssignment(a, b, n *big.Int) *bigintCircuit {
+	prod := new(big.Int).Mul(a, b)
+	return &bigintCircuit{
+		A:        bigint.ValueOf(a, nbBits),
+		B:        bigint.ValueOf(b, nbBits),
+		N:        bigint.ValueOf(n, nbBits/2),
+		Prod:     bigint.ValueOf(prod, 2*nbBits),
+		Rem:      bigint.ValueOf(new(big.Int).Mod(a, n), nbBits/2),
+		ProdMod:  bigint.ValueOf(new(big.Int).Mod(prod, n), nbBits/2),
+		Exp3:     bigint.ValueOf(new(big.Int).Exp(a, big.NewInt(3), n), nbBits/2),
+		Exp65537: bigint.ValueOf(new(big.Int).Exp(a, big.NewInt(65537), n), nbBits/2),
+	}
+}
+
+func randomBigInt(t *testing.T, nbBits int) *big.Int {
+	v, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(nbBits)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestBigInt(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), nbBits), big.NewInt(1))
+	one := big.NewInt(1)
+	small := new(big.Int).Lsh(one, bigint.LimbBits)
+	small.Add(small, one)
+	n := randomBigInt(t, nbBits/2)
+	n.SetBit(n, nbBits/2-1, 1)
+
+	for _, v := range [][3]*big.Int{
+		{randomBigInt(t, nbBits), randomBigInt(t, nbBits), n},
+		{max, max, n},
+		{big.NewInt(0), max, n},
+		{max, one, small},
+		{randomBigInt(t, 64), randomBigInt(t, nbBits), big.NewInt(3)},
+	} {
+		assert.NoError(test.IsSolved(newBigintCircuit(), bigintAssignment(v[0], v[1], v[2]), ecc.BN254, backend.UNKNOWN))
+	}
+
+	assert.ProverSucceeded(newBigintCircuit(), bigintAssignment(randomBigInt(t, nbBits), randomBigInt(t, nbBits), n), test.WithCurves(ecc.BN254))
+
+	// wrong results
+	bad := bigintAssignment(randomBigInt(t, nbBits), randomBigInt(t, nbBits), n)
+	bad.ProdMod = bigint.ValueOf(new(big.Int).Add(n, big.NewInt(1)), nbBits/2)
+	assert.Error(test.IsSolved(newBigintCircuit(), bad, ecc.BN254, backend.UNKNOWN))
+
+	a := randomBigInt(t, nbBits)
+	bad = bigintAssignment(a, randomBigInt(t, nbBits), n)
+	bad.Exp3 = bigint.ValueOf(new(big.Int).Exp(a, big.NewInt(5), n), nbBits/2)
+	assert.Error(test.IsSolved(newBigintCircuit(), bad, ecc.BN254, backend.UNKNOWN))
+
+	// non-reduced remainder: a mod n + n is congruent but not less than n
+	a = randomBigInt(t, nbBits/2-2)
+	bad = bigintAssignment(a, one, n)
+	bad.Rem = bigint.ValueOf(new(big.Int).Add(a, n), nbBits/2+1)
+	assert.Error(test.IsSolved(newBigintCircuit(), bad, ecc.BN254, backend.UNKNOWN))
+
+	// limb out of range
+	bad = bigintAssignment(a, one, n)
+	bad.A.Limbs[0] = new(big.Int).Lsh(big.NewInt(1), bigint.LimbBits)
+	assert.Error(test.IsSolved(newBigintCircuit(), bad, ecc.BN254, backend.UNKNOWN))
+}
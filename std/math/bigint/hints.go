@@ -0,0 +1,122 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bigint
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+)
+
+func init() {
+	hint.Register(MulLimbs)
+	hint.Register(QuoRemLimbs)
+	hint.Register(SubLimbs)
+	hint.Register(Carries)
+}
+
+// MulLimbs returns the limbs of a * b. The inputs are the number of limbs of a, the limbs of a
+// and the limbs of b.
+func MulLimbs(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	la := int(inputs[0].Uint64())
+	a := fromLimbs(inputs[1 : 1+la])
+	b := fromLimbs(inputs[1+la:])
+	return toLimbs(a.Mul(a, b), results)
+}
+
+// QuoRemLimbs returns the limbs of the quotient and of the remainder of a * b by n, the
+// remainder having as many limbs as n. The inputs are the number of limbs of a and b, the
+// limbs of a, b and n. If b has no limbs, a is divided by n.
+func QuoRemLimbs(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	la, lb := int(inputs[0].Uint64()), int(inputs[1].Uint64())
+	a := fromLimbs(inputs[2 : 2+la])
+	if lb > 0 {
+		a.Mul(a, fromLimbs(inputs[2+la:2+la+lb]))
+	}
+	n := inputs[2+la+lb:]
+	m := fromLimbs(n)
+	if m.Sign() == 0 {
+		return errors.New("division by zero")
+	}
+	q, r := new(big.Int).QuoRem(a, m, new(big.Int))
+	nbQuotient := len(results) - len(n)
+	if err := toLimbs(q, results[:nbQuotient]); err != nil {
+		return err
+	}
+	return toLimbs(r, results[nbQuotient:])
+}
+
+// SubLimbs returns the limbs of b - a - 1. The inputs are the number of limbs of a, the limbs
+// of a and the limbs of b.
+func SubLimbs(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	la := int(inputs[0].Uint64())
+	a := fromLimbs(inputs[1 : 1+la])
+	b := fromLimbs(inputs[1+la:])
+	d := b.Sub(b, a)
+	d.Sub(d, big.NewInt(1))
+	if d.Sign() < 0 {
+		return errors.New("a >= b")
+	}
+	return toLimbs(d, results)
+}
+
+// Carries returns the carries of the coefficients of a polynomial in 2^LimbBits vanishing at
+// this point, plus an offset making them positive. The inputs are the size of the offset in
+// bits, and the coefficients as signed field elements.
+func Carries(curveID ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	modulus := curveID.Info().Fr.Modulus()
+	half := new(big.Int).Rsh(modulus, 1)
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(inputs[0].Uint64()))
+
+	carry := new(big.Int)
+	for k := range results {
+		d := new(big.Int).Set(inputs[1+k])
+		if d.Cmp(half) > 0 {
+			d.Sub(d, modulus)
+		}
+		carry.Add(carry, d)
+		carry.Rsh(carry, LimbBits)
+		results[k].Add(carry, offset)
+	}
+	return nil
+}
+
+// fromLimbs returns Σ limbs[i] 2^(LimbBits*i)
+func fromLimbs(limbs []*big.Int) *big.Int {
+	res := new(big.Int)
+	for i := len(limbs) - 1; i >= 0; i-- {
+		res.Lsh(res, LimbBits)
+		res.Add(res, limbs[i])
+	}
+	return res
+}
+
+// toLimbs sets limbs to the limbs of v, and errors if v doesn't fit
+func toLimbs(v *big.Int, limbs []*big.Int) error {
+	v = new(big.Int).Set(v)
+	mask := new(big.Int).Lsh(big.NewInt(1), LimbBits)
+	mask.Sub(mask, big.NewInt(1))
+	for i := range limbs {
+		limbs[i].And(v, mask)
+		v.Rsh(v, LimbBits)
+	}
+	if v.Sign() != 0 {
+		return errors.New("value doesn't fit in the limbs")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bigint
+
+import (
+	"math/big"
+	mbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// poly is an integer Σ coeffs[i] 2^(LimbBits*i), whose coefficients are < 2^bound
+type poly struct {
+	coeffs []frontend.Variable
+	bound  int
+}
+
+func intPoly(a Int) poly {
+	return poly{coeffs: a.Limbs, bound: LimbBits}
+}
+
+// mulPoly returns the product of a and b, without carries
+func mulPoly(api frontend.API, a, b Int) poly {
+	if len(a.Limbs) == 0 || len(b.Limbs) == 0 {
+		return poly{bound: 0}
+	}
+	coeffs := make([]frontend.Variable, len(a.Limbs)+len(b.Limbs)-1)
+	for i := range coeffs {
+		coeffs[i] = 0
+	}
+	for i := range a.Limbs {
+		for j := range b.Limbs {
+			coeffs[i+j] = api.Add(coeffs[i+j], api.Mul(a.Limbs[i], b.Limbs[j]))
+		}
+	}
+	n := len(a.Limbs)
+	if len(b.Limbs) < n {
+		n = len(b.Limbs)
+	}
+	// each coefficient is the sum of at most n products of limbs
+	return poly{coeffs: coeffs, bound: 2*LimbBits + mbits.Len(uint(n))}
+}
+
+// addPoly returns the sum of p and q, without carries
+func addPoly(api frontend.API, p, q poly) poly {
+	if len(p.coeffs) < len(q.coeffs) {
+		p, q = q, p
+	}
+	coeffs := make([]frontend.Variable, len(p.coeffs))
+	copy(coeffs, p.coeffs)
+	for i := range q.coeffs {
+		coeffs[i] = api.Add(coeffs[i], q.coeffs[i])
+	}
+	bound := p.bound
+	if q.bound > bound {
+		bound = q.bound
+	}
+	return poly{coeffs: coeffs, bound: bound + 1}
+}
+
+// assertPolyEqual checks that p and q are the same integer.
+//
+// The difference d = p - q is shown to vanish with carries c[k], such that
+// d[k] + c[k-1] = c[k] 2^LimbBits, and the last carry is 0. The coefficients of d are
+// in ]-2^b, 2^b[, where b is the largest bound of p and q, so the carries are in
+// ]-2^(b-LimbBits+1), 2^(b-LimbBits+1)[, which is range-checked after adding an offset.
+// The field must be large enough for these equations to hold over the integers.
+func assertPolyEqual(api frontend.API, p, q poly) {
+	n := len(p.coeffs)
+	if len(q.coeffs) > n {
+		n = len(q.coeffs)
+	}
+	if n == 0 {
+		return
+	}
+	bound := p.bound
+	if q.bound > bound {
+		bound = q.bound
+	}
+	if bound+3 > api.Compiler().Curve().Info().Fr.Bits {
+		panic("the field is too small for the integers")
+	}
+
+	d := make([]frontend.Variable, n)
+	for i := range d {
+		switch {
+		case i >= len(p.coeffs):
+			d[i] = api.Neg(q.coeffs[i])
+		case i >= len(q.coeffs):
+			d[i] = p.coeffs[i]
+		default:
+			d[i] = api.Sub(p.coeffs[i], q.coeffs[i])
+		}
+	}
+
+	carryBits := bound - LimbBits + 1
+	if carryBits < 1 {
+		carryBits = 1
+	}
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(carryBits))
+	shift := new(big.Int).Lsh(big.NewInt(1), LimbBits)
+
+	var carries []frontend.Variable
+	if n > 1 {
+		var err error
+		carries, err = api.Compiler().NewHint(Carries, n-1, append([]frontend.Variable{carryBits}, d...)...)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var carry frontend.Variable = 0
+	for k := 0; k < n-1; k++ {
+		bits.ToBinary(api, carries[k], bits.WithNbDigits(carryBits+1))
+		next := api.Sub(carries[k], offset)
+		api.AssertIsEqual(api.Add(d[k], carry), api.Mul(next, shift))
+		carry = next
+	}
+	api.AssertIsEqual(api.Add(d[n-1], carry), 0)
+}
@@ -65,7 +65,9 @@ func toBinary(api frontend.API, v frontend.Variable, opts ...BaseConversionOptio
 	}
 
 	// if a is a constant, work with the big int value.
-	if c, ok := api.Compiler().ConstantValue(v); ok {
+	// a constant which doesn't fit in cfg.NbDigits goes through the constrained decomposition
+	// below, which no assignment satisfies.
+	if c, ok := api.Compiler().ConstantValue(v); ok && c.BitLen() <= cfg.NbDigits {
 		bits := make([]frontend.Variable, cfg.NbDigits)
 		for i := 0; i < len(bits); i++ {
 			bits[i] = c.Bit(i)
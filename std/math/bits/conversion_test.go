@@ -33,6 +33,23 @@ func TestToBinary(t *testing.T) {
 	assert.ProverSucceeded(&toBinaryCircuit{}, &toBinaryCircuit{A: 5, B0: 1, B1: 0, B2: 1})
 }
 
+type toBinaryConstantCircuit struct {
+	B0 frontend.Variable
+}
+
+func (c *toBinaryConstantCircuit) Define(api frontend.API) error {
+	// 9 doesn't fit in 3 bits
+	b := bits.ToBinary(api, 9, bits.WithNbDigits(3))
+	api.AssertIsEqual(b[0], c.B0)
+
+	return nil
+}
+
+func TestToBinaryConstantOverflow(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.SolvingFailed(&toBinaryConstantCircuit{}, &toBinaryConstantCircuit{B0: 1})
+}
+
 type toTernaryCircuit struct {
 	A          frontend.Variable
 	T0, T1, T2 frontend.Variable
@@ -0,0 +1,113 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import "github.com/consensys/gnark/frontend"
+
+// U32 is an unsigned 32-bit integer, as its bits in little-endian order
+type U32 [32]frontend.Variable
+
+func toU32(w []frontend.Variable) (res U32) {
+	copy(res[:], w)
+	return
+}
+
+// NewU32 returns the constant c
+func NewU32(c uint32) U32 {
+	return toU32(newWord(uint64(c), 32))
+}
+
+// ValueOf32 returns v as a U32, and constrains v < 2^32
+func (bf *BinaryField) ValueOf32(v frontend.Variable) U32 {
+	return toU32(bf.valueOf(v, 32))
+}
+
+// ToValue32 returns the value of a
+func (bf *BinaryField) ToValue32(a U32) frontend.Variable {
+	return bf.toValue(a[:])
+}
+
+// Add32 returns a + b + in[0] + ... mod 2^32
+func (bf *BinaryField) Add32(a, b U32, in ...U32) U32 {
+	ws := [][]frontend.Variable{a[:], b[:]}
+	for i := range in {
+		ws = append(ws, in[i][:])
+	}
+	return toU32(bf.add(32, ws...))
+}
+
+// Mul32 returns a * b mod 2^32
+func (bf *BinaryField) Mul32(a, b U32) U32 {
+	return toU32(bf.mul(32, a[:], b[:]))
+}
+
+// Xor32 returns a ^ b
+func (bf *BinaryField) Xor32(a, b U32) U32 {
+	return toU32(bf.xor(a[:], b[:]))
+}
+
+// And32 returns a & b
+func (bf *BinaryField) And32(a, b U32) U32 {
+	return toU32(bf.and(a[:], b[:]))
+}
+
+// Or32 returns a | b
+func (bf *BinaryField) Or32(a, b U32) U32 {
+	return toU32(bf.or(a[:], b[:]))
+}
+
+// Not32 returns ^a
+func (bf *BinaryField) Not32(a U32) U32 {
+	return toU32(bf.not(a[:]))
+}
+
+// Lsh32 returns a << k. It panics if k < 0.
+func (bf *BinaryField) Lsh32(a U32, k int) U32 {
+	return toU32(lsh(a[:], k))
+}
+
+// Rsh32 returns a >> k. It panics if k < 0.
+func (bf *BinaryField) Rsh32(a U32, k int) U32 {
+	return toU32(rsh(a[:], k))
+}
+
+// RotateLeft32 returns a rotated left by (k mod 32) bits. To rotate right by k bits, call
+// RotateLeft32(a, -k).
+func (bf *BinaryField) RotateLeft32(a U32, k int) U32 {
+	return toU32(rotateLeft(a[:], k))
+}
+
+// AssertIsEqual32 fails if a != b
+func (bf *BinaryField) AssertIsEqual32(a, b U32) {
+	bf.assertIsEqual(a[:], b[:])
+}
+
+// ToBytes32 returns the 4 bytes of a, in little-endian order
+func (bf *BinaryField) ToBytes32(a U32) (res [4]U8) {
+	for i := range res {
+		res[i] = toU8(a[8*i : 8*i+8])
+	}
+	return
+}
+
+// FromBytes32 returns the U32 of little-endian bytes b
+func (bf *BinaryField) FromBytes32(b [4]U8) (res U32) {
+	for i := range b {
+		copy(res[8*i:8*i+8], b[i][:])
+	}
+	return
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import "github.com/consensys/gnark/frontend"
+
+// U64 is an unsigned 64-bit integer, as its bits in little-endian order
+type U64 [64]frontend.Variable
+
+func toU64(w []frontend.Variable) (res U64) {
+	copy(res[:], w)
+	return
+}
+
+// NewU64 returns the constant c
+func NewU64(c uint64) U64 {
+	return toU64(newWord(c, 64))
+}
+
+// ValueOf64 returns v as a U64, and constrains v < 2^64
+func (bf *BinaryField) ValueOf64(v frontend.Variable) U64 {
+	return toU64(bf.valueOf(v, 64))
+}
+
+// ToValue64 returns the value of a
+func (bf *BinaryField) ToValue64(a U64) frontend.Variable {
+	return bf.toValue(a[:])
+}
+
+// Add64 returns a + b + in[0] + ... mod 2^64
+func (bf *BinaryField) Add64(a, b U64, in ...U64) U64 {
+	ws := [][]frontend.Variable{a[:], b[:]}
+	for i := range in {
+		ws = append(ws, in[i][:])
+	}
+	return toU64(bf.add(64, ws...))
+}
+
+// Mul64 returns a * b mod 2^64
+func (bf *BinaryField) Mul64(a, b U64) U64 {
+	return toU64(bf.mul(64, a[:], b[:]))
+}
+
+// Xor64 returns a ^ b
+func (bf *BinaryField) Xor64(a, b U64) U64 {
+	return toU64(bf.xor(a[:], b[:]))
+}
+
+// And64 returns a & b
+func (bf *BinaryField) And64(a, b U64) U64 {
+	return toU64(bf.and(a[:], b[:]))
+}
+
+// Or64 returns a | b
+func (bf *BinaryField) Or64(a, b U64) U64 {
+	return toU64(bf.or(a[:], b[:]))
+}
+
+// Not64 returns ^a
+func (bf *BinaryField) Not64(a U64) U64 {
+	return toU64(bf.not(a[:]))
+}
+
+// Lsh64 returns a << k. It panics if k < 0.
+func (bf *BinaryField) Lsh64(a U64, k int) U64 {
+	return toU64(lsh(a[:], k))
+}
+
+// Rsh64 returns a >> k. It panics if k < 0.
+func (bf *BinaryField) Rsh64(a U64, k int) U64 {
+	return toU64(rsh(a[:], k))
+}
+
+// RotateLeft64 returns a rotated left by (k mod 64) bits. To rotate right by k bits, call
+// RotateLeft64(a, -k).
+func (bf *BinaryField) RotateLeft64(a U64, k int) U64 {
+	return toU64(rotateLeft(a[:], k))
+}
+
+// AssertIsEqual64 fails if a != b
+func (bf *BinaryField) AssertIsEqual64(a, b U64) {
+	bf.assertIsEqual(a[:], b[:])
+}
+
+// ToBytes64 returns the 8 bytes of a, in little-endian order
+func (bf *BinaryField) ToBytes64(a U64) (res [8]U8) {
+	for i := range res {
+		res[i] = toU8(a[8*i : 8*i+8])
+	}
+	return
+}
+
+// FromBytes64 returns the U64 of little-endian bytes b
+func (bf *BinaryField) FromBytes64(b [8]U8) (res U64) {
+	for i := range b {
+		copy(res[8*i:8*i+8], b[i][:])
+	}
+	return
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import "github.com/consensys/gnark/frontend"
+
+// U8 is an unsigned 8-bit integer, as its bits in little-endian order
+type U8 [8]frontend.Variable
+
+func toU8(w []frontend.Variable) (res U8) {
+	copy(res[:], w)
+	return
+}
+
+// NewU8 returns the constant c
+func NewU8(c uint8) U8 {
+	return toU8(newWord(uint64(c), 8))
+}
+
+// ValueOf8 returns v as a U8, and constrains v < 2^8
+func (bf *BinaryField) ValueOf8(v frontend.Variable) U8 {
+	return toU8(bf.valueOf(v, 8))
+}
+
+// ToValue8 returns the value of a
+func (bf *BinaryField) ToValue8(a U8) frontend.Variable {
+	return bf.toValue(a[:])
+}
+
+// Add8 returns a + b + in[0] + ... mod 2^8
+func (bf *BinaryField) Add8(a, b U8, in ...U8) U8 {
+	ws := [][]frontend.Variable{a[:], b[:]}
+	for i := range in {
+		ws = append(ws, in[i][:])
+	}
+	return toU8(bf.add(8, ws...))
+}
+
+// Mul8 returns a * b mod 2^8
+func (bf *BinaryField) Mul8(a, b U8) U8 {
+	return toU8(bf.mul(8, a[:], b[:]))
+}
+
+// Xor8 returns a ^ b
+func (bf *BinaryField) Xor8(a, b U8) U8 {
+	return toU8(bf.xor(a[:], b[:]))
+}
+
+// And8 returns a & b
+func (bf *BinaryField) And8(a, b U8) U8 {
+	return toU8(bf.and(a[:], b[:]))
+}
+
+// Or8 returns a | b
+func (bf *BinaryField) Or8(a, b U8) U8 {
+	return toU8(bf.or(a[:], b[:]))
+}
+
+// Not8 returns ^a
+func (bf *BinaryField) Not8(a U8) U8 {
+	return toU8(bf.not(a[:]))
+}
+
+// Lsh8 returns a << k. It panics if k < 0.
+func (bf *BinaryField) Lsh8(a U8, k int) U8 {
+	return toU8(lsh(a[:], k))
+}
+
+// Rsh8 returns a >> k. It panics if k < 0.
+func (bf *BinaryField) Rsh8(a U8, k int) U8 {
+	return toU8(rsh(a[:], k))
+}
+
+// RotateLeft8 returns a rotated left by (k mod 8) bits. To rotate right by k bits, call
+// RotateLeft8(a, -k).
+func (bf *BinaryField) RotateLeft8(a U8, k int) U8 {
+	return toU8(rotateLeft(a[:], k))
+}
+
+// AssertIsEqual8 fails if a != b
+func (bf *BinaryField) AssertIsEqual8(a, b U8) {
+	bf.assertIsEqual(a[:], b[:])
+}
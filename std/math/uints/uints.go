@@ -0,0 +1,157 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package uints provides fixed-width unsigned integers (U8, U32, U64) in circuits.
+//
+// An integer is represented by its bits in little-endian order, such that bitwise operations,
+// shifts and rotations are cheap, and arithmetic wraps around like Go's uint8, uint32 and uint64.
+//
+// The bits of an integer are only constrained to be 0 or 1 when it is built by this package:
+// from a constant (NewU8, NewU32, NewU64), from a frontend.Variable (ValueOf8, ValueOf32,
+// ValueOf64) or as the result of an operation. An integer in a witness should then be assigned
+// as a frontend.Variable, and converted with ValueOf.
+package uints
+
+import (
+	mbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// BinaryField implements the operations on fixed-width unsigned integers
+type BinaryField struct {
+	api frontend.API
+}
+
+// New returns a BinaryField using api
+func New(api frontend.API) *BinaryField {
+	return &BinaryField{api: api}
+}
+
+// newWord returns the n bits of c
+func newWord(c uint64, n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := 0; i < n; i++ {
+		res[i] = (c >> i) & 1
+	}
+	return res
+}
+
+// valueOf returns the n bits of v, and constrains v < 2^n
+func (bf *BinaryField) valueOf(v frontend.Variable, n int) []frontend.Variable {
+	return bits.ToBinary(bf.api, v, bits.WithNbDigits(n))
+}
+
+// toValue returns the value of the (constrained) bits w
+func (bf *BinaryField) toValue(w []frontend.Variable) frontend.Variable {
+	return bits.FromBinary(bf.api, w, bits.WithUnconstrainedInputs())
+}
+
+// add returns Σ ws mod 2^n
+func (bf *BinaryField) add(n int, ws ...[]frontend.Variable) []frontend.Variable {
+	sum := bf.toValue(ws[0])
+	for i := 1; i < len(ws); i++ {
+		sum = bf.api.Add(sum, bf.toValue(ws[i]))
+	}
+	// the sum of len(ws) integers of n bits holds on n+len(len(ws)-1) bits
+	return bits.ToBinary(bf.api, sum, bits.WithNbDigits(n+mbits.Len(uint(len(ws)-1))))[:n]
+}
+
+// mul returns a*b mod 2^n
+func (bf *BinaryField) mul(n int, a, b []frontend.Variable) []frontend.Variable {
+	prod := bf.api.Mul(bf.toValue(a), bf.toValue(b))
+	return bits.ToBinary(bf.api, prod, bits.WithNbDigits(2*n))[:n]
+}
+
+func (bf *BinaryField) xor(a, b []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(a))
+	for i := range res {
+		res[i] = bf.api.Xor(a[i], b[i])
+	}
+	return res
+}
+
+func (bf *BinaryField) and(a, b []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(a))
+	for i := range res {
+		res[i] = bf.api.And(a[i], b[i])
+	}
+	return res
+}
+
+func (bf *BinaryField) or(a, b []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(a))
+	for i := range res {
+		res[i] = bf.api.Or(a[i], b[i])
+	}
+	return res
+}
+
+func (bf *BinaryField) not(a []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(a))
+	for i := range res {
+		res[i] = bf.api.Sub(1, a[i])
+	}
+	return res
+}
+
+func (bf *BinaryField) assertIsEqual(a, b []frontend.Variable) {
+	bf.api.AssertIsEqual(bf.toValue(a), bf.toValue(b))
+}
+
+// lsh returns a << k; the shift panics if k < 0
+func lsh(a []frontend.Variable, k int) []frontend.Variable {
+	if k < 0 {
+		panic("negative shift amount")
+	}
+	res := make([]frontend.Variable, len(a))
+	for i := range res {
+		if i < k {
+			res[i] = 0
+		} else {
+			res[i] = a[i-k]
+		}
+	}
+	return res
+}
+
+// rsh returns a >> k; the shift panics if k < 0
+func rsh(a []frontend.Variable, k int) []frontend.Variable {
+	if k < 0 {
+		panic("negative shift amount")
+	}
+	res := make([]frontend.Variable, len(a))
+	for i := range res {
+		if i+k < len(a) {
+			res[i] = a[i+k]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+// rotateLeft returns a rotated left by (k mod len(a)) bits; to rotate right, call it with -k
+func rotateLeft(a []frontend.Variable, k int) []frontend.Variable {
+	n := len(a)
+	k = ((k % n) + n) % n
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[(i+k)%n] = a[i]
+	}
+	return res
+}
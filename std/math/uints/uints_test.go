@@ -0,0 +1,235 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints_test
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+)
+
+// shifts are the shift and rotation amounts tested, valid for all widths
+var shifts = [...]int{0, 1, 3, 7}
+
+type u8Circuit struct {
+	A, B                 frontend.Variable
+	Sum, Sum3, Prod      frontend.Variable
+	Xor, And, Or, Not    frontend.Variable
+	Lsh, Rsh, RotL, RotR [len(shifts)]frontend.Variable
+}
+
+func (c *u8Circuit) Define(api frontend.API) error {
+	bf := uints.New(api)
+	a, b := bf.ValueOf8(c.A), bf.ValueOf8(c.B)
+
+	api.AssertIsEqual(bf.ToValue8(bf.Add8(a, b)), c.Sum)
+	api.AssertIsEqual(bf.ToValue8(bf.Add8(a, b, a, uints.NewU8(math.MaxUint8))), c.Sum3)
+	api.AssertIsEqual(bf.ToValue8(bf.Mul8(a, b)), c.Prod)
+	api.AssertIsEqual(bf.ToValue8(bf.Xor8(a, b)), c.Xor)
+	api.AssertIsEqual(bf.ToValue8(bf.And8(a, b)), c.And)
+	api.AssertIsEqual(bf.ToValue8(bf.Or8(a, b)), c.Or)
+	api.AssertIsEqual(bf.ToValue8(bf.Not8(a)), c.Not)
+	for i, k := range shifts {
+		api.AssertIsEqual(bf.ToValue8(bf.Lsh8(a, k)), c.Lsh[i])
+		api.AssertIsEqual(bf.ToValue8(bf.Rsh8(a, k)), c.Rsh[i])
+		api.AssertIsEqual(bf.ToValue8(bf.RotateLeft8(a, k)), c.RotL[i])
+		api.AssertIsEqual(bf.ToValue8(bf.RotateLeft8(a, -k)), c.RotR[i])
+	}
+	return nil
+}
+
+func u8Assignment(a, b uint8) *u8Circuit {
+	res := &u8Circuit{
+		A: a, B: b,
+		Sum: a + b, Sum3: a + b + a + math.MaxUint8, Prod: a * b,
+		Xor: a ^ b, And: a & b, Or: a | b, Not: ^a,
+	}
+	for i, k := range shifts {
+		res.Lsh[i] = a << k
+		res.Rsh[i] = a >> k
+		res.RotL[i] = bits.RotateLeft8(a, k)
+		res.RotR[i] = bits.RotateLeft8(a, -k)
+	}
+	return res
+}
+
+func TestU8(t *testing.T) {
+	assert := test.NewAssert(t)
+	values := []uint8{0, 1, math.MaxUint8, uint8(rand.Uint64())}
+	for _, a := range values {
+		for _, b := range values {
+			assert.NoError(test.IsSolved(&u8Circuit{}, u8Assignment(a, b), ecc.BN254, backend.UNKNOWN))
+		}
+	}
+	assert.ProverSucceeded(&u8Circuit{}, u8Assignment(uint8(rand.Uint64()), uint8(rand.Uint64())), test.WithCurves(ecc.BN254))
+
+	// wrong result, and out of range input
+	bad := u8Assignment(3, 5)
+	bad.Prod = 16
+	assert.Error(test.IsSolved(&u8Circuit{}, bad, ecc.BN254, backend.UNKNOWN))
+	bad = u8Assignment(3, 5)
+	bad.A = new(big.Int).Lsh(big.NewInt(1), 8)
+	assert.Error(test.IsSolved(&u8Circuit{}, bad, ecc.BN254, backend.UNKNOWN))
+}
+
+type u32Circuit struct {
+	A, B                 frontend.Variable
+	Sum, Sum3, Prod      frontend.Variable
+	Xor, And, Or, Not    frontend.Variable
+	Lsh, Rsh, RotL, RotR [len(shifts)]frontend.Variable
+	Bytes                [4]frontend.Variable
+}
+
+func (c *u32Circuit) Define(api frontend.API) error {
+	bf := uints.New(api)
+	a, b := bf.ValueOf32(c.A), bf.ValueOf32(c.B)
+
+	api.AssertIsEqual(bf.ToValue32(bf.Add32(a, b)), c.Sum)
+	api.AssertIsEqual(bf.ToValue32(bf.Add32(a, b, a, uints.NewU32(math.MaxUint32))), c.Sum3)
+	api.AssertIsEqual(bf.ToValue32(bf.Mul32(a, b)), c.Prod)
+	api.AssertIsEqual(bf.ToValue32(bf.Xor32(a, b)), c.Xor)
+	api.AssertIsEqual(bf.ToValue32(bf.And32(a, b)), c.And)
+	api.AssertIsEqual(bf.ToValue32(bf.Or32(a, b)), c.Or)
+	api.AssertIsEqual(bf.ToValue32(bf.Not32(a)), c.Not)
+	for i, k := range shifts {
+		api.AssertIsEqual(bf.ToValue32(bf.Lsh32(a, k)), c.Lsh[i])
+		api.AssertIsEqual(bf.ToValue32(bf.Rsh32(a, k)), c.Rsh[i])
+		api.AssertIsEqual(bf.ToValue32(bf.RotateLeft32(a, k)), c.RotL[i])
+		api.AssertIsEqual(bf.ToValue32(bf.RotateLeft32(a, -k)), c.RotR[i])
+	}
+	bytes := bf.ToBytes32(a)
+	for i := range bytes {
+		api.AssertIsEqual(bf.ToValue8(bytes[i]), c.Bytes[i])
+	}
+	bf.AssertIsEqual32(bf.FromBytes32(bytes), a)
+	return nil
+}
+
+func u32Assignment(a, b uint32) *u32Circuit {
+	res := &u32Circuit{
+		A: a, B: b,
+		Sum: a + b, Sum3: a + b + a + math.MaxUint32, Prod: a * b,
+		Xor: a ^ b, And: a & b, Or: a | b, Not: ^a,
+	}
+	for i, k := range shifts {
+		res.Lsh[i] = a << k
+		res.Rsh[i] = a >> k
+		res.RotL[i] = bits.RotateLeft32(a, k)
+		res.RotR[i] = bits.RotateLeft32(a, -k)
+	}
+	for i := range res.Bytes {
+		res.Bytes[i] = uint8(a >> (8 * i))
+	}
+	return res
+}
+
+func TestU32(t *testing.T) {
+	assert := test.NewAssert(t)
+	values := []uint32{0, 1, math.MaxUint32, uint32(rand.Uint64())}
+	for _, a := range values {
+		for _, b := range values {
+			assert.NoError(test.IsSolved(&u32Circuit{}, u32Assignment(a, b), ecc.BN254, backend.UNKNOWN))
+		}
+	}
+	assert.ProverSucceeded(&u32Circuit{}, u32Assignment(uint32(rand.Uint64()), uint32(rand.Uint64())), test.WithCurves(ecc.BN254))
+
+	// wrong result, and out of range input
+	bad := u32Assignment(3, 5)
+	bad.Prod = 16
+	assert.Error(test.IsSolved(&u32Circuit{}, bad, ecc.BN254, backend.UNKNOWN))
+	bad = u32Assignment(3, 5)
+	bad.A = new(big.Int).Lsh(big.NewInt(1), 32)
+	assert.Error(test.IsSolved(&u32Circuit{}, bad, ecc.BN254, backend.UNKNOWN))
+}
+
+type u64Circuit struct {
+	A, B                 frontend.Variable
+	Sum, Sum3, Prod      frontend.Variable
+	Xor, And, Or, Not    frontend.Variable
+	Lsh, Rsh, RotL, RotR [len(shifts)]frontend.Variable
+	Bytes                [8]frontend.Variable
+}
+
+func (c *u64Circuit) Define(api frontend.API) error {
+	bf := uints.New(api)
+	a, b := bf.ValueOf64(c.A), bf.ValueOf64(c.B)
+
+	api.AssertIsEqual(bf.ToValue64(bf.Add64(a, b)), c.Sum)
+	api.AssertIsEqual(bf.ToValue64(bf.Add64(a, b, a, uints.NewU64(math.MaxUint64))), c.Sum3)
+	api.AssertIsEqual(bf.ToValue64(bf.Mul64(a, b)), c.Prod)
+	api.AssertIsEqual(bf.ToValue64(bf.Xor64(a, b)), c.Xor)
+	api.AssertIsEqual(bf.ToValue64(bf.And64(a, b)), c.And)
+	api.AssertIsEqual(bf.ToValue64(bf.Or64(a, b)), c.Or)
+	api.AssertIsEqual(bf.ToValue64(bf.Not64(a)), c.Not)
+	for i, k := range shifts {
+		api.AssertIsEqual(bf.ToValue64(bf.Lsh64(a, k)), c.Lsh[i])
+		api.AssertIsEqual(bf.ToValue64(bf.Rsh64(a, k)), c.Rsh[i])
+		api.AssertIsEqual(bf.ToValue64(bf.RotateLeft64(a, k)), c.RotL[i])
+		api.AssertIsEqual(bf.ToValue64(bf.RotateLeft64(a, -k)), c.RotR[i])
+	}
+	bytes := bf.ToBytes64(a)
+	for i := range bytes {
+		api.AssertIsEqual(bf.ToValue8(bytes[i]), c.Bytes[i])
+	}
+	bf.AssertIsEqual64(bf.FromBytes64(bytes), a)
+	return nil
+}
+
+func u64Assignment(a, b uint64) *u64Circuit {
+	res := &u64Circuit{
+		A: a, B: b,
+		Sum: a + b, Sum3: a + b + a + math.MaxUint64, Prod: a * b,
+		Xor: a ^ b, And: a & b, Or: a | b, Not: ^a,
+	}
+	for i, k := range shifts {
+		res.Lsh[i] = a << k
+		res.Rsh[i] = a >> k
+		res.RotL[i] = bits.RotateLeft64(a, k)
+		res.RotR[i] = bits.RotateLeft64(a, -k)
+	}
+	for i := range res.Bytes {
+		res.Bytes[i] = uint8(a >> (8 * i))
+	}
+	return res
+}
+
+func TestU64(t *testing.T) {
+	assert := test.NewAssert(t)
+	values := []uint64{0, 1, math.MaxUint64, rand.Uint64()}
+	for _, a := range values {
+		for _, b := range values {
+			assert.NoError(test.IsSolved(&u64Circuit{}, u64Assignment(a, b), ecc.BN254, backend.UNKNOWN))
+		}
+	}
+	assert.ProverSucceeded(&u64Circuit{}, u64Assignment(rand.Uint64(), rand.Uint64()), test.WithCurves(ecc.BN254))
+
+	// wrong result, and out of range input
+	bad := u64Assignment(3, 5)
+	bad.Prod = 16
+	assert.Error(test.IsSolved(&u64Circuit{}, bad, ecc.BN254, backend.UNKNOWN))
+	bad = u64Assignment(3, 5)
+	bad.A = new(big.Int).Lsh(big.NewInt(1), 64)
+	assert.Error(test.IsSolved(&u64Circuit{}, bad, ecc.BN254, backend.UNKNOWN))
+}
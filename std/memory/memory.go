@@ -0,0 +1,195 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package memory provides a read-write memory with secret addresses for ZKP circuits, checked
+// offline.
+//
+// Each access is recorded in a log of tuples (address, time, value, isWrite), the time being the
+// position of the access in the log. The values read are computed out of the circuit, and left
+// unconstrained until Finalize checks the whole log at once:
+//   - a copy of the log sorted by address, then time, is computed out of the circuit, and proven
+//     to be a permutation of the log with a grand-product argument (std/permutation);
+//   - in the sorted copy, consecutive accesses to the same address have increasing times, each
+//     read returns the value of the previous access, and the first access to an address is a
+//     write (the initial values of the memory being written first).
+//
+// An access then costs a few constraints and a range check on the number of bits of the length
+// of the log, instead of a selection over the whole memory. The values read are computed by a
+// hint taking the addresses and values of all the previous writes as inputs, though: the hints
+// of r reads take O(r·(size+writes)) inputs, which the compiled circuit stores and the solver
+// goes through.
+package memory
+
+import (
+	"errors"
+	"math/big"
+	mbits "math/bits"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/permutation"
+)
+
+func init() {
+	hint.Register(ReadHint)
+	hint.Register(SortAccesses)
+}
+
+// nbColumns is the number of elements of an access: address, time, value and isWrite
+const nbColumns = 4
+
+// Memory is a read-write memory of fixed size, whose accesses are checked by Finalize
+type Memory struct {
+	api       frontend.API
+	h         hash.Hash
+	size      int
+	log       [][]frontend.Variable
+	writes    []frontend.Variable // addresses and values of the writes, in time order
+	finalized bool
+
+	// hints computing the values read and the sorted log, replaced by tests with dishonest ones
+	readHint, sortHint hint.Function
+}
+
+// New returns a Memory whose addresses are [0, len(initial)), and the initial values initial.
+// h is used to derive the challenges of the permutation argument.
+func New(api frontend.API, h hash.Hash, initial []frontend.Variable) *Memory {
+	if len(initial) == 0 {
+		panic("memory must have at least one address")
+	}
+	m := &Memory{api: api, h: h, size: len(initial), readHint: ReadHint, sortHint: SortAccesses}
+	for i := range initial {
+		m.Write(i, initial[i])
+	}
+	return m
+}
+
+// Read returns the value at addr. The value is only constrained by Finalize, which fails if
+// addr isn't in the memory.
+func (m *Memory) Read(addr frontend.Variable) frontend.Variable {
+	m.assertNotFinalized()
+	inputs := append([]frontend.Variable{addr}, m.writes...)
+	v, err := m.api.Compiler().NewHint(m.readHint, 1, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	m.log = append(m.log, []frontend.Variable{addr, len(m.log), v[0], 0})
+	return v[0]
+}
+
+// Write sets the value at addr to v. Finalize fails if addr isn't in the memory.
+func (m *Memory) Write(addr, v frontend.Variable) {
+	m.assertNotFinalized()
+	m.log = append(m.log, []frontend.Variable{addr, len(m.log), v, 1})
+	m.writes = append(m.writes, addr, v)
+}
+
+// Finalize checks the consistency of the accesses to the memory. It must be called once, after
+// the last access: the values read are unconstrained otherwise.
+func (m *Memory) Finalize() error {
+	m.assertNotFinalized()
+	m.finalized = true
+	api := m.api
+
+	// the log sorted by address, then time
+	inputs := make([]frontend.Variable, 0, len(m.log)*nbColumns)
+	for i := range m.log {
+		inputs = append(inputs, m.log[i]...)
+	}
+	outputs, err := api.Compiler().NewHint(m.sortHint, len(inputs), inputs...)
+	if err != nil {
+		return err
+	}
+	sorted := make([][]frontend.Variable, len(m.log))
+	for i := range sorted {
+		sorted[i] = outputs[i*nbColumns : (i+1)*nbColumns]
+	}
+	if err := permutation.AssertIsRowPermutation(api, m.h, m.log, sorted); err != nil {
+		return err
+	}
+
+	// the addresses go from 0 to size-1 by steps of 0 or 1: all the addresses of the memory
+	// appear, as they are initialized
+	api.AssertIsEqual(sorted[0][0], 0)
+	api.AssertIsEqual(sorted[len(sorted)-1][0], m.size-1)
+	api.AssertIsEqual(sorted[0][3], 1)
+	nbBits := mbits.Len(uint(len(m.log)))
+	for i := 1; i < len(sorted); i++ {
+		addr, time, value, isWrite := sorted[i][0], sorted[i][1], sorted[i][2], sorted[i][3]
+		step := api.Sub(addr, sorted[i-1][0])
+		api.AssertIsBoolean(step)
+		sameAddr := api.Sub(1, step)
+
+		// a new address starts with a write
+		api.AssertIsEqual(api.Mul(step, api.Sub(1, isWrite)), 0)
+
+		// same address: time > previous time, and a read returns the previous value
+		dt := api.Sub(time, sorted[i-1][1], 1)
+		bits.ToBinary(api, api.Mul(sameAddr, dt), bits.WithNbDigits(nbBits))
+		isRead := api.Sub(1, isWrite)
+		api.AssertIsEqual(api.Mul(api.Mul(sameAddr, isRead), api.Sub(value, sorted[i-1][2])), 0)
+	}
+	return nil
+}
+
+func (m *Memory) assertNotFinalized() {
+	if m.finalized {
+		panic("memory already finalized")
+	}
+}
+
+// ReadHint returns the value of the last write to the address inputs[0], the following inputs
+// being the addresses and values of the writes, in time order. It returns 0 if the address was
+// never written, Finalize failing then.
+func ReadHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	if len(inputs)%2 != 1 {
+		return errors.New("expected an address and pairs of address and value")
+	}
+	results[0].SetUint64(0)
+	for i := 1; i < len(inputs); i += 2 {
+		if inputs[i].Cmp(inputs[0]) == 0 {
+			results[0].Set(inputs[i+1])
+		}
+	}
+	return nil
+}
+
+// SortAccesses returns the accesses (address, time, value, isWrite) of the inputs, sorted by
+// address, then time
+func SortAccesses(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	if len(inputs)%nbColumns != 0 || len(results) != len(inputs) {
+		return errors.New("invalid number of inputs or results")
+	}
+	rows := make([][]*big.Int, len(inputs)/nbColumns)
+	for i := range rows {
+		rows[i] = inputs[i*nbColumns : (i+1)*nbColumns]
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if c := rows[i][0].Cmp(rows[j][0]); c != 0 {
+			return c < 0
+		}
+		return rows[i][1].Cmp(rows[j][1]) < 0
+	})
+	for i := range rows {
+		for j := 0; j < nbColumns; j++ {
+			results[i*nbColumns+j].Set(rows[i][j])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package memory
+
+import (
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	memorySize = 4
+	nbAccesses = 5
+)
+
+// memoryCircuit writes Values[i] at WriteAddrs[i] and reads ReadAddrs[i], expecting Expected[i]
+type memoryCircuit struct {
+	Initial    [memorySize]frontend.Variable
+	WriteAddrs [nbAccesses]frontend.Variable
+	Values     [nbAccesses]frontend.Variable
+	ReadAddrs  [nbAccesses]frontend.Variable
+	Expected   [nbAccesses]frontend.Variable
+}
+
+func (circuit *memoryCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	m := New(api, &h, circuit.Initial[:])
+	for i := 0; i < nbAccesses; i++ {
+		m.Write(circuit.WriteAddrs[i], circuit.Values[i])
+		api.AssertIsEqual(m.Read(circuit.ReadAddrs[i]), circuit.Expected[i])
+	}
+	return m.Finalize()
+}
+
+// readCircuit reads the value at Addr, and ignores it
+type readCircuit struct {
+	Initial [memorySize]frontend.Variable
+	Addr    frontend.Variable
+}
+
+func (circuit *readCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	m := New(api, &h, circuit.Initial[:])
+	m.Read(circuit.Addr)
+	return m.Finalize()
+}
+
+func TestMemory(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	valid := memoryCircuit{
+		Initial:    [memorySize]frontend.Variable{10, 11, 12, 13},
+		WriteAddrs: [nbAccesses]frontend.Variable{2, 0, 2, 3, 1},
+		Values:     [nbAccesses]frontend.Variable{20, 21, 22, 23, 24},
+		ReadAddrs:  [nbAccesses]frontend.Variable{2, 1, 0, 2, 3},
+		Expected:   [nbAccesses]frontend.Variable{20, 11, 21, 22, 23},
+	}
+	assert.ProverSucceeded(&memoryCircuit{}, &valid, test.WithCurves(ecc.BN254))
+
+	// reading a stale value
+	invalid := valid
+	invalid.Expected = [nbAccesses]frontend.Variable{20, 11, 21, 20, 23}
+	assert.SolvingFailed(&memoryCircuit{}, &invalid, test.WithCurves(ecc.BN254))
+
+	// writing out of the memory
+	invalid = valid
+	invalid.WriteAddrs = [nbAccesses]frontend.Variable{2, 0, 2, 3, memorySize}
+	assert.SolvingFailed(&memoryCircuit{}, &invalid, test.WithCurves(ecc.BN254))
+
+	// reading out of the memory
+	assert.SolvingSucceeded(&readCircuit{}, &readCircuit{Initial: valid.Initial, Addr: memorySize - 1}, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(&readCircuit{}, &readCircuit{Initial: valid.Initial, Addr: memorySize}, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(&readCircuit{}, &readCircuit{Initial: valid.Initial, Addr: -1}, test.WithCurves(ecc.BN254))
+}
+
+func init() {
+	hint.Register(readFirstHint)
+	hint.Register(sortByValueHint)
+}
+
+// staleCircuit writes Value at Addr, and reads Addr with a dishonest hint returning the initial
+// value. sortByValue replaces the sorting hint with a dishonest one, ordering the accesses to an
+// address by value such that the stale read follows the initial write. finalize is false to show
+// that the values read are unconstrained until Finalize.
+type staleCircuit struct {
+	sortByValue, finalize bool
+	Initial               [memorySize]frontend.Variable
+	Addr, Value, Expected frontend.Variable
+}
+
+func (circuit *staleCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	m := New(api, &h, circuit.Initial[:])
+	m.readHint = readFirstHint
+	if circuit.sortByValue {
+		m.sortHint = sortByValueHint
+	}
+	m.Write(circuit.Addr, circuit.Value)
+	api.AssertIsEqual(m.Read(circuit.Addr), circuit.Expected)
+	if !circuit.finalize {
+		return nil
+	}
+	return m.Finalize()
+}
+
+func TestDishonestHints(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	witness := staleCircuit{
+		Initial:  [memorySize]frontend.Variable{10, 11, 12, 13},
+		Addr:     2,
+		Value:    20,
+		Expected: 12,
+	}
+	assert.SolvingSucceeded(&staleCircuit{}, &witness, test.WithCurves(ecc.BN254), test.WithCompileOpts(frontend.IgnoreUnconstrainedInputs()))
+
+	// the stale read follows the write in the sorted log
+	assert.SolvingFailed(&staleCircuit{finalize: true}, &witness, test.WithCurves(ecc.BN254))
+
+	// the stale read follows the initial write, and the write comes after the read, out of order
+	assert.SolvingFailed(&staleCircuit{finalize: true, sortByValue: true}, &witness, test.WithCurves(ecc.BN254))
+}
+
+// readFirstHint returns the value of the first write to the address inputs[0], instead of the
+// last one as ReadHint does
+func readFirstHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	for i := len(inputs) - 2; i >= 1; i -= 2 {
+		if inputs[i].Cmp(inputs[0]) == 0 {
+			results[0].Set(inputs[i+1])
+		}
+	}
+	return nil
+}
+
+// sortByValueHint returns the accesses sorted by address, then value, then time
+func sortByValueHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	rows := make([][]*big.Int, len(inputs)/nbColumns)
+	for i := range rows {
+		rows[i] = inputs[i*nbColumns : (i+1)*nbColumns]
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, k := range []int{0, 2, 1} {
+			if c := rows[i][k].Cmp(rows[j][k]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	for i := range rows {
+		for j := 0; j < nbColumns; j++ {
+			results[i*nbColumns+j].Set(rows[i][j])
+		}
+	}
+	return nil
+}
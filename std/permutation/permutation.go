@@ -0,0 +1,117 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package permutation provides ZKP-circuit functions to prove that an array is a permutation of
+// another one, or its sorted version.
+//
+// The permutation check is a randomized grand-product argument: b is a permutation of a if and
+// only if the polynomials Π(X - a_i) and Π(X - b_i) are equal, which is checked at a challenge γ
+// derived from a and b with Fiat-Shamir (std/fiat-shamir). By the Schwartz-Zippel lemma, a prover
+// succeeds with distinct polynomials with probability at most n/|F|, n being the length of the
+// arrays, provided the hash is collision resistant in the circuit's field (e.g. MiMC). This costs
+// 2n multiplications, instead of the O(n log² n) comparators of a sorting network.
+//
+// Sortedness is checked with bounded comparisons: the differences of consecutive elements are
+// range checked on a given number of bits, instead of comparing full field elements with api.Cmp.
+package permutation
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash"
+)
+
+var errLengthMismatch = errors.New("arrays must have the same length")
+
+// AssertIsPermutation fails if b isn't a permutation of a. h is used to derive the challenge of
+// the grand-product argument.
+func AssertIsPermutation(api frontend.API, h hash.Hash, a, b []frontend.Variable) error {
+	if len(a) != len(b) {
+		return errLengthMismatch
+	}
+	t := fiatshamir.NewTranscript(api, h, "gamma")
+	if err := t.Bind("gamma", a); err != nil {
+		return err
+	}
+	if err := t.Bind("gamma", b); err != nil {
+		return err
+	}
+	gamma, err := t.ComputeChallenge("gamma")
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(grandProduct(api, gamma, a), grandProduct(api, gamma, b))
+	return nil
+}
+
+// AssertIsRowPermutation fails if the rows of b aren't a permutation of the rows of a, all rows
+// having the same length. h is used to derive the challenges of the argument: the rows are
+// compressed with a first challenge β as r_0 + β r_1 + β² r_2 + ..., and the compressed rows are
+// checked with a grand-product argument.
+func AssertIsRowPermutation(api frontend.API, h hash.Hash, a, b [][]frontend.Variable) error {
+	if len(a) != len(b) {
+		return errLengthMismatch
+	}
+	if len(a) == 0 {
+		return nil
+	}
+	width := len(a[0])
+	t := fiatshamir.NewTranscript(api, h, "beta", "gamma")
+	for _, rows := range [][][]frontend.Variable{a, b} {
+		for i := range rows {
+			if len(rows[i]) != width {
+				return errors.New("rows must have the same length")
+			}
+			if err := t.Bind("beta", rows[i]); err != nil {
+				return err
+			}
+		}
+	}
+	beta, err := t.ComputeChallenge("beta")
+	if err != nil {
+		return err
+	}
+	gamma, err := t.ComputeChallenge("gamma")
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(grandProduct(api, gamma, compress(api, beta, a)), grandProduct(api, gamma, compress(api, beta, b)))
+	return nil
+}
+
+// grandProduct returns Π (gamma - a_i)
+func grandProduct(api frontend.API, gamma frontend.Variable, a []frontend.Variable) frontend.Variable {
+	res := frontend.Variable(1)
+	for i := range a {
+		res = api.Mul(res, api.Sub(gamma, a[i]))
+	}
+	return res
+}
+
+// compress returns the rows evaluated at beta, r_0 + beta r_1 + beta² r_2 + ...
+func compress(api frontend.API, beta frontend.Variable, rows [][]frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(rows))
+	for i := range rows {
+		// Horner's rule
+		acc := rows[i][len(rows[i])-1]
+		for j := len(rows[i]) - 2; j >= 0; j-- {
+			acc = api.Add(api.Mul(acc, beta), rows[i][j])
+		}
+		res[i] = acc
+	}
+	return res
+}
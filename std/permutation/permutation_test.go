@@ -0,0 +1,121 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package permutation
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const size = 6
+
+type permutationCircuit struct {
+	A, B [size]frontend.Variable
+}
+
+func (circuit *permutationCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return AssertIsPermutation(api, &h, circuit.A[:], circuit.B[:])
+}
+
+type rowPermutationCircuit struct {
+	A, B [size][2]frontend.Variable
+}
+
+func (circuit *rowPermutationCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	a := make([][]frontend.Variable, size)
+	b := make([][]frontend.Variable, size)
+	for i := range a {
+		a[i], b[i] = circuit.A[i][:], circuit.B[i][:]
+	}
+	return AssertIsRowPermutation(api, &h, a, b)
+}
+
+type sortedCircuit struct {
+	A [size]frontend.Variable
+}
+
+func (circuit *sortedCircuit) Define(api frontend.API) error {
+	AssertIsSorted(api, circuit.A[:], 16)
+	return nil
+}
+
+type sortCircuit struct {
+	A, Sorted [size]frontend.Variable
+}
+
+func (circuit *sortCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	sorted, err := Sort(api, &h, circuit.A[:], 16)
+	if err != nil {
+		return err
+	}
+	for i := range sorted {
+		api.AssertIsEqual(sorted[i], circuit.Sorted[i])
+	}
+	return nil
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	a := [size]frontend.Variable{5, 3, 3, 9, 0, 1}
+	assert.ProverSucceeded(&permutationCircuit{}, &permutationCircuit{A: a, B: [size]frontend.Variable{3, 9, 1, 0, 3, 5}}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&permutationCircuit{}, &permutationCircuit{A: a, B: a}, test.WithCurves(ecc.BN254))
+	// multiplicities matter
+	assert.ProverFailed(&permutationCircuit{}, &permutationCircuit{A: a, B: [size]frontend.Variable{3, 9, 1, 0, 5, 5}}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&permutationCircuit{}, &permutationCircuit{A: a, B: [size]frontend.Variable{3, 9, 1, 0, 3, 6}}, test.WithCurves(ecc.BN254))
+}
+
+func TestRowPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	a := [size][2]frontend.Variable{{1, 10}, {2, 20}, {3, 30}, {4, 40}, {5, 50}, {6, 60}}
+	b := [size][2]frontend.Variable{{4, 40}, {6, 60}, {1, 10}, {3, 30}, {5, 50}, {2, 20}}
+	assert.ProverSucceeded(&rowPermutationCircuit{}, &rowPermutationCircuit{A: a, B: b}, test.WithCurves(ecc.BN254))
+	// the columns are permuted independently
+	c := [size][2]frontend.Variable{{4, 10}, {6, 20}, {1, 30}, {3, 40}, {5, 50}, {2, 60}}
+	assert.ProverFailed(&rowPermutationCircuit{}, &rowPermutationCircuit{A: a, B: c}, test.WithCurves(ecc.BN254))
+}
+
+func TestSort(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	sorted := [size]frontend.Variable{0, 1, 3, 3, 5, 9}
+	assert.ProverSucceeded(&sortedCircuit{}, &sortedCircuit{A: sorted}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&sortedCircuit{}, &sortedCircuit{A: [size]frontend.Variable{0, 1, 3, 5, 3, 9}}, test.WithCurves(ecc.BN254))
+	// out of range elements
+	assert.ProverFailed(&sortedCircuit{}, &sortedCircuit{A: [size]frontend.Variable{0, 1, 3, 3, 5, 1 << 16}}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&sortedCircuit{}, &sortedCircuit{A: [size]frontend.Variable{-1, 1, 3, 3, 5, 9}}, test.WithCurves(ecc.BN254))
+
+	a := [size]frontend.Variable{5, 3, 3, 9, 0, 1}
+	assert.ProverSucceeded(&sortCircuit{}, &sortCircuit{A: a, Sorted: sorted}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&sortCircuit{}, &sortCircuit{A: a, Sorted: [size]frontend.Variable{0, 1, 3, 5, 3, 9}}, test.WithCurves(ecc.BN254))
+}
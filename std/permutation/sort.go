@@ -0,0 +1,91 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package permutation
+
+import (
+	"math/big"
+	mbits "math/bits"
+	"sort"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+func init() {
+	hint.Register(SortHint)
+}
+
+// AssertIsSorted fails if the elements of a aren't in [0, 2^nbBits), or aren't in ascending
+// order. It range checks the first and last elements, and the differences of consecutive
+// elements, on nbBits bits: as long as len(a) * 2^nbBits doesn't overflow the field, the
+// elements are then a non-decreasing sequence of integers in [a_0, a_{n-1}].
+func AssertIsSorted(api frontend.API, a []frontend.Variable, nbBits int) {
+	if len(a) == 0 {
+		return
+	}
+	if nbBits+mbits.Len(uint(len(a))) >= api.Compiler().Curve().Info().Fr.Bits {
+		panic("can't sort " + strconv.Itoa(len(a)) + " elements of " + strconv.Itoa(nbBits) + " bits in this field")
+	}
+	rangeCheck(api, a[0], nbBits)
+	for i := 1; i < len(a); i++ {
+		rangeCheck(api, api.Sub(a[i], a[i-1]), nbBits)
+	}
+	if len(a) > 1 {
+		rangeCheck(api, a[len(a)-1], nbBits)
+	}
+}
+
+// AssertIsSortedPermutation fails if sorted isn't the sorted version of a, the elements of a
+// being in [0, 2^nbBits). h is used to derive the challenge of the permutation argument.
+func AssertIsSortedPermutation(api frontend.API, h hash.Hash, a, sorted []frontend.Variable, nbBits int) error {
+	AssertIsSorted(api, sorted, nbBits)
+	return AssertIsPermutation(api, h, a, sorted)
+}
+
+// Sort returns the elements of a in ascending order, the elements of a being in [0, 2^nbBits).
+// The result is computed out of the circuit, and checked with AssertIsSortedPermutation.
+func Sort(api frontend.API, h hash.Hash, a []frontend.Variable, nbBits int) ([]frontend.Variable, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	sorted, err := api.Compiler().NewHint(SortHint, len(a), a...)
+	if err != nil {
+		return nil, err
+	}
+	if err := AssertIsSortedPermutation(api, h, a, sorted, nbBits); err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
+
+// SortHint sets results to the inputs in ascending order
+func SortHint(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	sorted := make([]*big.Int, len(inputs))
+	copy(sorted, inputs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	for i := range results {
+		results[i].Set(sorted[i])
+	}
+	return nil
+}
+
+func rangeCheck(api frontend.API, v frontend.Variable, nbBits int) {
+	bits.ToBinary(api, v, bits.WithNbDigits(nbBits))
+}
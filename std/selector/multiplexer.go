@@ -0,0 +1,134 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package selector provides ZKP-circuit functions to access arrays at secret indices.
+//
+// The gadgets build an indicator vector out of the circuit with a hint, and constrain it in the
+// circuit: a boolean b_i for each entry, with b_i = 0 unless the entry is selected, and exactly
+// one b_i equal to 1. Selecting, reading or writing an entry of an array of n elements then costs
+// a few constraints per element (about 4n with the R1CS builder, and 7n with the PLONK builder),
+// without decomposing the index in bits.
+package selector
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+)
+
+func init() {
+	hint.Register(MuxIndicators)
+	hint.Register(MapIndicators)
+}
+
+// Mux returns inputs[sel]. It fails if sel isn't in [0, len(inputs)).
+func Mux(api frontend.API, sel frontend.Variable, inputs ...frontend.Variable) frontend.Variable {
+	return dotProduct(api, muxIndicators(api, sel, len(inputs)), inputs)
+}
+
+// Map returns values[i], keys[i] being equal to key. It fails if key isn't in keys. The keys
+// should be distinct: otherwise, the prover may choose any of the values of the key.
+func Map(api frontend.API, key frontend.Variable, keys, values []frontend.Variable) frontend.Variable {
+	if len(keys) != len(values) {
+		panic("keys and values must have the same length")
+	}
+	inputs := append([]frontend.Variable{key}, keys...)
+	indicators, err := api.Compiler().NewHint(MapIndicators, len(keys), inputs...)
+	if err != nil {
+		panic(err)
+	}
+	assertIndicators(api, indicators, func(i int) frontend.Variable { return api.Sub(key, keys[i]) })
+	return dotProduct(api, indicators, values)
+}
+
+// Read returns arr[index]. It fails if index isn't in [0, len(arr)).
+func Read(api frontend.API, arr []frontend.Variable, index frontend.Variable) frontend.Variable {
+	return Mux(api, index, arr...)
+}
+
+// Write returns a copy of arr, with arr[index] set to v. It fails if index isn't in
+// [0, len(arr)).
+func Write(api frontend.API, arr []frontend.Variable, index, v frontend.Variable) []frontend.Variable {
+	indicators := muxIndicators(api, index, len(arr))
+	res := make([]frontend.Variable, len(arr))
+	for i := range arr {
+		// arr[i] + b_i * (v - arr[i])
+		res[i] = api.Add(arr[i], api.Mul(indicators[i], api.Sub(v, arr[i])))
+	}
+	return res
+}
+
+// muxIndicators returns the n booleans b_i = (sel == i), constrained in the circuit
+func muxIndicators(api frontend.API, sel frontend.Variable, n int) []frontend.Variable {
+	if n == 0 {
+		panic("selecting from an empty array")
+	}
+	indicators, err := api.Compiler().NewHint(MuxIndicators, n, sel)
+	if err != nil {
+		panic(err)
+	}
+	assertIndicators(api, indicators, func(i int) frontend.Variable { return api.Sub(sel, i) })
+	return indicators
+}
+
+// assertIndicators checks that the b_i are booleans, that b_i * diff(i) == 0, and that exactly
+// one b_i is 1
+func assertIndicators(api frontend.API, indicators []frontend.Variable, diff func(i int) frontend.Variable) {
+	sum := frontend.Variable(0)
+	for i := range indicators {
+		api.AssertIsBoolean(indicators[i])
+		api.AssertIsEqual(api.Mul(indicators[i], diff(i)), 0)
+		sum = api.Add(sum, indicators[i])
+	}
+	api.AssertIsEqual(sum, 1)
+}
+
+// dotProduct returns Σ a_i * b_i
+func dotProduct(api frontend.API, a, b []frontend.Variable) frontend.Variable {
+	res := frontend.Variable(0)
+	for i := range a {
+		res = api.Add(res, api.Mul(a[i], b[i]))
+	}
+	return res
+}
+
+// MuxIndicators sets results[i] to 1 if inputs[0] == i, and to 0 otherwise
+func MuxIndicators(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	for i := range results {
+		if inputs[0].IsUint64() && inputs[0].Uint64() == uint64(i) {
+			results[i].SetUint64(1)
+		} else {
+			results[i].SetUint64(0)
+		}
+	}
+	return nil
+}
+
+// MapIndicators sets results[i] to 1 if inputs[0] == inputs[i+1] and inputs[0] differs from
+// the previous keys inputs[1], ..., inputs[i], and to 0 otherwise
+func MapIndicators(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	found := false
+	for i := range results {
+		if !found && inputs[0].Cmp(inputs[i+1]) == 0 {
+			results[i].SetUint64(1)
+			found = true
+		} else {
+			results[i].SetUint64(0)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package selector
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const size = 5
+
+type muxCircuit struct {
+	Sel    frontend.Variable
+	Inputs [size]frontend.Variable
+	Out    frontend.Variable
+}
+
+func (circuit *muxCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Mux(api, circuit.Sel, circuit.Inputs[:]...), circuit.Out)
+	return nil
+}
+
+type mapCircuit struct {
+	Key    frontend.Variable
+	Keys   [size]frontend.Variable
+	Values [size]frontend.Variable
+	Out    frontend.Variable
+}
+
+func (circuit *mapCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(Map(api, circuit.Key, circuit.Keys[:], circuit.Values[:]), circuit.Out)
+	return nil
+}
+
+type writeCircuit struct {
+	Index    frontend.Variable
+	Value    frontend.Variable
+	Arr      [size]frontend.Variable
+	Expected [size]frontend.Variable
+}
+
+func (circuit *writeCircuit) Define(api frontend.API) error {
+	res := Write(api, circuit.Arr[:], circuit.Index, circuit.Value)
+	for i := range res {
+		api.AssertIsEqual(res[i], circuit.Expected[i])
+	}
+	api.AssertIsEqual(Read(api, res, circuit.Index), circuit.Value)
+	return nil
+}
+
+type partitionCircuit struct {
+	rightSide bool
+	Pivot     frontend.Variable
+	Input     [size]frontend.Variable
+	Expected  [size]frontend.Variable
+}
+
+func (circuit *partitionCircuit) Define(api frontend.API) error {
+	res := Partition(api, circuit.Pivot, circuit.rightSide, circuit.Input[:])
+	for i := range res {
+		api.AssertIsEqual(res[i], circuit.Expected[i])
+	}
+	return nil
+}
+
+type sliceCircuit struct {
+	Start, End frontend.Variable
+	Input      [size]frontend.Variable
+	Expected   [size]frontend.Variable
+}
+
+func (circuit *sliceCircuit) Define(api frontend.API) error {
+	res := Slice(api, circuit.Start, circuit.End, circuit.Input[:])
+	for i := range res {
+		api.AssertIsEqual(res[i], circuit.Expected[i])
+	}
+	return nil
+}
+
+var input = [size]frontend.Variable{10, 11, 12, 13, 14}
+
+func TestMux(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.ProverSucceeded(&muxCircuit{}, &muxCircuit{Sel: 3, Inputs: input, Out: 13}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&muxCircuit{}, &muxCircuit{Sel: 0, Inputs: input, Out: 10}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&muxCircuit{}, &muxCircuit{Sel: 3, Inputs: input, Out: 12}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&muxCircuit{}, &muxCircuit{Sel: size, Inputs: input, Out: 0}, test.WithCurves(ecc.BN254))
+}
+
+func TestMap(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	keys := [size]frontend.Variable{7, 100, 3, 42, 8}
+	assert.ProverSucceeded(&mapCircuit{}, &mapCircuit{Key: 42, Keys: keys, Values: input, Out: 13}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&mapCircuit{}, &mapCircuit{Key: 42, Keys: keys, Values: input, Out: 12}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&mapCircuit{}, &mapCircuit{Key: 43, Keys: keys, Values: input, Out: 0}, test.WithCurves(ecc.BN254))
+}
+
+func TestWrite(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	expected := [size]frontend.Variable{10, 11, 99, 13, 14}
+	assert.ProverSucceeded(&writeCircuit{}, &writeCircuit{Index: 2, Value: 99, Arr: input, Expected: expected}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&writeCircuit{}, &writeCircuit{Index: 1, Value: 99, Arr: input, Expected: expected}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&writeCircuit{}, &writeCircuit{Index: size, Value: 99, Arr: input, Expected: input}, test.WithCurves(ecc.BN254))
+}
+
+func TestPartition(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	right := [size]frontend.Variable{0, 0, 12, 13, 14}
+	left := [size]frontend.Variable{10, 11, 0, 0, 0}
+	assert.ProverSucceeded(&partitionCircuit{rightSide: true}, &partitionCircuit{Pivot: 2, Input: input, Expected: right}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&partitionCircuit{rightSide: false}, &partitionCircuit{Pivot: 2, Input: input, Expected: left}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&partitionCircuit{rightSide: true}, &partitionCircuit{Pivot: size, Input: input, Expected: [size]frontend.Variable{0, 0, 0, 0, 0}}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&partitionCircuit{rightSide: true}, &partitionCircuit{Pivot: 3, Input: input, Expected: right}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&partitionCircuit{rightSide: true}, &partitionCircuit{Pivot: size + 1, Input: input, Expected: [size]frontend.Variable{0, 0, 0, 0, 0}}, test.WithCurves(ecc.BN254))
+}
+
+func TestSlice(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.ProverSucceeded(&sliceCircuit{}, &sliceCircuit{Start: 1, End: 4, Input: input, Expected: [size]frontend.Variable{0, 11, 12, 13, 0}}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&sliceCircuit{}, &sliceCircuit{Start: 0, End: size, Input: input, Expected: input}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&sliceCircuit{}, &sliceCircuit{Start: 3, End: 1, Input: input, Expected: [size]frontend.Variable{0, 0, 0, 0, 0}}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&sliceCircuit{}, &sliceCircuit{Start: 1, End: 4, Input: input, Expected: [size]frontend.Variable{0, 11, 12, 13, 14}}, test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package selector
+
+import "github.com/consensys/gnark/frontend"
+
+// Partition returns a copy of input, where the elements on one side of pivot are set to 0: if
+// rightSide is true, the elements input[i] with i >= pivot are kept, and otherwise, those with
+// i < pivot. It fails if pivot isn't in [0, len(input)].
+func Partition(api frontend.API, pivot frontend.Variable, rightSide bool, input []frontend.Variable) []frontend.Variable {
+	step := stepMask(api, pivot, len(input))
+	res := make([]frontend.Variable, len(input))
+	for i := range input {
+		if rightSide {
+			res[i] = api.Mul(step[i], input[i])
+		} else {
+			res[i] = api.Sub(input[i], api.Mul(step[i], input[i]))
+		}
+	}
+	return res
+}
+
+// Slice returns a copy of input, where the elements input[i] with i < start or i >= end are set
+// to 0. The result is all zeros if start >= end. It fails if start or end isn't in
+// [0, len(input)].
+func Slice(api frontend.API, start, end frontend.Variable, input []frontend.Variable) []frontend.Variable {
+	afterStart := stepMask(api, start, len(input))
+	afterEnd := stepMask(api, end, len(input))
+	res := make([]frontend.Variable, len(input))
+	for i := range input {
+		// keep input[i] if start <= i < end
+		keep := api.Sub(afterStart[i], api.Mul(afterStart[i], afterEnd[i]))
+		res[i] = api.Mul(keep, input[i])
+	}
+	return res
+}
+
+// stepMask returns the n booleans (i >= pivot), for a pivot in [0, n]. It constrains the
+// indicators e_j = (pivot == j) for j in [0, n], whose prefix sums are the mask.
+func stepMask(api frontend.API, pivot frontend.Variable, n int) []frontend.Variable {
+	indicators, err := api.Compiler().NewHint(MuxIndicators, n+1, pivot)
+	if err != nil {
+		panic(err)
+	}
+	assertIndicators(api, indicators, func(j int) frontend.Variable { return api.Sub(pivot, j) })
+	res := make([]frontend.Variable, n)
+	sum := frontend.Variable(0)
+	for i := range res {
+		sum = api.Add(sum, indicators[i])
+		res[i] = sum
+	}
+	return res
+}
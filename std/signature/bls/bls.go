@@ -0,0 +1,121 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package bls provides ZKP-circuit functions to verify BLS signatures over BLS12-377.
+//
+// Signatures are in G1 and public keys in G2: a signature of msg is σ = [sk]H(msg), with H
+// hashing to G1, and it is verified by checking e(σ, g₂) = e(H(msg), pk). The circuits must
+// be defined over BW6-761, whose scalar field is the base field of BLS12-377.
+//
+// The signatures are checked to be on the curve, but not in G1: a signature may be shifted
+// by a point of small order without being rejected, which doesn't allow to forge signatures.
+// The public keys are not checked, and must be valid points of G2 (public keys of a light
+// client are typically public inputs, checked out of the circuit).
+package bls
+
+import (
+	"errors"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+)
+
+var errMessageNotHashable = errors.New("the message can't be mapped to G1")
+
+// PublicKey stores a BLS public key (to be used in gnark circuit)
+type PublicKey struct {
+	Q sw_bls12377.G2Affine
+}
+
+// Signature stores a BLS signature (to be used in gnark circuit)
+type Signature struct {
+	S sw_bls12377.G1Affine
+}
+
+// Assign is a helper to assign a public key
+func (pk *PublicKey) Assign(q *bls12377.G2Affine) {
+	pk.Q.Assign(q)
+}
+
+// Assign is a helper to assign a signature
+func (sig *Signature) Assign(s *bls12377.G1Affine) {
+	sig.S.Assign(s)
+}
+
+// Verify verifies the signature sig of msg with the public key pk
+func Verify(api frontend.API, pk PublicKey, msg frontend.Variable, sig Signature) error {
+	return VerifyMulti(api, []PublicKey{pk}, []frontend.Variable{msg}, sig)
+}
+
+// VerifyAggregate verifies the aggregated signature sig of msg by the public keys pks, that is
+// the sum of their signatures of msg.
+//
+// The public keys are aggregated with incomplete addition formulas, so they must be distinct
+// and none of them may be the opposite of the sum of the previous ones. As for any aggregation
+// of signatures of the same message, the public keys must come with a proof of possession of
+// their secret keys (to prevent rogue key attacks).
+func VerifyAggregate(api frontend.API, pks []PublicKey, msg frontend.Variable, sig Signature) error {
+	if len(pks) == 0 {
+		return errors.New("no public key")
+	}
+	apk := pks[0].Q
+	for i := 1; i < len(pks); i++ {
+		apk.AddAssign(api, pks[i].Q)
+	}
+	return Verify(api, PublicKey{Q: apk}, msg, sig)
+}
+
+// VerifyMulti verifies the aggregated signature sig of msgs[i] by pks[i], that is the sum of
+// their signatures, by checking e(sig, g₂) = ∏ e(H(msgs[i]), pks[i]) with a single final
+// exponentiation.
+func VerifyMulti(api frontend.API, pks []PublicKey, msgs []frontend.Variable, sig Signature) error {
+	if len(pks) == 0 || len(pks) != len(msgs) {
+		return errors.New("invalid inputs sizes")
+	}
+
+	// σ is on the curve: y² = x³ + 1
+	api.AssertIsEqual(api.Mul(sig.S.Y, sig.S.Y), api.Add(api.Mul(sig.S.X, sig.S.X, sig.S.X), 1))
+
+	P := []sw_bls12377.G1Affine{sig.S}
+	Q := []sw_bls12377.G2Affine{g2Neg()}
+	for i := range msgs {
+		h, err := HashToG1(api, msgs[i])
+		if err != nil {
+			return err
+		}
+		P = append(P, h)
+		Q = append(Q, pks[i].Q)
+	}
+
+	// e(σ, -g₂) ∏ e(H(msgs[i]), pks[i]) == 1
+	res, err := sw_bls12377.Pair(api, P, Q)
+	if err != nil {
+		return err
+	}
+	var one sw_bls12377.GT
+	one.SetOne()
+	res.AssertIsEqual(api, one)
+	return nil
+}
+
+// g2Neg returns the constant -g₂
+func g2Neg() sw_bls12377.G2Affine {
+	_, _, _, g2 := bls12377.Generators()
+	g2.Neg(&g2)
+	var res sw_bls12377.G2Affine
+	res.Assign(&g2)
+	return res
+}
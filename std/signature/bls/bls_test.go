@@ -0,0 +1,198 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bls
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	"github.com/consensys/gnark/test"
+)
+
+func randomMessage(t *testing.T) fr.Element {
+	var msg fr.Element
+	if _, err := msg.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestHashToCurve(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	msg := randomMessage(t)
+	h1, err := HashToCurve(&msg)
+	assert.NoError(err)
+	assert.True(h1.IsOnCurve() && h1.IsInSubGroup())
+
+	h2, err := HashToCurve(&msg)
+	assert.NoError(err)
+	assert.True(h1.Equal(&h2))
+
+	other := randomMessage(t)
+	h2, err = HashToCurve(&other)
+	assert.NoError(err)
+	assert.False(h1.Equal(&h2))
+}
+
+type hashToG1Circuit struct {
+	Msg frontend.Variable
+	H   sw_bls12377.G1Affine
+}
+
+func (circuit *hashToG1Circuit) Define(api frontend.API) error {
+	h, err := HashToG1(api, circuit.Msg)
+	if err != nil {
+		return err
+	}
+	h.AssertIsEqual(api, circuit.H)
+	return nil
+}
+
+func TestHashToG1(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	msg := randomMessage(t)
+	h, err := HashToCurve(&msg)
+	assert.NoError(err)
+
+	var witness hashToG1Circuit
+	witness.Msg = msg
+	witness.H.Assign(&h)
+	assert.SolvingSucceeded(&hashToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// -H(msg) is not the hash of msg
+	h.Neg(&h)
+	witness.H.Assign(&h)
+	assert.SolvingFailed(&hashToG1Circuit{}, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+}
+
+type verifyCircuit struct {
+	PublicKey PublicKey         `gnark:",public"`
+	Msg       frontend.Variable `gnark:",public"`
+	Signature Signature
+}
+
+func (circuit *verifyCircuit) Define(api frontend.API) error {
+	return Verify(api, circuit.PublicKey, circuit.Msg, circuit.Signature)
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	sk, err := GenerateKey(rand.Reader)
+	assert.NoError(err)
+	msg := randomMessage(t)
+	sig, err := sk.Sign(&msg)
+	assert.NoError(err)
+
+	var witness verifyCircuit
+	pk := sk.PublicKey()
+	witness.PublicKey.Assign(&pk)
+	witness.Msg = msg
+	witness.Signature.Assign(&sig)
+	assert.SolvingSucceeded(&verifyCircuit{}, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// wrong message
+	witness.Msg = randomMessage(t)
+	assert.SolvingFailed(&verifyCircuit{}, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// wrong key
+	other, err := GenerateKey(rand.Reader)
+	assert.NoError(err)
+	pk = other.PublicKey()
+	witness.Msg = msg
+	witness.PublicKey.Assign(&pk)
+	assert.SolvingFailed(&verifyCircuit{}, &witness, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+}
+
+const nbSigners = 3
+
+type verifyAggregateCircuit struct {
+	PublicKeys [nbSigners]PublicKey `gnark:",public"`
+	Msg        frontend.Variable    `gnark:",public"`
+	Signature  Signature
+}
+
+func (circuit *verifyAggregateCircuit) Define(api frontend.API) error {
+	return VerifyAggregate(api, circuit.PublicKeys[:], circuit.Msg, circuit.Signature)
+}
+
+type verifyMultiCircuit struct {
+	PublicKeys [nbSigners]PublicKey         `gnark:",public"`
+	Msgs       [nbSigners]frontend.Variable `gnark:",public"`
+	Signature  Signature
+}
+
+func (circuit *verifyMultiCircuit) Define(api frontend.API) error {
+	return VerifyMulti(api, circuit.PublicKeys[:], circuit.Msgs[:], circuit.Signature)
+}
+
+func TestVerifyAggregated(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var sks [nbSigners]*SecretKey
+	for i := range sks {
+		var err error
+		sks[i], err = GenerateKey(rand.Reader)
+		assert.NoError(err)
+	}
+
+	// same message
+	msg := randomMessage(t)
+	var aggregate verifyAggregateCircuit
+	sigs := make([]bls12377.G1Affine, nbSigners)
+	for i := range sks {
+		var err error
+		sigs[i], err = sks[i].Sign(&msg)
+		assert.NoError(err)
+		pk := sks[i].PublicKey()
+		aggregate.PublicKeys[i].Assign(&pk)
+	}
+	aggregate.Msg = msg
+	sig := Aggregate(sigs)
+	aggregate.Signature.Assign(&sig)
+	assert.SolvingSucceeded(&verifyAggregateCircuit{}, &aggregate, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// a missing signature
+	sig = Aggregate(sigs[1:])
+	aggregate.Signature.Assign(&sig)
+	assert.SolvingFailed(&verifyAggregateCircuit{}, &aggregate, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// different messages
+	var multi verifyMultiCircuit
+	for i := range sks {
+		msg := randomMessage(t)
+		var err error
+		sigs[i], err = sks[i].Sign(&msg)
+		assert.NoError(err)
+		multi.PublicKeys[i] = aggregate.PublicKeys[i]
+		multi.Msgs[i] = msg
+	}
+	sig = Aggregate(sigs)
+	multi.Signature.Assign(&sig)
+	assert.SolvingSucceeded(&verifyMultiCircuit{}, &multi, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+
+	// swapped messages
+	multi.Msgs[0], multi.Msgs[1] = multi.Msgs[1], multi.Msgs[0]
+	assert.SolvingFailed(&verifyMultiCircuit{}, &multi, test.WithCurves(ecc.BW6_761), test.WithBackends(backend.GROTH16))
+}
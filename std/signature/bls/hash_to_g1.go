@@ -0,0 +1,174 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bls
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	frbw6 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// maxTries is the number of candidates for the abscissa of the point a message is mapped to
+const maxTries = 64
+
+// xGen is the seed x₀ of BLS12-377
+const xGen uint64 = 9586122913090633729
+
+var (
+	// nonResidue is the smallest non square of Fp
+	nonResidue fp.Element
+	// halfP is (p-1)/2
+	halfP *big.Int
+)
+
+func init() {
+	for nonResidue.SetUint64(2); nonResidue.Legendre() != -1; {
+		var one fp.Element
+		one.SetOne()
+		nonResidue.Add(&nonResidue, &one)
+	}
+	halfP = fp.Modulus()
+	halfP.Rsh(halfP, 1)
+
+	hint.Register(SquareRoot)
+}
+
+// SquareRoot returns 1 and the square root of a which is at most (p-1)/2 if a is a square in
+// Fp, and 0 and a square root of nonResidue*a otherwise.
+func SquareRoot(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	var a, r fp.Element
+	a.SetBigInt(inputs[0])
+	if a.Legendre() == -1 {
+		results[0].SetUint64(0)
+		a.Mul(&a, &nonResidue)
+	} else {
+		results[0].SetUint64(1)
+	}
+	r.Sqrt(&a)
+	r.ToBigIntRegular(results[1])
+	if results[1].Cmp(halfP) > 0 {
+		results[1].Sub(fp.Modulus(), results[1])
+	}
+	return nil
+}
+
+// HashToG1 maps msg to a point of G1, by try-and-increment: msg is hashed with MiMC to h, and
+// the point is the first (x, y) on the curve with x = h + i for i < 64, and y the square root
+// of x³ + 1 which is at most (p-1)/2. The cofactor is then cleared by multiplying the point by
+// 1 - x₀, x₀ being the seed of BLS12-377.
+//
+// The circuit must be defined over BW6-761, whose scalar field is the base field of BLS12-377.
+// The native counterpart, used to sign, is HashToCurve.
+func HashToG1(api frontend.API, msg frontend.Variable) (sw_bls12377.G1Affine, error) {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return sw_bls12377.G1Affine{}, err
+	}
+	h.Write(msg)
+	x0 := h.Sum()
+
+	// P = (x, y) for the first x = x0 + i such that x³ + 1 is a square
+	var P sw_bls12377.G1Affine
+	P.X, P.Y = 0, 0
+	var notFound frontend.Variable = 1
+	for i := 0; i < maxTries; i++ {
+		x := api.Add(x0, i)
+		a := api.Add(api.Mul(x, x, x), 1)
+		res, err := api.Compiler().NewHint(SquareRoot, 2, a)
+		if err != nil {
+			return sw_bls12377.G1Affine{}, err
+		}
+		isSquare, y := res[0], res[1]
+		api.AssertIsBoolean(isSquare)
+		// y² = a if a is a square, nonResidue*a otherwise
+		api.AssertIsEqual(api.Mul(y, y), api.Mul(a, api.Select(isSquare, 1, nonResidue)))
+
+		found := api.Mul(notFound, isSquare)
+		P.X = api.Add(P.X, api.Mul(found, x))
+		P.Y = api.Add(P.Y, api.Mul(found, y))
+		notFound = api.Sub(notFound, found)
+	}
+	api.AssertIsEqual(notFound, 0)
+	api.AssertIsLessOrEqual(P.Y, halfP)
+
+	// [1 - x₀]P
+	var xP sw_bls12377.G1Affine
+	xP = P
+	for i := bits.Len64(xGen) - 2; i >= 0; i-- {
+		if (xGen>>i)&1 == 1 {
+			xP.DoubleAndAdd(api, &xP, &P)
+		} else {
+			xP.Double(api, xP)
+		}
+	}
+	xP.Neg(api, xP)
+	xP.AddAssign(api, P)
+
+	return xP, nil
+}
+
+// HashToCurve maps msg to a point of G1, as HashToG1 does in a circuit. msg is an element of
+// the scalar field of BW6-761, which is the base field of BLS12-377.
+func HashToCurve(msg *frbw6.Element) (bls12377.G1Affine, error) {
+	h := mimcHash(msg)
+
+	var one, x, a fp.Element
+	one.SetOne()
+	for i := 0; i < maxTries; i++ {
+		x = h
+		h.Add(&h, &one)
+
+		a.Square(&x).Mul(&a, &x).Add(&a, &one)
+		if a.Legendre() == -1 {
+			continue
+		}
+		var P bls12377.G1Affine
+		P.X = x
+		P.Y.Sqrt(&a)
+		var y big.Int
+		if P.Y.ToBigIntRegular(&y).Cmp(halfP) > 0 {
+			P.Y.Neg(&P.Y)
+		}
+
+		// [1 - x₀]P, by double and add as the endomorphisms of the curve only act as scalar
+		// multiplications on the r-torsion
+		var xP, res bls12377.G1Jac
+		xP.FromAffine(&P)
+		for i := bits.Len64(xGen) - 2; i >= 0; i-- {
+			xP.DoubleAssign()
+			if (xGen>>i)&1 == 1 {
+				xP.AddMixed(&P)
+			}
+		}
+		res.Neg(&xP).AddMixed(&P)
+
+		var Q bls12377.G1Affine
+		Q.FromJacobian(&res)
+		if Q.IsInfinity() {
+			return Q, errMessageNotHashable
+		}
+		return Q, nil
+	}
+	return bls12377.G1Affine{}, errMessageNotHashable
+}
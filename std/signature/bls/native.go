@@ -0,0 +1,89 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bls
+
+import (
+	"io"
+	"math/big"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	frbw6 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
+)
+
+// SecretKey is a native BLS secret key, to produce the signatures verified in circuits
+type SecretKey struct {
+	scalar big.Int
+	pk     bls12377.G2Affine
+}
+
+// GenerateKey returns a random secret key, read from r
+func GenerateKey(r io.Reader) (*SecretKey, error) {
+	var s fr.Element
+	for s.IsZero() {
+		var b [fr.Bytes]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		s.SetBytes(b[:])
+	}
+
+	sk := new(SecretKey)
+	s.ToBigIntRegular(&sk.scalar)
+	_, _, _, g2 := bls12377.Generators()
+	sk.pk.ScalarMultiplication(&g2, &sk.scalar)
+	return sk, nil
+}
+
+// PublicKey returns the public key [sk]g₂
+func (sk *SecretKey) PublicKey() bls12377.G2Affine {
+	return sk.pk
+}
+
+// Sign returns the signature [sk]H(msg) of msg, H being HashToCurve
+func (sk *SecretKey) Sign(msg *frbw6.Element) (bls12377.G1Affine, error) {
+	h, err := HashToCurve(msg)
+	if err != nil {
+		return bls12377.G1Affine{}, err
+	}
+	var sig bls12377.G1Affine
+	sig.ScalarMultiplication(&h, &sk.scalar)
+	return sig, nil
+}
+
+// Aggregate returns the sum of the signatures sigs, verified in circuits by VerifyAggregate
+// or VerifyMulti
+func Aggregate(sigs []bls12377.G1Affine) bls12377.G1Affine {
+	var res bls12377.G1Jac
+	for i := range sigs {
+		res.AddMixed(&sigs[i])
+	}
+	var sig bls12377.G1Affine
+	sig.FromJacobian(&res)
+	return sig
+}
+
+// mimcHash returns the MiMC hash of msg, as std/hash/mimc computes it in a BW6-761 circuit
+func mimcHash(msg *frbw6.Element) fp.Element {
+	h := mimc.NewMiMC()
+	b := msg.Bytes()
+	_, _ = h.Write(b[:])
+	var res fp.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
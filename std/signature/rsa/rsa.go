@@ -0,0 +1,133 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package rsa provides a ZKP-circuit function to verify RSA signatures (RSASSA-PKCS1-v1_5).
+package rsa
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bigint"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// PublicKey stores an RSA public key (to be used in gnark circuit)
+//
+// The public exponent E is a constant of the circuit, and is not part of the witness.
+// The size of the modulus must be a multiple of bigint.LimbBits bits (RSA-2048, RSA-3072,
+// RSA-4096, ...).
+type PublicKey struct {
+	N bigint.Int
+	E int `gnark:"-"`
+}
+
+// Signature stores an RSA signature (to be used in gnark circuit)
+type Signature struct {
+	S bigint.Int
+}
+
+// NewPublicKey returns a PublicKey with a modulus of nbBits bits and public exponent e, to be
+// used in circuit definitions
+func NewPublicKey(nbBits, e int) PublicKey {
+	return PublicKey{N: bigint.Placeholder(nbBits), E: e}
+}
+
+// NewSignature returns a Signature for a modulus of nbBits bits, to be used in circuit
+// definitions
+func NewSignature(nbBits int) Signature {
+	return Signature{S: bigint.Placeholder(nbBits)}
+}
+
+// Assign is a helper to assign a crypto/rsa public key
+func (pk *PublicKey) Assign(key *rsa.PublicKey) {
+	pk.N = bigint.ValueOf(key.N, 8*key.Size())
+	pk.E = key.E
+}
+
+// Assign is a helper to assign a signature, as returned by rsa.SignPKCS1v15
+func (s *Signature) Assign(sig []byte) {
+	s.S = bigint.ValueOf(new(big.Int).SetBytes(sig), 8*len(sig))
+}
+
+// hashPrefixes are the DER encodings of the DigestInfo of the hash functions, without the
+// digest (see crypto/rsa)
+var hashPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA224: {0x30, 0x2d, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x04, 0x05, 0x00, 0x04, 0x1c},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// Verify verifies an RSASSA-PKCS1-v1_5 signature of the digest hashed, computed with the hash
+// function hash, as rsa.VerifyPKCS1v15 does. hashed holds the bytes of the digest.
+func Verify(api frontend.API, pk PublicKey, hash crypto.Hash, hashed []frontend.Variable, sig Signature) error {
+	prefix, ok := hashPrefixes[hash]
+	if !ok {
+		return errors.New("unsupported hash function")
+	}
+	if len(hashed) != hash.Size() {
+		return errors.New("wrong digest length")
+	}
+	if pk.E < 2 {
+		return errors.New("invalid public exponent")
+	}
+	// EM = 0x00 || 0x01 || PS || 0x00 || T, with T = prefix || hashed and PS at least 8 bytes 0xff
+	k := len(pk.N.Limbs) * bigint.LimbBits / 8
+	tLen := len(prefix) + len(hashed)
+	if k < tLen+11 {
+		return errors.New("modulus too short")
+	}
+
+	bigint.AssertIsValid(api, pk.N)
+	bigint.AssertIsValid(api, sig.S)
+	bigint.AssertIsLess(api, sig.S, pk.N)
+	m := bigint.ExpMod(api, sig.S, pk.E, pk.N)
+
+	// the constant bytes of EM, most significant first
+	em := make([]byte, k-len(hashed))
+	em[1] = 1
+	for i := 2; i < k-tLen-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[k-tLen:], prefix)
+
+	limbs := make([]frontend.Variable, len(pk.N.Limbs))
+	for i := range limbs {
+		limbs[i] = 0
+	}
+	weight := func(j int) (int, *big.Int) {
+		p := k - 1 - j // byte j from the most significant one has weight 2^(8p)
+		return p * 8 / bigint.LimbBits, new(big.Int).Lsh(big.NewInt(1), uint(p*8%bigint.LimbBits))
+	}
+	for j, b := range em {
+		if b != 0 {
+			l, w := weight(j)
+			limbs[l] = api.Add(limbs[l], w.Mul(w, big.NewInt(int64(b))))
+		}
+	}
+	for j := range hashed {
+		bits.ToBinary(api, hashed[j], bits.WithNbDigits(8))
+		l, w := weight(len(em) + j)
+		limbs[l] = api.Add(limbs[l], api.Mul(hashed[j], w))
+	}
+
+	bigint.AssertIsEqual(api, m, bigint.Int{Limbs: limbs})
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package rsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type rsaCircuit struct {
+	PublicKey PublicKey                      `gnark:",public"`
+	Hashed    [sha256.Size]frontend.Variable `gnark:",public"`
+	Signature Signature
+}
+
+func (circuit *rsaCircuit) Define(api frontend.API) error {
+	return Verify(api, circuit.PublicKey, crypto.SHA256, circuit.Hashed[:], circuit.Signature)
+}
+
+func newRSACircuit(nbBits int) *rsaCircuit {
+	return &rsaCircuit{
+		PublicKey: NewPublicKey(nbBits, 65537),
+		Signature: NewSignature(nbBits),
+	}
+}
+
+func rsaAssignment(t *testing.T, key *rsa.PrivateKey, msg []byte) *rsaCircuit {
+	hashed := sha256.Sum256(msg)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res rsaCircuit
+	res.PublicKey.Assign(&key.PublicKey)
+	res.Signature.Assign(sig)
+	for i := range hashed {
+		res.Hashed[i] = hashed[i]
+	}
+	return &res
+}
+
+func TestRSA2048(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+
+	valid := rsaAssignment(t, key, []byte("legacy identity document"))
+	assert.NoError(test.IsSolved(newRSACircuit(2048), valid, ecc.BN254, backend.UNKNOWN))
+
+	// signature of another message
+	invalid := rsaAssignment(t, key, []byte("forged identity document"))
+	invalid.Hashed = valid.Hashed
+	assert.Error(test.IsSolved(newRSACircuit(2048), invalid, ecc.BN254, backend.UNKNOWN))
+
+	// signature with another key
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	invalid = rsaAssignment(t, other, []byte("legacy identity document"))
+	invalid.PublicKey = valid.PublicKey
+	assert.Error(test.IsSolved(newRSACircuit(2048), invalid, ecc.BN254, backend.UNKNOWN))
+}
+
+func TestRSAProver(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping RSA prover test in short mode")
+	}
+	assert := test.NewAssert(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(err)
+
+	valid := rsaAssignment(t, key, []byte("legacy identity document"))
+	assert.ProverSucceeded(newRSACircuit(1024), valid, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+}
@@ -0,0 +1,196 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package schnorr
+
+import (
+	"crypto/rand"
+	"hash"
+	"io"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// NativeSignature is a Schnorr signature (R, s), out of circuits
+type NativeSignature struct {
+	R twistededwards.NativePoint
+	S *big.Int
+}
+
+// NativeCompactSignature is a compact Schnorr signature (R.Y, s), out of circuits
+type NativeCompactSignature struct {
+	R, S *big.Int
+}
+
+// SecretKey is a Schnorr secret key, to sign messages out of circuits
+type SecretKey struct {
+	curve     *nativeCurve
+	Scalar    *big.Int
+	PublicKey twistededwards.NativePoint
+}
+
+// GenerateKey returns a random secret key on the curve id, reading randomness from r (if r is
+// nil, crypto/rand.Reader is used)
+func GenerateKey(id tedwards.ID, r io.Reader) (*SecretKey, error) {
+	curve, err := newNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	scalar, err := curve.randomScalar(r)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretKey{
+		curve:     curve,
+		Scalar:    scalar,
+		PublicKey: curve.ScalarMul(curve.Base(), scalar),
+	}, nil
+}
+
+// CompactPublicKey returns the compact public key, the y coordinate of the public key
+func (sk *SecretKey) CompactPublicKey() *big.Int {
+	return new(big.Int).Set(sk.PublicKey.Y)
+}
+
+// Sign returns the signature of msg, h being the challenge hash. The nonce is read from r (if r
+// is nil, crypto/rand.Reader is used).
+func (sk *SecretKey) Sign(msg *big.Int, h hash.Hash, r io.Reader) (NativeSignature, error) {
+	k, err := sk.curve.randomScalar(r)
+	if err != nil {
+		return NativeSignature{}, err
+	}
+	R := sk.curve.ScalarMul(sk.curve.Base(), k)
+	e := sk.curve.challenge(h, R.X, R.Y, sk.PublicKey.X, sk.PublicKey.Y, msg)
+	return NativeSignature{R: R, S: sk.curve.response(k, e, sk.Scalar)}, nil
+}
+
+// SignCompact returns the compact signature of msg, h being the challenge hash. The nonce is
+// read from r (if r is nil, crypto/rand.Reader is used).
+func (sk *SecretKey) SignCompact(msg *big.Int, h hash.Hash, r io.Reader) (NativeCompactSignature, error) {
+	k, err := sk.curve.randomScalar(r)
+	if err != nil {
+		return NativeCompactSignature{}, err
+	}
+	// the compact forms stand for the points of x <= (p-1)/2: negate the key and nonce otherwise
+	a := sk.curve.positive(sk.Scalar, sk.PublicKey)
+	R := sk.curve.ScalarMul(sk.curve.Base(), k)
+	k = sk.curve.positive(k, R)
+	e := sk.curve.challenge(h, R.Y, sk.PublicKey.Y, msg)
+	return NativeCompactSignature{R: new(big.Int).Set(R.Y), S: sk.curve.response(k, e, a)}, nil
+}
+
+// VerifySignature checks the signature sig of msg by pk on the curve id, h being the challenge
+// hash, as Verify does in a circuit.
+func VerifySignature(id tedwards.ID, sig NativeSignature, msg *big.Int, pk twistededwards.NativePoint, h hash.Hash) (bool, error) {
+	curve, err := newNativeCurve(id)
+	if err != nil {
+		return false, err
+	}
+	if !curve.IsOnCurve(pk) || !curve.IsOnCurve(sig.R) || !curve.isScalar(sig.S) {
+		return false, nil
+	}
+	e := curve.challenge(h, sig.R.X, sig.R.Y, pk.X, pk.Y, msg)
+
+	// [c]([s]G - [e]A - R) == 0
+	Q := curve.Add(curve.ScalarMul(curve.Base(), sig.S), curve.ScalarMul(pk, new(big.Int).Neg(e)))
+	Q = curve.Add(Q, curve.Neg(sig.R))
+	Q = curve.ScalarMul(Q, curve.Params().Cofactor)
+	return curve.Equal(Q, curve.Identity()), nil
+}
+
+// VerifyCompactSignature checks the compact signature sig of msg by the compact key pk on the
+// curve id, h being the challenge hash, as VerifyCompact does in a circuit.
+func VerifyCompactSignature(id tedwards.ID, sig NativeCompactSignature, msg *big.Int, pk *big.Int, h hash.Hash) (bool, error) {
+	curve, err := newNativeCurve(id)
+	if err != nil {
+		return false, err
+	}
+	if pk.Sign() < 0 || pk.Cmp(curve.Modulus()) >= 0 || !curve.isScalar(sig.S) {
+		return false, nil
+	}
+	x := curve.RecoverX(pk)
+	if x == nil {
+		return false, nil
+	}
+	e := curve.challenge(h, sig.R, pk, msg)
+
+	// R' = [s]G - [e]A, R'.Y == r and R'.X <= (p-1)/2
+	R := curve.Add(curve.ScalarMul(curve.Base(), sig.S), curve.ScalarMul(twistededwards.NativePoint{X: x, Y: pk}, new(big.Int).Neg(e)))
+	return R.Y.Cmp(sig.R) == 0 && R.X.Cmp(curve.half) <= 0, nil
+}
+
+// nativeCurve adds to a twisted Edwards curve the helpers of the signature scheme
+type nativeCurve struct {
+	*twistededwards.NativeCurve
+	half *big.Int
+}
+
+func newNativeCurve(id tedwards.ID) (*nativeCurve, error) {
+	curve, err := twistededwards.NewNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	half := curve.Modulus()
+	return &nativeCurve{NativeCurve: curve, half: half.Rsh(half, 1)}, nil
+}
+
+// randomScalar returns a random scalar in [1, ℓ-1]
+func (c *nativeCurve) randomScalar(r io.Reader) (*big.Int, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	max := new(big.Int).Sub(c.Params().Order, big.NewInt(1))
+	k, err := rand.Int(r, max)
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// positive returns k if the x coordinate of P = [k]G is at most (p-1)/2, and ℓ-k otherwise
+func (c *nativeCurve) positive(k *big.Int, P twistededwards.NativePoint) *big.Int {
+	if P.X.Cmp(c.half) <= 0 {
+		return new(big.Int).Set(k)
+	}
+	return new(big.Int).Sub(c.Params().Order, k)
+}
+
+// challenge returns H(elements) as a field element, writing each element in big-endian on the
+// byte size of the field
+func (c *nativeCurve) challenge(h hash.Hash, elements ...*big.Int) *big.Int {
+	p := c.Modulus()
+	size := (p.BitLen() + 7) / 8
+	h.Reset()
+	for _, e := range elements {
+		buf := make([]byte, size)
+		new(big.Int).Mod(e, p).FillBytes(buf)
+		h.Write(buf)
+	}
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	return e.Mod(e, p)
+}
+
+// response returns k + e*a mod ℓ
+func (c *nativeCurve) response(k, e, a *big.Int) *big.Int {
+	s := new(big.Int).Mul(e, a)
+	s.Add(s, k)
+	return s.Mod(s, c.Params().Order)
+}
+
+func (c *nativeCurve) isScalar(s *big.Int) bool {
+	return s != nil && s.Sign() >= 0 && s.Cmp(c.Params().Order) < 0
+}
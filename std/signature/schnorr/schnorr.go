@@ -0,0 +1,186 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package schnorr provides ZKP-circuit functions to verify Schnorr signatures over the twisted
+// Edwards curves embedded in the SNARK fields, with a challenge hash chosen by the caller.
+//
+// A signature of msg by the key A = [a]G is (R, s) with R = [k]G and s = k + e·a mod ℓ, where
+// e = H(R.X, R.Y, A.X, A.Y, msg), and ℓ is the order of G.
+//
+// Compact keys and signatures follow BIP340, adapted to twisted Edwards curves: since the
+// opposite of (x, y) is (-x, y), a point is represented by its y coordinate only, and stands
+// for the point whose x is at most (p-1)/2. Signers negate their secret key and nonce when
+// needed, such that A and R are such points, and the challenge is e = H(R.Y, A.Y, msg).
+//
+// The native counterparts (SecretKey, VerifySignature and VerifyCompactSignature) produce and
+// check the same signatures, for hashes such as gnark-crypto's MiMC, which read the field
+// elements in big-endian, on as many bytes as the field elements take.
+package schnorr
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// PublicKey stores a Schnorr public key (to be used in gnark circuit)
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Signature stores a Schnorr signature (to be used in gnark circuit)
+type Signature struct {
+	R twistededwards.Point
+	S frontend.Variable
+}
+
+// CompactPublicKey stores a compact Schnorr public key, the y coordinate of the key
+// (to be used in gnark circuit)
+type CompactPublicKey struct {
+	Y frontend.Variable
+}
+
+// CompactSignature stores a compact Schnorr signature (R.Y, s) (to be used in gnark circuit)
+type CompactSignature struct {
+	R, S frontend.Variable
+}
+
+// Assign is a helper to assign a native public key
+func (pk *PublicKey) Assign(p twistededwards.NativePoint) {
+	pk.A.X, pk.A.Y = p.X, p.Y
+}
+
+// Assign is a helper to assign a native signature
+func (sig *Signature) Assign(s NativeSignature) {
+	sig.R.X, sig.R.Y, sig.S = s.R.X, s.R.Y, s.S
+}
+
+// Assign is a helper to assign a native compact public key
+func (pk *CompactPublicKey) Assign(y *big.Int) {
+	pk.Y = y
+}
+
+// Assign is a helper to assign a native compact signature
+func (sig *CompactSignature) Assign(s NativeCompactSignature) {
+	sig.R, sig.S = s.R, s.S
+}
+
+// Verify verifies the signature sig of msg by pk, h being the challenge hash. It checks that
+// [c]([s]G - [e]A - R) is the neutral element, c being the cofactor of the curve.
+func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pk PublicKey, h hash.Hash) error {
+	api := curve.API()
+	params := curve.Params()
+	if !params.Cofactor.IsUint64() || params.Cofactor.Uint64()&(params.Cofactor.Uint64()-1) != 0 {
+		return errors.New("the cofactor of the curve is not a power of 2")
+	}
+
+	curve.AssertIsOnCurve(pk.A)
+	curve.AssertIsOnCurve(sig.R)
+	assertIsScalar(api, params, sig.S)
+
+	h.Reset()
+	h.Write(sig.R.X, sig.R.Y, pk.A.X, pk.A.Y, msg)
+	e := h.Sum()
+
+	Q := curve.DoubleBaseScalarMul(base(params), curve.Neg(pk.A), sig.S, e)
+	Q = curve.Add(Q, curve.Neg(sig.R))
+	for c := params.Cofactor.Uint64(); c > 1; c >>= 1 {
+		Q = curve.Double(Q)
+	}
+
+	api.AssertIsEqual(Q.X, 0)
+	api.AssertIsEqual(Q.Y, 1)
+	return nil
+}
+
+// BatchVerify verifies the signatures sigs[i] of msgs[i] by pks[i], h being the challenge hash.
+//
+// It is a convenience loop over Verify, and costs as many constraints as len(sigs) calls to
+// Verify. It is not a randomized batch verification: a random linear combination of the
+// verification equations needs its combined scalars reduced mod ℓ, which costs more in a
+// circuit than the scalar multiplications it saves.
+func BatchVerify(curve twistededwards.Curve, sigs []Signature, msgs []frontend.Variable, pks []PublicKey, h hash.Hash) error {
+	if len(pks) != len(msgs) || len(pks) != len(sigs) {
+		return errors.New("invalid inputs sizes")
+	}
+	for i := range sigs {
+		if err := Verify(curve, sigs[i], msgs[i], pks[i], h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyCompact verifies the compact signature sig of msg by the compact key pk, h being the
+// challenge hash. It recovers the key A, and checks that R' = [s]G - [e]A has y coordinate R
+// and x coordinate at most (p-1)/2.
+func VerifyCompact(curve twistededwards.Curve, sig CompactSignature, msg frontend.Variable, pk CompactPublicKey, h hash.Hash) error {
+	api := curve.API()
+	params := curve.Params()
+	halfP := halfModulus(api.Compiler().Curve())
+
+	// A = (x, pk.Y), x <= (p-1)/2
+	x, err := api.Compiler().NewHint(twistededwards.RecoverX, 1, params.A, params.D, pk.Y)
+	if err != nil {
+		return err
+	}
+	A := twistededwards.Point{X: x[0], Y: pk.Y}
+	curve.AssertIsOnCurve(A)
+	api.AssertIsLessOrEqual(A.X, halfP)
+	assertIsScalar(api, params, sig.S)
+
+	h.Reset()
+	h.Write(sig.R, pk.Y, msg)
+	e := h.Sum()
+
+	R := curve.DoubleBaseScalarMul(base(params), curve.Neg(A), sig.S, e)
+	api.AssertIsEqual(R.Y, sig.R)
+	api.AssertIsLessOrEqual(R.X, halfP)
+	return nil
+}
+
+// BatchVerifyCompact verifies the compact signatures sigs[i] of msgs[i] by pks[i].
+//
+// Like BatchVerify, it is a convenience loop over VerifyCompact.
+func BatchVerifyCompact(curve twistededwards.Curve, sigs []CompactSignature, msgs []frontend.Variable, pks []CompactPublicKey, h hash.Hash) error {
+	if len(pks) != len(msgs) || len(pks) != len(sigs) {
+		return errors.New("invalid inputs sizes")
+	}
+	for i := range sigs {
+		if err := VerifyCompact(curve, sigs[i], msgs[i], pks[i], h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assertIsScalar checks that s < ℓ, which makes signatures non malleable
+func assertIsScalar(api frontend.API, params *twistededwards.CurveParams, s frontend.Variable) {
+	api.AssertIsLessOrEqual(s, new(big.Int).Sub(params.Order, big.NewInt(1)))
+}
+
+func base(params *twistededwards.CurveParams) twistededwards.Point {
+	return twistededwards.Point{X: params.Base[0], Y: params.Base[1]}
+}
+
+// halfModulus returns (p-1)/2, p being the modulus of the scalar field of curveID
+func halfModulus(curveID ecc.ID) *big.Int {
+	p := curveID.Info().Fr.Modulus()
+	return p.Rsh(p, 1)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package schnorr
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const nbBatch = 2
+
+type schnorrCircuit struct {
+	curveID   tedwards.ID
+	PublicKey PublicKey         `gnark:",public"`
+	Signature Signature         `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+}
+
+func (circuit *schnorrCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return Verify(curve, circuit.Signature, circuit.Message, circuit.PublicKey, &h)
+}
+
+type compactCircuit struct {
+	curveID   tedwards.ID
+	PublicKey CompactPublicKey  `gnark:",public"`
+	Signature CompactSignature  `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+}
+
+func (circuit *compactCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return VerifyCompact(curve, circuit.Signature, circuit.Message, circuit.PublicKey, &h)
+}
+
+type batchCircuit struct {
+	curveID    tedwards.ID
+	PublicKeys [nbBatch]PublicKey
+	Signatures [nbBatch]Signature
+	Messages   [nbBatch]frontend.Variable
+}
+
+func (circuit *batchCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return BatchVerify(curve, circuit.Signatures[:], circuit.Messages[:], circuit.PublicKeys[:], &h)
+}
+
+func TestSchnorr(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	confs := []struct {
+		hash  hash.Hash
+		curve tedwards.ID
+	}{
+		{hash.MIMC_BN254, tedwards.BN254},
+		{hash.MIMC_BLS12_381, tedwards.BLS12_381},
+		{hash.MIMC_BLS12_377, tedwards.BLS12_377},
+		{hash.MIMC_BW6_761, tedwards.BW6_761},
+		{hash.MIMC_BLS24_315, tedwards.BLS24_315},
+		{hash.MIMC_BW6_633, tedwards.BW6_633},
+	}
+
+	for _, conf := range confs {
+		snarkCurve, err := twistededwards.GetSnarkCurve(conf.curve)
+		assert.NoError(err)
+
+		sk, err := GenerateKey(conf.curve, nil)
+		assert.NoError(err)
+		msg := big.NewInt(42)
+		wrongMsg := big.NewInt(43)
+
+		// signature
+		sig, err := sk.Sign(msg, conf.hash.New(), nil)
+		assert.NoError(err)
+		ok, err := VerifySignature(conf.curve, sig, msg, sk.PublicKey, conf.hash.New())
+		assert.NoError(err)
+		assert.True(ok)
+		ok, err = VerifySignature(conf.curve, sig, wrongMsg, sk.PublicKey, conf.hash.New())
+		assert.NoError(err)
+		assert.False(ok)
+
+		circuit := schnorrCircuit{curveID: conf.curve}
+		var witness schnorrCircuit
+		witness.PublicKey.Assign(sk.PublicKey)
+		witness.Signature.Assign(sig)
+		witness.Message = msg
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+		witness.Message = wrongMsg
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		// compact signature
+		compactSig, err := sk.SignCompact(msg, conf.hash.New(), nil)
+		assert.NoError(err)
+		ok, err = VerifyCompactSignature(conf.curve, compactSig, msg, sk.CompactPublicKey(), conf.hash.New())
+		assert.NoError(err)
+		assert.True(ok)
+		ok, err = VerifyCompactSignature(conf.curve, compactSig, wrongMsg, sk.CompactPublicKey(), conf.hash.New())
+		assert.NoError(err)
+		assert.False(ok)
+
+		compact := compactCircuit{curveID: conf.curve}
+		var compactWitness compactCircuit
+		compactWitness.PublicKey.Assign(sk.CompactPublicKey())
+		compactWitness.Signature.Assign(compactSig)
+		compactWitness.Message = msg
+		assert.SolvingSucceeded(&compact, &compactWitness, test.WithCurves(snarkCurve))
+		compactWitness.Message = wrongMsg
+		assert.SolvingFailed(&compact, &compactWitness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	circuit := batchCircuit{curveID: tedwards.BN254}
+	var witness batchCircuit
+	for i := 0; i < nbBatch; i++ {
+		sk, err := GenerateKey(tedwards.BN254, nil)
+		assert.NoError(err)
+		msg := big.NewInt(int64(i))
+		sig, err := sk.Sign(msg, hash.MIMC_BN254.New(), nil)
+		assert.NoError(err)
+		witness.PublicKeys[i].Assign(sk.PublicKey)
+		witness.Signatures[i].Assign(sig)
+		witness.Messages[i] = msg
+	}
+	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+	witness.Messages[0], witness.Messages[1] = witness.Messages[1], witness.Messages[0]
+	assert.ProverFailed(&circuit, &witness, test.WithCurves(ecc.BN254))
+}